@@ -0,0 +1,10 @@
+-- Newar Insights - Spawn attempt tracking
+-- Date: 2026-10-17
+
+-- =====================================================
+-- MEETINGS: SPAWN ATTEMPTS
+-- =====================================================
+-- Bot spawns are retried with backoff; attempts and the last
+-- error are persisted so stuck recordings can be diagnosed and resumed.
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS spawn_attempts INTEGER DEFAULT 0 NOT NULL;
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS spawn_last_error TEXT;
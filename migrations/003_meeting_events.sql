@@ -0,0 +1,20 @@
+-- Newar Insights - Meeting event log
+-- Date: 2026-10-17
+
+-- =====================================================
+-- MEETING EVENTS TABLE
+-- =====================================================
+-- Append-only history of bot status updates. meetings.status only holds
+-- the latest status; this keeps every step (with chunk counts and errors).
+CREATE TABLE IF NOT EXISTS meeting_events (
+    id BIGSERIAL PRIMARY KEY,
+    meeting_id BIGINT NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL, -- Status reported by the bot (or set by bot-manager)
+    container_id VARCHAR(255), -- Recording session / container that reported it
+    chunk_count INTEGER,
+    error_message TEXT,
+    occurred_at TIMESTAMPTZ NOT NULL, -- When the bot reported the status
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_events_meeting_id ON meeting_events(meeting_id, occurred_at);
@@ -0,0 +1,14 @@
+-- Newar Insights - Scheduled recordings
+-- Date: 2026-10-17
+
+-- =====================================================
+-- MEETINGS: SCHEDULING
+-- =====================================================
+-- Scheduled recordings are created with status 'scheduled' and moved to
+-- 'requested' by the bot-manager scheduler shortly before scheduled_at.
+-- max_duration (seconds) stops the bot after that long in the meeting.
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMPTZ;
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS max_duration INTEGER;
+
+-- Scheduler looks up due meetings by scheduled_at
+CREATE INDEX IF NOT EXISTS idx_meetings_scheduled_at ON meetings(scheduled_at) WHERE status = 'scheduled';
@@ -0,0 +1,38 @@
+-- Newar Insights - Calendar (ICS) import
+-- Date: 2026-10-17
+
+-- =====================================================
+-- CALENDAR FEEDS TABLE
+-- =====================================================
+-- ICS feed URLs that are re-synced periodically into scheduled recordings.
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    bot_name VARCHAR(255), -- Bot name for recordings created from this feed
+    max_duration INTEGER, -- Max duration (seconds) for recordings created from this feed
+    is_active BOOLEAN DEFAULT TRUE NOT NULL,
+    last_synced_at TIMESTAMPTZ,
+    last_sync_error TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_feeds_user_id ON calendar_feeds(user_id);
+
+DROP TRIGGER IF EXISTS update_calendar_feeds_updated_at ON calendar_feeds;
+CREATE TRIGGER update_calendar_feeds_updated_at BEFORE UPDATE ON calendar_feeds
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- =====================================================
+-- MEETINGS: CALENDAR SOURCE
+-- =====================================================
+-- Recordings imported from a calendar remember the event UID and the original
+-- occurrence start, so re-imports skip occurrences that were already created
+-- (even if they were rescheduled or cancelled since).
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS calendar_feed_id BIGINT REFERENCES calendar_feeds(id) ON DELETE SET NULL;
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS ical_uid TEXT;
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS ical_start TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_meetings_ical_occurrence ON meetings(user_id, ical_uid, ical_start) WHERE ical_uid IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_meetings_calendar_feed_id ON meetings(calendar_feed_id) WHERE calendar_feed_id IS NOT NULL;
@@ -0,0 +1,10 @@
+-- Newar Insights - Maximum recording duration
+-- Date: 2026-10-17
+
+-- =====================================================
+-- MEETINGS: AUTOMATIC STOP
+-- =====================================================
+-- bot-manager stops recordings that exceed their max duration (the meeting's
+-- max_duration, else the owner's users.data default_max_duration_seconds) and
+-- notes why in stop_reason, which survives the final status update.
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS stop_reason TEXT;
@@ -0,0 +1,23 @@
+-- Newar Insights - Recording file metadata
+-- Date: 2026-10-17
+
+-- =====================================================
+-- RECORDING FILES TABLE
+-- =====================================================
+-- Files produced by finalization, with the media metadata reported by ffprobe.
+-- meetings.recording_path and recording_duration keep pointing at the primary file.
+CREATE TABLE IF NOT EXISTS recording_files (
+    id BIGSERIAL PRIMARY KEY,
+    meeting_id BIGINT NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    path TEXT NOT NULL, -- Relative to the storage root
+    format VARCHAR(20) NOT NULL, -- Container, e.g. 'webm'
+    codec VARCHAR(50),
+    sample_rate INTEGER, -- Hz
+    channels INTEGER,
+    bitrate BIGINT, -- bits per second
+    size_bytes BIGINT NOT NULL,
+    duration_seconds DOUBLE PRECISION,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_recording_files_meeting_id ON recording_files(meeting_id);
@@ -0,0 +1,8 @@
+-- Newar Insights - Dropped recording chunks
+-- Date: 2026-10-17
+
+-- =====================================================
+-- RECORDING FILES: DROPPED CHUNKS
+-- =====================================================
+-- Indices of chunks that finalization left out (empty, missing a WebM header or unreadable).
+ALTER TABLE recording_files ADD COLUMN IF NOT EXISTS dropped_chunks BIGINT[];
@@ -0,0 +1,10 @@
+-- Newar Insights - Recording output formats
+-- Date: 2026-10-17
+
+-- =====================================================
+-- MEETINGS: OUTPUT FORMATS
+-- =====================================================
+-- Extra renditions (mp3, m4a, wav, ogg) produced next to the webm recording.
+-- NULL falls back to the user's default_output_formats in users.data.
+-- Each rendition is stored as its own row in recording_files.
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS output_formats TEXT[];
@@ -0,0 +1,10 @@
+-- Newar Insights - Recording post-processing
+-- Date: 2026-10-17
+
+-- =====================================================
+-- RECORDING FILES: PROCESSING STEPS
+-- =====================================================
+-- Post-processing filters applied to the file, in order:
+-- [{"name": "loudnorm", "filter": "loudnorm=I=-16:TP=-1.5:LRA=11,...", "params": {...}}]
+-- NULL means the file is the unprocessed concatenation.
+ALTER TABLE recording_files ADD COLUMN IF NOT EXISTS processing JSONB;
@@ -0,0 +1,24 @@
+-- Newar Insights - Finalization job queue
+-- Date: 2026-10-17
+
+-- =====================================================
+-- FINALIZATION JOBS TABLE
+-- =====================================================
+-- State of each meeting's finalization (chunk concatenation, post-processing,
+-- renditions). The jobs themselves are queued on the queue:finalize Redis stream;
+-- this table keeps their outcome so failed finalizations can be inspected and re-run.
+CREATE TABLE IF NOT EXISTS finalization_jobs (
+    meeting_id BIGINT PRIMARY KEY REFERENCES meetings(id) ON DELETE CASCADE,
+    container_id VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'running', 'retrying', 'completed', 'failed')),
+    chunk_count INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    queued_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_finalization_jobs_status ON finalization_jobs(status);
@@ -0,0 +1,14 @@
+-- Newar Insights - Unique recording file paths
+-- Date: 2026-10-17
+
+-- =====================================================
+-- RECORDING FILES: ONE ROW PER STORED FILE
+-- =====================================================
+-- Finalization writes each meeting's files to fixed paths, so a retried attempt
+-- overwrites the previous one's files and updates their rows instead of adding more.
+-- Rows duplicated by earlier retries are dropped, keeping the latest.
+DELETE FROM recording_files a
+USING recording_files b
+WHERE a.meeting_id = b.meeting_id AND a.path = b.path AND a.id < b.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recording_files_meeting_path ON recording_files(meeting_id, path);
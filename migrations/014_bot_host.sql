@@ -0,0 +1,11 @@
+-- Newar Insights - Bot host ownership
+-- Date: 2026-10-17
+
+-- =====================================================
+-- MEETINGS: BOT HOST
+-- =====================================================
+-- Docker host a meeting's bot was spawned on (the daemon ID, or BOT_MANAGER_HOST_ID).
+-- With several bot-manager replicas, each one only reconciles and reaps meetings
+-- whose bots run on its own host. NULL (spawned before this column existed) is
+-- treated as local by every replica.
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS bot_host VARCHAR(255);
@@ -0,0 +1,9 @@
+-- Newar Insights - Persisted stop requests
+-- Date: 2026-10-17
+
+-- =====================================================
+-- MEETINGS: STOP REQUESTS
+-- =====================================================
+-- When the bot was first told to stop. Stored before the stop command is sent,
+-- so the stop grace period can still be enforced after a service restart.
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS stop_requested_at TIMESTAMPTZ;
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/database"
+)
+
+type FinalizationHandler struct {
+	jobRepo       *database.FinalizationJobRepository
+	botManagerURL string
+}
+
+func NewFinalizationHandler(jobRepo *database.FinalizationJobRepository, botManagerURL string) *FinalizationHandler {
+	return &FinalizationHandler{
+		jobRepo:       jobRepo,
+		botManagerURL: botManagerURL,
+	}
+}
+
+// GetFinalization handles GET /admin/recordings/:id/finalization
+func (h *FinalizationHandler) GetFinalization(c *fiber.Ctx) error {
+	recordingID, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid recording ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	job, err := h.jobRepo.GetByMeeting(ctx, int64(recordingID))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": constants.ErrNotFound,
+		})
+	}
+
+	return c.JSON(job)
+}
+
+// RetryFinalization handles POST /admin/recordings/:id/finalize
+// Finalization runs in the bot-manager, which holds the uploaded chunks; the request is forwarded there.
+func (h *FinalizationHandler) RetryFinalization(c *fiber.Ctx) error {
+	recordingID, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid recording ID",
+		})
+	}
+
+	url := fmt.Sprintf("%s/finalizations/%d/retry", h.botManagerURL, recordingID)
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Post(url, "application/json", nil)
+	if err != nil {
+		log.Error().Err(err).Str("url", url).Msg("Failed to reach bot manager")
+		return c.Status(502).JSON(fiber.Map{
+			"error": "Failed to reach bot manager",
+		})
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return c.Status(502).JSON(fiber.Map{
+			"error": "Failed to read bot manager response",
+		})
+	}
+
+	log.Info().
+		Int("recording_id", recordingID).
+		Int("status_code", resp.StatusCode).
+		Msg("Finalization re-run requested")
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(resp.StatusCode).Send(body)
+}
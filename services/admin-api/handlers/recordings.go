@@ -8,16 +8,26 @@ import (
 
 	"github.com/newar/insights/shared/constants"
 	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/events"
 	"github.com/newar/insights/shared/types"
+	"github.com/newar/insights/shared/webhooks"
 )
 
 type RecordingHandler struct {
-	db database.Database
+	db          database.Database
+	meetingRepo *database.MeetingRepository
+	eventRepo   *database.MeetingEventRepository
+	notifier    *webhooks.Notifier
+	publisher   *events.Publisher
 }
 
-func NewRecordingHandler(db database.Database) *RecordingHandler {
+func NewRecordingHandler(db database.Database, meetingRepo *database.MeetingRepository, eventRepo *database.MeetingEventRepository, notifier *webhooks.Notifier, publisher *events.Publisher) *RecordingHandler {
 	return &RecordingHandler{
-		db: db,
+		db:          db,
+		meetingRepo: meetingRepo,
+		eventRepo:   eventRepo,
+		notifier:    notifier,
+		publisher:   publisher,
 	}
 }
 
@@ -169,6 +179,46 @@ func (h *RecordingHandler) GetRecordingsByUser(c *fiber.Ctx) error {
 	})
 }
 
+// GetRecordingEvents handles GET /admin/recordings/:id/events
+func (h *RecordingHandler) GetRecordingEvents(c *fiber.Ctx) error {
+	recordingID, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid recording ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	var exists bool
+	err = h.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM meetings WHERE id = $1)`, recordingID).Scan(&exists)
+	if err != nil {
+		log.Error().Err(err).Int("recording_id", recordingID).Msg("Failed to look up recording")
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get recording events",
+		})
+	}
+	if !exists {
+		return c.Status(404).JSON(fiber.Map{
+			"error": constants.ErrNotFound,
+		})
+	}
+
+	events, err := h.eventRepo.ListByMeeting(ctx, int64(recordingID))
+	if err != nil {
+		log.Error().Err(err).Int("recording_id", recordingID).Msg("Failed to list recording events")
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get recording events",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"recording_id": recordingID,
+		"events":       events,
+	})
+}
+
 // DeleteRecording handles DELETE /admin/recordings/:id
 func (h *RecordingHandler) DeleteRecording(c *fiber.Ctx) error {
 	recordingID, err := c.ParamsInt("id")
@@ -206,22 +256,18 @@ func (h *RecordingHandler) DeleteRecording(c *fiber.Ctx) error {
 	})
 }
 
-// CleanupStaleRecordings marks old "requested" recordings as "failed"
-// Recordings that have been in "requested" status for more than 5 minutes are considered stale
+// CleanupStaleRecordings marks stuck "requested" recordings as "failed"
+// Recordings that have been in "requested" status without an update (e.g. a spawn
+// attempt) for longer than constants.StaleRequestTimeout are considered stale
 func (h *RecordingHandler) CleanupStaleRecordings(c *fiber.Ctx) error {
 	log.Info().Msg("Cleaning up stale recordings")
 
 	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
 	defer cancel()
 
-	// Mark all recordings in "requested" status for more than 5 minutes as "failed"
-	query := `
-		UPDATE meetings
-		SET status = 'failed', updated_at = NOW()
-		WHERE status = 'requested'
-		AND created_at < NOW() - INTERVAL '5 minutes'
-	`
-	result, err := h.db.Exec(ctx, query)
+	// Spawns in retry backoff and re-queued stalled spawns keep touching updated_at
+	errMsg := "Recording was never picked up (cleaned up by admin)"
+	failed, err := h.meetingRepo.FailStale(ctx, types.MeetingStatusRequested, constants.StaleRequestTimeout, errMsg)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to cleanup stale recordings")
 		return c.Status(500).JSON(fiber.Map{
@@ -229,12 +275,15 @@ func (h *RecordingHandler) CleanupStaleRecordings(c *fiber.Ctx) error {
 		})
 	}
 
-	rowsAffected, _ := result.RowsAffected()
+	for _, meeting := range failed {
+		h.notifier.Notify(ctx, meeting.ID, types.MeetingStatusFailed)
+		h.publisher.PublishStatusChanged(ctx, meeting.ID, meeting.UserID, types.MeetingStatusFailed, &errMsg, 0)
+	}
 
-	log.Info().Int64("cleaned_up", rowsAffected).Msg("Stale recordings cleaned up")
+	log.Info().Int("cleaned_up", len(failed)).Msg("Stale recordings cleaned up")
 
 	return c.JSON(fiber.Map{
 		"message":       "Stale recordings cleaned up successfully",
-		"rows_affected": rowsAffected,
+		"rows_affected": len(failed),
 	})
 }
@@ -127,6 +127,45 @@ func (h *UserHandler) ListUsers(c *fiber.Ctx) error {
 	})
 }
 
+// UpdateUser handles PATCH /admin/users/:id
+func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var req types.UpdateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	if _, err := h.userService.GetUser(ctx, int64(userID)); err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": constants.ErrNotFound,
+		})
+	}
+
+	// Use domain service
+	userEntity, err := h.userService.UpdateUser(ctx, int64(userID), req.Name, req.MaxConcurrentBots, req.DefaultMaxDurationSeconds, req.DefaultOutputFormats)
+	if err != nil {
+		log.Warn().Err(err).Int("user_id", userID).Msg("Failed to update user")
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	log.Info().Int("user_id", userID).Msg("User updated successfully")
+
+	return c.JSON(h.adapter.ToDTO(userEntity))
+}
+
 // DeleteUser handles DELETE /admin/users/:id
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	userID, err := c.ParamsInt("id")
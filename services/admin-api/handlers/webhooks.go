@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/types"
+	"github.com/newar/insights/shared/webhooks"
+)
+
+type WebhookHandler struct {
+	webhookRepo *database.WebhookRepository
+	userRepo    *database.UserRepository
+}
+
+func NewWebhookHandler(webhookRepo *database.WebhookRepository, userRepo *database.UserRepository) *WebhookHandler {
+	return &WebhookHandler{
+		webhookRepo: webhookRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// ListWebhooks handles GET /admin/webhooks
+func (h *WebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	return h.listWebhooks(c, 0)
+}
+
+// GetWebhooksByUser handles GET /admin/users/:id/webhooks
+func (h *WebhookHandler) GetWebhooksByUser(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return h.listWebhooks(c, int64(userID))
+}
+
+// listWebhooks responds with a user's webhooks (userID=0 lists all)
+func (h *WebhookHandler) listWebhooks(c *fiber.Ctx, userID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	endpoints, err := h.webhookRepo.ListEndpoints(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("Failed to list webhooks")
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to list webhooks",
+		})
+	}
+
+	for _, endpoint := range endpoints {
+		endpoint.Secret = ""
+	}
+
+	return c.JSON(fiber.Map{
+		"webhooks": endpoints,
+		"total":    len(endpoints),
+	})
+}
+
+// CreateWebhookForUser handles POST /admin/users/:id/webhooks
+// The signing secret is only returned in this response.
+func (h *WebhookHandler) CreateWebhookForUser(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var req types.CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := webhooks.ValidateURL(req.URL); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	events, err := webhooks.ValidateEvents(req.Events)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	if _, err := h.userRepo.GetByID(ctx, int64(userID)); err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	secret, err := webhooks.GenerateSecret()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate webhook secret")
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to create webhook",
+		})
+	}
+
+	endpoint, err := h.webhookRepo.CreateEndpoint(ctx, int64(userID), req.URL, secret, events, req.Description)
+	if err != nil {
+		log.Error().Err(err).Int("user_id", userID).Msg("Failed to create webhook")
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to create webhook",
+		})
+	}
+
+	log.Info().
+		Int64("webhook_id", endpoint.ID).
+		Int("user_id", userID).
+		Str("url", endpoint.URL).
+		Msg("Webhook created by admin")
+
+	return c.Status(201).JSON(endpoint)
+}
+
+// UpdateWebhook handles PATCH /admin/webhooks/:id
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+	}
+
+	var req types.UpdateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.URL != nil {
+		if err := webhooks.ValidateURL(*req.URL); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	if req.Events != nil {
+		events, err := webhooks.ValidateEvents(req.Events)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		req.Events = events
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	if err := h.webhookRepo.UpdateEndpoint(ctx, int64(id), req); err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": constants.ErrWebhookNotFound,
+		})
+	}
+
+	endpoint, err := h.webhookRepo.GetEndpoint(ctx, int64(id))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get webhook",
+		})
+	}
+
+	endpoint.Secret = ""
+	return c.JSON(endpoint)
+}
+
+// DeleteWebhook handles DELETE /admin/webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	if err := h.webhookRepo.DeleteEndpoint(ctx, int64(id)); err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": constants.ErrWebhookNotFound,
+		})
+	}
+
+	log.Info().Int("webhook_id", id).Msg("Webhook deleted by admin")
+
+	return c.JSON(fiber.Map{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// ListDeliveries handles GET /admin/webhooks/:id/deliveries
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+	}
+
+	limit := c.QueryInt("limit", 50)
+	offset := c.QueryInt("offset", 0)
+
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	deliveries, total, err := h.webhookRepo.ListDeliveries(ctx, int64(id), limit, offset)
+	if err != nil {
+		log.Error().Err(err).Int("webhook_id", id).Msg("Failed to list webhook deliveries")
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to list webhook deliveries",
+		})
+	}
+
+	return c.JSON(types.PaginatedResponse{
+		Data:   deliveries,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// Redeliver handles POST /admin/webhooks/deliveries/:id/redeliver
+func (h *WebhookHandler) Redeliver(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid delivery ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	if err := h.webhookRepo.ResetDelivery(ctx, int64(id)); err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Delivery not found",
+		})
+	}
+
+	log.Info().Int("delivery_id", id).Msg("Webhook delivery queued for redelivery by admin")
+
+	return c.Status(202).JSON(fiber.Map{
+		"message": "Delivery queued",
+		"id":      id,
+	})
+}
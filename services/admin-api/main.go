@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 
 	"github.com/rs/zerolog/log"
 
@@ -10,7 +12,11 @@ import (
 	"github.com/newar/insights/services/admin-api/middleware"
 	"github.com/newar/insights/shared/database"
 	"github.com/newar/insights/shared/domain/services"
+	"github.com/newar/insights/shared/events"
+	"github.com/newar/insights/shared/redis"
 	"github.com/newar/insights/shared/server"
+	"github.com/newar/insights/shared/utils"
+	"github.com/newar/insights/shared/webhooks"
 )
 
 func main() {
@@ -34,8 +40,16 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to run migrations")
 	}
 
-	// Register standard endpoints (Redis not used in admin-api)
-	builder.RegisterHealthEndpoints(db, nil)
+	// Initialize Redis (status changes are published on the meeting:events channel)
+	redisClient, err := redis.NewClient(cfg.Redis)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to Redis")
+	}
+	defer redisClient.Close()
+	builder.Shutdown().Register("redis", func() { redisClient.Close() })
+
+	// Register standard endpoints
+	builder.RegisterHealthEndpoints(db, redisClient)
 	builder.RegisterMetricsEndpoint()
 
 	// Initialize domain repositories
@@ -53,8 +67,19 @@ func main() {
 	userRepo := database.NewUserRepository(db)
 	tokenHandler := handlers.NewTokenHandler(tokenRepo, userRepo)
 
-	// Recording handler
-	recordingHandler := handlers.NewRecordingHandler(db)
+	// Recording handler (failed recordings are announced to webhooks and on meeting:events)
+	meetingRepo := database.NewMeetingRepository(db)
+	webhookRepo := database.NewWebhookRepository(db)
+	notifier := webhooks.NewNotifier(webhookRepo, meetingRepo)
+	publisher := events.NewPublisher(redisClient)
+	recordingHandler := handlers.NewRecordingHandler(db, meetingRepo, database.NewMeetingEventRepository(db), notifier, publisher)
+
+	// Finalization handler (re-runs are carried out by the bot-manager)
+	botManagerURL := utils.GetEnvOrDefault("BOT_MANAGER_URL", "http://localhost:8082")
+	finalizationHandler := handlers.NewFinalizationHandler(database.NewFinalizationJobRepository(db), botManagerURL)
+
+	// Webhook handler
+	webhookHandler := handlers.NewWebhookHandler(webhookRepo, userRepo)
 
 	// Bot handler (with Docker client)
 	botHandler, err := handlers.NewBotHandler()
@@ -74,6 +99,7 @@ func main() {
 	admin.Post("/users", userHandler.CreateUser)
 	admin.Get("/users", userHandler.ListUsers)
 	admin.Get("/users/:id", userHandler.GetUser)
+	admin.Patch("/users/:id", userHandler.UpdateUser)
 	admin.Delete("/users/:id", userHandler.DeleteUser)
 
 	// Token management
@@ -82,8 +108,20 @@ func main() {
 	// Recording management
 	admin.Get("/recordings", recordingHandler.ListRecordings)
 	admin.Get("/users/:id/recordings", recordingHandler.GetRecordingsByUser)
+	admin.Get("/recordings/:id/events", recordingHandler.GetRecordingEvents)
 	admin.Delete("/recordings/:id", recordingHandler.DeleteRecording)
 	admin.Post("/recordings/cleanup", recordingHandler.CleanupStaleRecordings)
+	admin.Get("/recordings/:id/finalization", finalizationHandler.GetFinalization)
+	admin.Post("/recordings/:id/finalize", finalizationHandler.RetryFinalization)
+
+	// Webhook management
+	admin.Get("/webhooks", webhookHandler.ListWebhooks)
+	admin.Get("/users/:id/webhooks", webhookHandler.GetWebhooksByUser)
+	admin.Post("/users/:id/webhooks", webhookHandler.CreateWebhookForUser)
+	admin.Patch("/webhooks/:id", webhookHandler.UpdateWebhook)
+	admin.Delete("/webhooks/:id", webhookHandler.DeleteWebhook)
+	admin.Get("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+	admin.Post("/webhooks/deliveries/:id/redeliver", webhookHandler.Redeliver)
 
 	// Bot management
 	admin.Get("/bots/active", botHandler.GetActiveBots)
@@ -99,20 +137,38 @@ func main() {
 	builder.MustStart()
 }
 
-// runMigrations runs database migrations
+// runMigrations runs all database migrations in filename order
+// Migrations must be idempotent (IF NOT EXISTS) since they run on every startup
 func runMigrations(db database.Database) error {
 	log.Info().Msg("Running database migrations...")
 
-	migrationFile := "./migrations/001_initial_schema.sql"
-	data, err := os.ReadFile(migrationFile)
-	if err != nil {
+	migrationsDir := "./migrations"
+	if _, err := os.Stat(migrationsDir); os.IsNotExist(err) {
 		// Try alternative path (when running from services/admin-api)
-		migrationFile = "../../migrations/001_initial_schema.sql"
-		data, err = os.ReadFile(migrationFile)
+		migrationsDir = "../../migrations"
+	}
+
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.sql"))
+	if err != nil {
+		return fmt.Errorf("failed to list migration files: %w", err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no migration files found in %s", migrationsDir)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
 		if err != nil {
-			return fmt.Errorf("failed to read migration file: %w", err)
+			return fmt.Errorf("failed to read migration file %s: %w", file, err)
+		}
+
+		log.Info().Str("file", filepath.Base(file)).Msg("Applying migration")
+
+		if err := database.RunMigration(db, string(data)); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(file), err)
 		}
 	}
 
-	return database.RunMigration(db, string(data))
+	return nil
 }
@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/calendar"
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/types"
+)
+
+type CalendarHandler struct {
+	feedRepo *database.CalendarFeedRepository
+	importer *calendar.Importer
+}
+
+func NewCalendarHandler(feedRepo *database.CalendarFeedRepository, importer *calendar.Importer) *CalendarHandler {
+	return &CalendarHandler{
+		feedRepo: feedRepo,
+		importer: importer,
+	}
+}
+
+// ImportCalendar handles POST /calendars/import
+// The calendar is either uploaded (multipart "file" field or a text/calendar body,
+// with bot_name and max_duration_seconds as form fields or query parameters) or fetched from
+// the "url" of a JSON body. With "feed": true the URL is saved and re-synced periodically.
+func (h *CalendarHandler) ImportCalendar(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	var req types.CalendarImportRequest
+	var upload io.Reader
+
+	contentType := strings.ToLower(string(c.Request().Header.ContentType()))
+	switch {
+	case strings.HasPrefix(contentType, fiber.MIMEMultipartForm):
+		fileHeader, err := c.FormFile(constants.ICSUploadField)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": "Missing calendar file (form field \"" + constants.ICSUploadField + "\")",
+			})
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": constants.ErrBadRequest,
+			})
+		}
+		defer file.Close()
+		upload = file
+
+	case strings.HasPrefix(contentType, "text/calendar"):
+		upload = bytes.NewReader(c.Body())
+
+	default:
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": constants.ErrBadRequest,
+			})
+		}
+		if req.URL == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"error": "url is required unless a calendar file is uploaded",
+			})
+		}
+	}
+
+	if upload != nil {
+		if botName := formOrQuery(c, "bot_name"); botName != "" {
+			req.BotName = &botName
+		}
+		if value := formOrQuery(c, "max_duration_seconds"); value != "" {
+			maxDuration, err := strconv.Atoi(value)
+			if err != nil {
+				return c.Status(400).JSON(fiber.Map{
+					"error": "max_duration_seconds must be a number",
+				})
+			}
+			req.MaxDuration = &maxDuration
+		}
+	}
+
+	if req.BotName != nil && len(*req.BotName) > constants.BotNameMaxLength {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "bot_name is too long",
+		})
+	}
+
+	if msg := validateSchedule(nil, req.MaxDuration); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.ICSFetchTimeout+constants.LongQueryTimeout)
+	defer cancel()
+
+	if req.Feed {
+		return h.createFeed(ctx, c, userID, req)
+	}
+
+	if upload == nil {
+		feedURL, err := calendar.ValidateFeedURL(req.URL)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		body, err := h.importer.Fetch(ctx, feedURL)
+		if err != nil {
+			return importError(c, userID, err)
+		}
+		defer body.Close()
+		upload = body
+	}
+
+	opts := calendar.Options{
+		UserID:      userID,
+		BotName:     constants.DefaultBotName,
+		MaxDuration: req.MaxDuration,
+	}
+	if req.BotName != nil && *req.BotName != "" {
+		opts.BotName = *req.BotName
+	}
+
+	result, err := h.importer.Import(ctx, upload, opts)
+	if err != nil {
+		return importError(c, userID, err)
+	}
+
+	return c.JSON(result)
+}
+
+// createFeed saves a calendar feed and runs its first sync.
+// The feed is only kept if that sync succeeds.
+func (h *CalendarHandler) createFeed(ctx context.Context, c *fiber.Ctx, userID int64, req types.CalendarImportRequest) error {
+	feedURL, err := calendar.ValidateFeedURL(req.URL)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	count, err := h.feedRepo.Count(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("Failed to count calendar feeds")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+	if count >= constants.MaxCalendarFeeds {
+		return c.Status(400).JSON(fiber.Map{
+			"error": constants.ErrMaxCalendarFeeds,
+			"limit": constants.MaxCalendarFeeds,
+		})
+	}
+
+	feed, err := h.feedRepo.Create(ctx, userID, feedURL, req.BotName, req.MaxDuration)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("Failed to create calendar feed")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	result, err := h.importer.SyncFeed(ctx, feed)
+	if err != nil {
+		if deleteErr := h.feedRepo.Delete(ctx, feed.ID); deleteErr != nil {
+			log.Error().Err(deleteErr).Int64("feed_id", feed.ID).Msg("Failed to delete unsynced calendar feed")
+		}
+		return importError(c, userID, err)
+	}
+
+	log.Info().
+		Int64("feed_id", feed.ID).
+		Int64("user_id", userID).
+		Int("created", result.Created).
+		Msg("Calendar feed created")
+
+	feed, err = h.feedRepo.GetByID(ctx, feed.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"feed":   feed,
+		"import": result,
+	})
+}
+
+// ListFeeds handles GET /calendars/feeds
+func (h *CalendarHandler) ListFeeds(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	feeds, err := h.feedRepo.List(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("Failed to list calendar feeds")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"feeds": feeds,
+		"total": len(feeds),
+	})
+}
+
+// SyncFeed handles POST /calendars/feeds/:id/sync
+func (h *CalendarHandler) SyncFeed(c *fiber.Ctx) error {
+	feed, ok := h.ownedFeed(c)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.ICSFetchTimeout+constants.LongQueryTimeout)
+	defer cancel()
+
+	result, err := h.importer.SyncFeed(ctx, feed)
+	if err != nil {
+		return importError(c, feed.UserID, err)
+	}
+
+	return c.JSON(result)
+}
+
+// DeleteFeed handles DELETE /calendars/feeds/:id
+// Recordings imported from the feed that are still scheduled are cancelled.
+func (h *CalendarHandler) DeleteFeed(c *fiber.Ctx) error {
+	feed, ok := h.ownedFeed(c)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.LongQueryTimeout)
+	defer cancel()
+
+	cancelled, err := h.importer.CancelFeedMeetings(ctx, feed.ID, "Calendar feed removed")
+	if err != nil {
+		log.Error().Err(err).Int64("feed_id", feed.ID).Msg("Failed to cancel calendar feed recordings")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	if err := h.feedRepo.Delete(ctx, feed.ID); err != nil {
+		log.Error().Err(err).Int64("feed_id", feed.ID).Msg("Failed to delete calendar feed")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	log.Info().
+		Int64("feed_id", feed.ID).
+		Int("cancelled", cancelled).
+		Msg("Calendar feed deleted")
+
+	return c.JSON(fiber.Map{
+		"message":   "Calendar feed deleted",
+		"cancelled": cancelled,
+	})
+}
+
+// ownedFeed loads the :id feed, responding 404 unless it belongs to the caller.
+// Returns false once a response is written.
+func (h *CalendarHandler) ownedFeed(c *fiber.Ctx) (*types.CalendarFeed, bool) {
+	userID := c.Locals("user_id").(int64)
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		c.Status(400).JSON(fiber.Map{
+			"error": "Invalid calendar feed ID",
+		})
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	feed, err := h.feedRepo.GetByID(ctx, int64(id))
+	if err != nil || feed.UserID != userID {
+		c.Status(404).JSON(fiber.Map{
+			"error": constants.ErrCalendarNotFound,
+		})
+		return nil, false
+	}
+
+	return feed, true
+}
+
+// importError responds to a failed calendar import or sync
+func importError(c *fiber.Ctx, userID int64, err error) error {
+	switch {
+	case errors.Is(err, calendar.ErrTooLarge):
+		return c.Status(413).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, calendar.ErrInvalid):
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, calendar.ErrFetchFailed):
+		return c.Status(422).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	log.Error().Err(err).Int64("user_id", userID).Msg("Failed to import calendar")
+	return c.Status(500).JSON(fiber.Map{
+		"error": constants.ErrInternalServer,
+	})
+}
+
+// formOrQuery returns a multipart form value, falling back to the query string
+func formOrQuery(c *fiber.Ctx, key string) string {
+	if value := c.FormValue(key); value != "" {
+		return value
+	}
+	return c.Query(key)
+}
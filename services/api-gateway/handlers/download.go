@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/storage"
+)
+
+// byteRange is the part of a file a Range request asks for
+type byteRange struct {
+	start  int64
+	length int64
+}
+
+// sendObject streams a file from storage, with ETag and Last-Modified validators,
+// conditional GETs (304) and single byte ranges (206, or 416 if unsatisfiable).
+// Ranges are read from storage directly, so seeking never downloads the whole file.
+func sendObject(c *fiber.Ctx, store storage.Storage, path string, info storage.ObjectInfo, contentType, filename string) error {
+	c.Set("Accept-Ranges", "bytes")
+	if info.ETag != "" {
+		c.Set("ETag", info.ETag)
+	}
+	if !info.LastModified.IsZero() {
+		c.Set("Last-Modified", info.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c, info) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set("Content-Type", contentType)
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+
+	status := fiber.StatusOK
+	span := byteRange{start: 0, length: info.Size}
+	if rangeHeader := c.Get("Range"); rangeHeader != "" && rangeApplies(c.Get("If-Range"), info) {
+		requested, ok, satisfiable := parseRange(rangeHeader, info.Size)
+		if !satisfiable {
+			c.Set("Content-Range", fmt.Sprintf("bytes */%d", info.Size))
+			return c.Status(fiber.StatusRequestedRangeNotSatisfiable).JSON(fiber.Map{
+				"error": "Requested range not satisfiable",
+			})
+		}
+		if ok {
+			status = fiber.StatusPartialContent
+			span = requested
+			c.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", span.start, span.start+span.length-1, info.Size))
+		}
+	}
+
+	c.Status(status)
+	if c.Method() == fiber.MethodHead {
+		c.Response().Header.SetContentLength(int(span.length))
+		return nil
+	}
+
+	// The body is streamed after the handler returns, so the download must outlive the request context
+	var reader io.ReadCloser
+	var err error
+	if status == fiber.StatusPartialContent {
+		reader, err = store.DownloadRange(context.Background(), path, span.start, span.length)
+	} else {
+		reader, err = store.Download(context.Background(), path)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("path", path).Msg("Failed to open file")
+		c.Response().Header.Del("Content-Range")
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to download file",
+		})
+	}
+
+	// Closed once sent
+	return c.SendStream(reader, int(span.length))
+}
+
+// notModified evaluates If-None-Match and, without it, If-Modified-Since
+func notModified(c *fiber.Ctx, info storage.ObjectInfo) bool {
+	if method := c.Method(); method != fiber.MethodGet && method != fiber.MethodHead {
+		return false
+	}
+
+	if header := c.Get("If-None-Match"); header != "" {
+		if info.ETag == "" {
+			return false
+		}
+		for _, tag := range strings.Split(header, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(info.ETag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if header := c.Get("If-Modified-Since"); header != "" && !info.LastModified.IsZero() {
+		since, err := http.ParseTime(header)
+		return err == nil && !info.LastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// rangeApplies evaluates If-Range: the Range header is only honoured if the file
+// still matches the validator the client's partial copy came with
+func rangeApplies(ifRange string, info storage.ObjectInfo) bool {
+	if ifRange == "" {
+		return true
+	}
+
+	// Entity tags need a strong match
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		return !strings.HasPrefix(ifRange, "W/") && ifRange == info.ETag
+	}
+
+	date, err := http.ParseTime(ifRange)
+	return err == nil && !info.LastModified.IsZero() && info.LastModified.Truncate(time.Second).Equal(date)
+}
+
+// parseRange parses a Range header against a file of the given size. ok is false
+// for headers that are ignored (malformed, other units, multiple ranges), in which
+// case the whole file is served; satisfiable is false if no byte of the file is in range.
+func parseRange(header string, size int64) (span byteRange, ok bool, satisfiable bool) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return byteRange{}, false, true
+	}
+
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return byteRange{}, false, true
+	}
+
+	// Suffix range: the last N bytes
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return byteRange{}, false, true
+		}
+		if n == 0 || size == 0 {
+			return byteRange{}, false, false
+		}
+		n = min(n, size)
+		return byteRange{start: size - n, length: n}, true, true
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return byteRange{}, false, true
+	}
+
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return byteRange{}, false, true
+		}
+		end = min(end, size-1)
+	}
+
+	if start >= size {
+		return byteRange{}, false, false
+	}
+
+	return byteRange{start: start, length: end - start + 1}, true, true
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/newar/insights/shared/storage"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		size        int64
+		want        byteRange
+		ok          bool
+		satisfiable bool
+	}{
+		{"closed range", "bytes=0-99", 1000, byteRange{start: 0, length: 100}, true, true},
+		{"open-ended range", "bytes=900-", 1000, byteRange{start: 900, length: 100}, true, true},
+		{"end clamped to size", "bytes=900-5000", 1000, byteRange{start: 900, length: 100}, true, true},
+		{"single last byte", "bytes=999-999", 1000, byteRange{start: 999, length: 1}, true, true},
+		{"suffix range", "bytes=-100", 1000, byteRange{start: 900, length: 100}, true, true},
+		{"suffix larger than file", "bytes=-5000", 1000, byteRange{start: 0, length: 1000}, true, true},
+		{"zero-length suffix", "bytes=-0", 1000, byteRange{}, false, false},
+		{"start at size", "bytes=1000-", 1000, byteRange{}, false, false},
+		{"start past size", "bytes=2000-3000", 1000, byteRange{}, false, false},
+		{"empty file", "bytes=0-", 0, byteRange{}, false, false},
+		{"suffix on empty file", "bytes=-10", 0, byteRange{}, false, false},
+		{"multiple ranges ignored", "bytes=0-9,20-29", 1000, byteRange{}, false, true},
+		{"other unit ignored", "items=0-9", 1000, byteRange{}, false, true},
+		{"missing dash ignored", "bytes=100", 1000, byteRange{}, false, true},
+		{"end before start ignored", "bytes=500-100", 1000, byteRange{}, false, true},
+		{"non-numeric ignored", "bytes=a-b", 1000, byteRange{}, false, true},
+		{"negative start ignored", "bytes=--5", 1000, byteRange{}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, satisfiable := parseRange(tt.header, tt.size)
+			if got != tt.want || ok != tt.ok || satisfiable != tt.satisfiable {
+				t.Errorf("parseRange(%q, %d) = %+v, %v, %v; want %+v, %v, %v",
+					tt.header, tt.size, got, ok, satisfiable, tt.want, tt.ok, tt.satisfiable)
+			}
+		})
+	}
+}
+
+func TestRangeApplies(t *testing.T) {
+	modified := time.Date(2026, 10, 17, 12, 30, 45, 500_000_000, time.UTC)
+	info := storage.ObjectInfo{
+		Size:         1000,
+		ETag:         `"abc123"`,
+		LastModified: modified,
+	}
+
+	tests := []struct {
+		name    string
+		ifRange string
+		info    storage.ObjectInfo
+		want    bool
+	}{
+		{"no If-Range", "", info, true},
+		{"matching etag", `"abc123"`, info, true},
+		{"stale etag", `"old"`, info, false},
+		{"weak etag never matches", `W/"abc123"`, info, false},
+		{"weak file etag never matches", `W/"abc123"`, storage.ObjectInfo{ETag: `W/"abc123"`}, false},
+		{"matching date", modified.Format(http.TimeFormat), info, true},
+		{"older date", modified.Add(-time.Hour).Format(http.TimeFormat), info, false},
+		{"newer date", modified.Add(time.Hour).Format(http.TimeFormat), info, false},
+		{"date without modification time", modified.Format(http.TimeFormat), storage.ObjectInfo{ETag: `"abc123"`}, false},
+		{"invalid date", "yesterday", info, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rangeApplies(tt.ifRange, tt.info); got != tt.want {
+				t.Errorf("rangeApplies(%q) = %v, want %v", tt.ifRange, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2026, 10, 17, 12, 30, 45, 500_000_000, time.UTC)
+	info := storage.ObjectInfo{
+		Size:         1000,
+		ETag:         `"abc123"`,
+		LastModified: modified,
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		info    storage.ObjectInfo
+		want    bool
+	}{
+		{"no conditions", fiber.MethodGet, nil, info, false},
+		{"matching etag", fiber.MethodGet, map[string]string{"If-None-Match": `"abc123"`}, info, true},
+		{"matching etag on HEAD", fiber.MethodHead, map[string]string{"If-None-Match": `"abc123"`}, info, true},
+		{"weak etag matches", fiber.MethodGet, map[string]string{"If-None-Match": `W/"abc123"`}, info, true},
+		{"etag in list", fiber.MethodGet, map[string]string{"If-None-Match": `"old", "abc123"`}, info, true},
+		{"wildcard", fiber.MethodGet, map[string]string{"If-None-Match": "*"}, info, true},
+		{"stale etag", fiber.MethodGet, map[string]string{"If-None-Match": `"old"`}, info, false},
+		{"file without etag", fiber.MethodGet, map[string]string{"If-None-Match": `"abc123"`}, storage.ObjectInfo{LastModified: modified}, false},
+		{"etag takes precedence over date", fiber.MethodGet, map[string]string{
+			"If-None-Match":     `"old"`,
+			"If-Modified-Since": modified.Add(time.Hour).Format(http.TimeFormat),
+		}, info, false},
+		{"not modified since same second", fiber.MethodGet, map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, info, true},
+		{"not modified since later date", fiber.MethodGet, map[string]string{"If-Modified-Since": modified.Add(time.Hour).Format(http.TimeFormat)}, info, true},
+		{"modified since earlier date", fiber.MethodGet, map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)}, info, false},
+		{"invalid date", fiber.MethodGet, map[string]string{"If-Modified-Since": "yesterday"}, info, false},
+		{"file without modification time", fiber.MethodGet, map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, storage.ObjectInfo{}, false},
+		{"POST is never conditional", fiber.MethodPost, map[string]string{"If-None-Match": "*"}, info, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			app := fiber.New()
+			app.All("/", func(c *fiber.Ctx) error {
+				got = notModified(c, tt.info)
+				return c.SendStatus(fiber.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("notModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/storage"
+	"github.com/newar/insights/shared/types"
+)
+
+// FileHandler serves files from local storage through the signed URLs it issues.
+// Remote storage serves its own presigned URLs.
+type FileHandler struct {
+	storage  storage.Storage
+	verifier storage.SignedURLVerifier
+}
+
+func NewFileHandler(store storage.Storage, verifier storage.SignedURLVerifier) *FileHandler {
+	return &FileHandler{
+		storage:  store,
+		verifier: verifier,
+	}
+}
+
+// ServeFile handles GET /files/*?expires=...&signature=..., with the same Range
+// and conditional GET support as recording downloads
+func (h *FileHandler) ServeFile(c *fiber.Ctx) error {
+	filePath, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid file path",
+		})
+	}
+
+	if err := h.verifier.VerifySignedURL(filePath, c.Query("expires"), c.Query("signature")); err != nil {
+		if errors.Is(err, storage.ErrURLExpired) {
+			return c.Status(403).JSON(fiber.Map{
+				"error": "Link has expired",
+			})
+		}
+		return c.Status(403).JSON(fiber.Map{
+			"error": "Invalid link signature",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info, err := h.storage.Stat(ctx, filePath)
+	if errors.Is(err, storage.ErrNotFound) {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "File not found",
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("path", filePath).Msg("Failed to check file")
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to download file",
+		})
+	}
+
+	format := types.OutputFormat(strings.TrimPrefix(path.Ext(filePath), "."))
+	return sendObject(c, h.storage, filePath, info, format.ContentType(), path.Base(filePath))
+}
@@ -1,35 +1,56 @@
 package handlers
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
 
+	"github.com/newar/insights/services/api-gateway/middleware"
 	"github.com/newar/insights/shared/constants"
 	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/events"
+	"github.com/newar/insights/shared/redis"
+	"github.com/newar/insights/shared/storage"
 	"github.com/newar/insights/shared/types"
 	"github.com/newar/insights/shared/utils"
+	"github.com/newar/insights/shared/webhooks"
 )
 
 type RecordingHandler struct {
 	meetingRepo   *database.MeetingRepository
 	userRepo      *database.UserRepository
+	eventRepo     *database.MeetingEventRepository
+	fileRepo      *database.RecordingFileRepository
+	redisClient   *redis.Client
+	spawnQueue    *redis.JobQueue
+	notifier      *webhooks.Notifier
+	publisher     *events.Publisher
+	storage       storage.Storage
+	redirect      bool // Redirect downloads to the storage URL instead of streaming them
+	streamTokens  *middleware.StreamTokens
 	botManagerURL string
 }
 
-func NewRecordingHandler(meetingRepo *database.MeetingRepository, userRepo *database.UserRepository, botManagerURL string) *RecordingHandler {
+func NewRecordingHandler(meetingRepo *database.MeetingRepository, userRepo *database.UserRepository, eventRepo *database.MeetingEventRepository, fileRepo *database.RecordingFileRepository, redisClient *redis.Client, spawnQueue *redis.JobQueue, notifier *webhooks.Notifier, publisher *events.Publisher, store storage.Storage, redirectDownloads bool, streamTokens *middleware.StreamTokens, botManagerURL string) *RecordingHandler {
 	return &RecordingHandler{
 		meetingRepo:   meetingRepo,
 		userRepo:      userRepo,
+		eventRepo:     eventRepo,
+		fileRepo:      fileRepo,
+		redisClient:   redisClient,
+		spawnQueue:    spawnQueue,
+		notifier:      notifier,
+		publisher:     publisher,
+		storage:       store,
+		redirect:      redirectDownloads,
+		streamTokens:  streamTokens,
 		botManagerURL: botManagerURL,
 	}
 }
@@ -58,9 +79,30 @@ func (h *RecordingHandler) CreateRecording(c *fiber.Ctx) error {
 		req.BotName = constants.DefaultBotName
 	}
 
+	if msg := validateSchedule(req.ScheduledAt, req.MaxDuration); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	if len(req.OutputFormats) > 0 {
+		formats, err := types.ParseOutputFormats(req.OutputFormats)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		req.OutputFormats = formats
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Scheduled recordings do not take a bot slot until bot-manager spawns them
+	if req.ScheduledAt != nil {
+		return h.scheduleRecording(ctx, c, userID, req)
+	}
+
 	// Check if user has reached max concurrent bots
 	user, err := h.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -105,6 +147,13 @@ func (h *RecordingHandler) CreateRecording(c *fiber.Ctx) error {
 		})
 	}
 
+	h.publisher.Publish(ctx, types.EventMeetingCreated, meeting.ID, userID, types.MeetingCreatedPayload{
+		Platform:        meeting.Platform,
+		NativeMeetingID: meeting.MeetingID,
+		MeetingURL:      meeting.MeetingURL,
+		BotName:         meeting.BotName,
+	})
+
 	// Send request to bot-manager to spawn bot
 	spawnReq := types.SpawnBotRequest{
 		MeetingID:  meeting.ID,
@@ -114,7 +163,22 @@ func (h *RecordingHandler) CreateRecording(c *fiber.Ctx) error {
 		BotName:    req.BotName,
 	}
 
-	go h.spawnBot(spawnReq, meeting.ID)
+	// Queue the spawn for bot-manager (retried until it succeeds or is dead-lettered)
+	if _, err := h.spawnQueue.Enqueue(ctx, spawnReq); err != nil {
+		log.Error().Err(err).Int64("meeting_id", meeting.ID).Msg("Failed to enqueue bot spawn")
+
+		errMsg := "Failed to queue bot spawn"
+		if err := h.meetingRepo.UpdateStatus(ctx, meeting.ID, types.StatusFailed, nil, &errMsg, nil); err != nil {
+			log.Error().Err(err).Int64("meeting_id", meeting.ID).Msg("Failed to mark meeting as failed")
+		} else {
+			h.notifier.Notify(ctx, meeting.ID, types.StatusFailed)
+			h.publisher.PublishStatusChanged(ctx, meeting.ID, userID, types.StatusFailed, &errMsg, 0)
+		}
+
+		return c.Status(503).JSON(fiber.Map{
+			"error": constants.ErrServiceUnavailable,
+		})
+	}
 
 	log.Info().
 		Int64("meeting_id", meeting.ID).
@@ -125,33 +189,6 @@ func (h *RecordingHandler) CreateRecording(c *fiber.Ctx) error {
 	return c.Status(201).JSON(meeting)
 }
 
-// spawnBot sends a request to bot-manager to spawn a bot (async)
-func (h *RecordingHandler) spawnBot(req types.SpawnBotRequest, meetingID int64) {
-	data, err := json.Marshal(req)
-	if err != nil {
-		log.Error().Err(err).Int64("meeting_id", meetingID).Msg("Failed to marshal spawn bot request")
-		return
-	}
-
-	url := h.botManagerURL + "/bots/spawn"
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
-	if err != nil {
-		log.Error().Err(err).Str("url", url).Msg("Failed to spawn bot")
-		// TODO: Update meeting status to failed
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		log.Error().
-			Int("status_code", resp.StatusCode).
-			Str("response", string(body)).
-			Msg("Bot manager returned error")
-		// TODO: Update meeting status to failed
-	}
-}
-
 // GetRecording handles GET /recordings/{platform}/{meeting_id}
 func (h *RecordingHandler) GetRecording(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
@@ -174,15 +211,49 @@ func (h *RecordingHandler) GetRecording(c *fiber.Ctx) error {
 		})
 	}
 
-	// Add recording URL if available
-	if meeting.RecordingPath != nil && *meeting.RecordingPath != "" {
-		recordingURL := fmt.Sprintf("/recordings/%s/%s/download", platform, meetingID)
-		meeting.RecordingURL = &recordingURL
+	// Add a short-lived recording URL if available
+	meeting.RecordingURL = h.recordingURL(ctx, meeting)
+
+	files, err := h.fileRepo.ListByMeeting(ctx, meeting.ID)
+	if err != nil {
+		log.Error().Err(err).Int64("meeting_id", meeting.ID).Msg("Failed to list recording files")
 	}
+	meeting.Files = files
 
 	return c.JSON(meeting)
 }
 
+// GetRecordingEvents handles GET /recordings/:platform/:meeting_id/events
+func (h *RecordingHandler) GetRecordingEvents(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	platform := types.Platform(c.Params("platform"))
+	meetingID := c.Params("meeting_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	meeting, err := h.meetingRepo.GetByPlatformAndMeetingID(ctx, userID, platform, meetingID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": constants.ErrRecordingNotFound,
+		})
+	}
+
+	events, err := h.eventRepo.ListByMeeting(ctx, meeting.ID)
+	if err != nil {
+		log.Error().Err(err).Int64("meeting_id", meeting.ID).Msg("Failed to list meeting events")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"meeting_id": meeting.ID,
+		"status":     meeting.Status,
+		"events":     events,
+	})
+}
+
 // ListRecordings handles GET /recordings
 func (h *RecordingHandler) ListRecordings(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
@@ -209,11 +280,19 @@ func (h *RecordingHandler) ListRecordings(c *fiber.Ctx) error {
 	}
 
 	// Add recording URLs
+	meetingIDs := make([]int64, len(meetings))
 	for i := range meetings {
-		if meetings[i].RecordingPath != nil && *meetings[i].RecordingPath != "" {
-			recordingURL := fmt.Sprintf("/recordings/%s/%s/download", meetings[i].Platform, meetings[i].MeetingID)
-			meetings[i].RecordingURL = &recordingURL
-		}
+		meetingIDs[i] = meetings[i].ID
+		meetings[i].RecordingURL = h.recordingURL(ctx, &meetings[i])
+	}
+
+	// Add file metadata
+	files, err := h.fileRepo.ListByMeetings(ctx, meetingIDs)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("Failed to list recording files")
+	}
+	for i := range meetings {
+		meetings[i].Files = files[meetings[i].ID]
 	}
 
 	return c.JSON(types.PaginatedResponse{
@@ -246,20 +325,138 @@ func (h *RecordingHandler) StopRecording(c *fiber.Ctx) error {
 		})
 	}
 
-	// Send stop command to bot-manager
-	// TODO: Implement stop command via Redis or HTTP
+	if meeting.Status == types.StatusFinalizing {
+		return c.Status(409).JSON(fiber.Map{
+			"error":  "Recording is already stopping",
+			"status": meeting.Status,
+		})
+	}
+
+	sessionID := ""
+	if meeting.RecordingSessionID != nil {
+		sessionID = *meeting.RecordingSessionID
+	}
+
+	// Nothing recorded yet - cancel the recording instead of finalizing it
+	if meeting.Status != types.StatusRecording {
+		errMsg := "Recording cancelled before recording started"
+		if err := h.meetingRepo.UpdateStatus(ctx, meeting.ID, types.StatusFailed, nil, &errMsg, nil); err != nil {
+			return h.statusWriteError(c, meeting.ID, err)
+		}
+		h.notifier.Notify(ctx, meeting.ID, types.StatusFailed)
+		h.publisher.PublishStatusChanged(ctx, meeting.ID, userID, types.StatusFailed, &errMsg, 0)
+
+		if sessionID != "" {
+			go h.stopBot(sessionID, meeting.ID)
+		}
+
+		log.Info().
+			Int64("meeting_id", meeting.ID).
+			Str("session_id", sessionID).
+			Str("previous_status", string(meeting.Status)).
+			Msg("Recording cancelled before recording started")
+
+		return c.JSON(fiber.Map{
+			"message": "Recording cancelled",
+			"status":  types.MeetingStatusFailed,
+		})
+	}
+
+	// Stored first, so the stop request outlives this gateway if it goes away
+	// before stopBot is done
+	if err := h.meetingRepo.RequestStop(ctx, meeting.ID, constants.UserStopReason); err != nil {
+		log.Error().Err(err).Int64("meeting_id", meeting.ID).Msg("Failed to store stop request")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	if err := h.meetingRepo.UpdateStatus(ctx, meeting.ID, types.StatusFinalizing, nil, nil, nil); err != nil {
+		return h.statusWriteError(c, meeting.ID, err)
+	}
+	h.publisher.PublishStatusChanged(ctx, meeting.ID, userID, types.StatusFinalizing, nil, 0)
+
+	go h.stopBot(sessionID, meeting.ID)
+
 	log.Info().
 		Int64("meeting_id", meeting.ID).
-		Str("container_id", *meeting.BotContainerID).
+		Str("session_id", sessionID).
 		Msg("Stop recording requested")
 
-	return c.JSON(fiber.Map{
+	return c.Status(202).JSON(fiber.Map{
 		"message": "Recording stop requested",
-		"status":  meeting.Status,
+		"status":  types.MeetingStatusFinalizing,
 	})
 }
 
+// statusWriteError maps a failed status write to a response. A rejected transition
+// means the recording changed status concurrently (e.g. the bot just finished).
+func (h *RecordingHandler) statusWriteError(c *fiber.Ctx, meetingID int64, err error) error {
+	var transitionErr *database.InvalidTransitionError
+	if errors.As(err, &transitionErr) {
+		return c.Status(409).JSON(fiber.Map{
+			"error":  "Recording status changed, please retry",
+			"status": transitionErr.From,
+		})
+	}
+
+	log.Error().Err(err).Int64("meeting_id", meetingID).Msg("Failed to update recording status")
+	return c.Status(500).JSON(fiber.Map{
+		"error": constants.ErrInternalServer,
+	})
+}
+
+// stopBot asks the bot to stop via Redis and waits for it to finish flushing its
+// last chunk. If the bot does not report a terminal status within the grace period,
+// bot-manager is asked to stop the container (async). This is only the fast path:
+// the bot-manager reaper enforces the same grace period from the stored stop request.
+func (h *RecordingHandler) stopBot(sessionID string, meetingID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.BotStopGracePeriod)
+	defer cancel()
+
+	command := types.BotCommand{
+		Command:   constants.BotCommandStop,
+		Timestamp: time.Now(),
+	}
+
+	status, err := h.redisClient.PublishBotCommandAndWait(ctx, sessionID, command,
+		types.MeetingStatusCompleted, types.MeetingStatusFailed)
+	if err == nil {
+		log.Info().
+			Int64("meeting_id", meetingID).
+			Str("session_id", sessionID).
+			Str("status", string(status.Status)).
+			Msg("Bot acknowledged stop command")
+		return
+	}
+
+	log.Warn().
+		Err(err).
+		Int64("meeting_id", meetingID).
+		Str("session_id", sessionID).
+		Msg("Bot did not stop within grace period - stopping container")
+
+	url := fmt.Sprintf("%s/bots/%s/stop", h.botManagerURL, sessionID)
+	client := &http.Client{Timeout: constants.ContainerStopTimeout + 15*time.Second}
+	resp, err := client.Post(url, "application/json", nil)
+	if err != nil {
+		log.Error().Err(err).Str("url", url).Msg("Failed to stop bot container")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Error().
+			Int("status_code", resp.StatusCode).
+			Str("response", string(body)).
+			Msg("Bot manager returned error")
+	}
+}
+
 // DownloadRecording handles GET /recordings/{platform}/{meeting_id}/download
+// ?format=mp3 (or m4a, wav, ogg) serves that rendition instead of the webm recording.
+// Supports Range/If-Range requests and conditional GETs on ETag and Last-Modified.
 func (h *RecordingHandler) DownloadRecording(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
 	platform := types.Platform(c.Params("platform"))
@@ -281,25 +478,88 @@ func (h *RecordingHandler) DownloadRecording(c *fiber.Ctx) error {
 		})
 	}
 
-	// For local storage, serve file directly
-	storagePath := os.Getenv("STORAGE_PATH")
-	if storagePath == "" {
-		storagePath = "./storage/recordings"
+	format := types.OutputFormatWebM
+	if value := c.Query("format"); value != "" {
+		format = types.OutputFormat(strings.ToLower(value))
+		if !format.IsValid() {
+			return c.Status(400).JSON(fiber.Map{
+				"error": "Unsupported format: " + value,
+			})
+		}
 	}
 
-	filePath := filepath.Join(storagePath, *meeting.RecordingPath)
+	recordingPath := *meeting.RecordingPath
+	if format != types.OutputFormatWebM {
+		path, ok := h.renditionPath(ctx, meeting.ID, format)
+		if !ok {
+			return c.Status(404).JSON(fiber.Map{
+				"error": "Recording not available in " + string(format) + " format",
+			})
+		}
+		recordingPath = path
+	}
 
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		log.Error().Str("file_path", filePath).Msg("Recording file not found")
+	info, err := h.storage.Stat(ctx, recordingPath)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Error().Str("path", recordingPath).Msg("Recording file not found")
 		return c.Status(404).JSON(fiber.Map{
 			"error": "Recording file not found",
 		})
 	}
+	if err != nil {
+		log.Error().Err(err).Str("path", recordingPath).Msg("Failed to check recording file")
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to download recording",
+		})
+	}
 
-	// Send file
-	c.Set("Content-Type", "audio/webm")
-	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s_%s.webm\"", platform, meetingID))
+	// Storage that can't sign URLs (local storage without a base URL) can only be streamed
+	if h.redirect {
+		url, err := h.storage.SignedURL(ctx, recordingPath, constants.SignedURLTTL)
+		if err == nil {
+			return c.Redirect(url, fiber.StatusFound)
+		}
+		if !errors.Is(err, storage.ErrSigningUnavailable) {
+			log.Warn().Err(err).Str("path", recordingPath).Msg("Failed to sign recording URL, streaming instead")
+		}
+	}
+
+	// Stream file, or the requested range of it
+	filename := fmt.Sprintf("%s_%s%s", platform, meetingID, format.Extension())
+	return sendObject(c, h.storage, recordingPath, info, format.ContentType(), filename)
+}
+
+// recordingURL returns a short-lived signed link to a meeting's recording, or the
+// authenticated download endpoint if the storage can't sign URLs
+func (h *RecordingHandler) recordingURL(ctx context.Context, meeting *types.Meeting) *string {
+	if meeting.RecordingPath == nil || *meeting.RecordingPath == "" {
+		return nil
+	}
+
+	url, err := h.storage.SignedURL(ctx, *meeting.RecordingPath, constants.SignedURLTTL)
+	if err != nil {
+		if !errors.Is(err, storage.ErrSigningUnavailable) {
+			log.Warn().Err(err).Int64("meeting_id", meeting.ID).Msg("Failed to sign recording URL")
+		}
+		url = fmt.Sprintf("/recordings/%s/%s/download", meeting.Platform, meeting.MeetingID)
+	}
+
+	return &url
+}
+
+// renditionPath returns the stored path of a meeting's recording in the given format
+func (h *RecordingHandler) renditionPath(ctx context.Context, meetingID int64, format types.OutputFormat) (string, bool) {
+	files, err := h.fileRepo.ListByMeeting(ctx, meetingID)
+	if err != nil {
+		log.Error().Err(err).Int64("meeting_id", meetingID).Msg("Failed to list recording files")
+		return "", false
+	}
+
+	for _, file := range files {
+		if file.Format == string(format) {
+			return file.Path, true
+		}
+	}
 
-	return c.SendFile(filePath)
+	return "", false
 }
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/types"
+	"github.com/newar/insights/shared/utils"
+)
+
+// scheduleRecording creates a recording in the "scheduled" status.
+// bot-manager's scheduler queues the bot spawn shortly before scheduled_at.
+func (h *RecordingHandler) scheduleRecording(ctx context.Context, c *fiber.Ctx, userID int64, req types.CreateRecordingRequest) error {
+	meetingURL := utils.BuildMeetingURL(string(req.Platform), req.MeetingID)
+
+	meeting, err := h.meetingRepo.Create(ctx, userID, req, meetingURL)
+	if err != nil {
+		log.Error().Err(err).Str("meeting_id", req.MeetingID).Msg("Failed to create scheduled meeting")
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to create recording",
+		})
+	}
+
+	h.publisher.Publish(ctx, types.EventMeetingCreated, meeting.ID, userID, types.MeetingCreatedPayload{
+		Platform:        meeting.Platform,
+		NativeMeetingID: meeting.MeetingID,
+		MeetingURL:      meeting.MeetingURL,
+		BotName:         meeting.BotName,
+	})
+
+	log.Info().
+		Int64("meeting_id", meeting.ID).
+		Int64("user_id", userID).
+		Str("platform", string(req.Platform)).
+		Time("scheduled_at", *req.ScheduledAt).
+		Msg("Recording scheduled")
+
+	return c.Status(201).JSON(meeting)
+}
+
+// ListScheduledRecordings handles GET /recordings/scheduled
+func (h *RecordingHandler) ListScheduledRecordings(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	limit := c.QueryInt("limit", 20)
+	offset := c.QueryInt("offset", 0)
+
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	meetings, total, err := h.meetingRepo.ListScheduled(ctx, userID, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("Failed to list scheduled meetings")
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to list scheduled recordings",
+		})
+	}
+
+	return c.JSON(types.PaginatedResponse{
+		Data:   meetings,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// RescheduleRecording handles PATCH /recordings/scheduled/:id
+func (h *RecordingHandler) RescheduleRecording(c *fiber.Ctx) error {
+	meeting, ok := h.ownedScheduledMeeting(c)
+	if !ok {
+		return nil
+	}
+
+	var req types.RescheduleRecordingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": constants.ErrBadRequest,
+		})
+	}
+
+	if req.ScheduledAt.IsZero() {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "scheduled_at is required",
+		})
+	}
+
+	if msg := validateSchedule(&req.ScheduledAt, req.MaxDuration); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.meetingRepo.Reschedule(ctx, meeting.ID, req.ScheduledAt, req.MaxDuration); err != nil {
+		return h.statusWriteError(c, meeting.ID, err)
+	}
+
+	updated, err := h.meetingRepo.GetByID(ctx, meeting.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	log.Info().
+		Int64("meeting_id", meeting.ID).
+		Time("scheduled_at", req.ScheduledAt).
+		Msg("Recording rescheduled")
+
+	return c.JSON(updated)
+}
+
+// CancelScheduledRecording handles DELETE /recordings/scheduled/:id
+func (h *RecordingHandler) CancelScheduledRecording(c *fiber.Ctx) error {
+	meeting, ok := h.ownedScheduledMeeting(c)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Only applies while still scheduled; the scheduler may have released it meanwhile
+	errMsg := "Scheduled recording cancelled"
+	if err := h.meetingRepo.CancelScheduled(ctx, meeting.ID, errMsg); err != nil {
+		return h.statusWriteError(c, meeting.ID, err)
+	}
+	h.notifier.Notify(ctx, meeting.ID, types.StatusFailed)
+	h.publisher.PublishStatusChanged(ctx, meeting.ID, meeting.UserID, types.StatusFailed, &errMsg, 0)
+
+	log.Info().Int64("meeting_id", meeting.ID).Msg("Scheduled recording cancelled")
+
+	return c.JSON(fiber.Map{
+		"message": "Scheduled recording cancelled",
+		"status":  types.MeetingStatusFailed,
+	})
+}
+
+// ownedScheduledMeeting loads the :id meeting, responding 404 unless it belongs to
+// the caller and 409 unless it is still scheduled. Returns false once a response is written.
+func (h *RecordingHandler) ownedScheduledMeeting(c *fiber.Ctx) (*types.Meeting, bool) {
+	userID := c.Locals("user_id").(int64)
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		c.Status(400).JSON(fiber.Map{
+			"error": "Invalid recording ID",
+		})
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	meeting, err := h.meetingRepo.GetByID(ctx, int64(id))
+	if err != nil || meeting.UserID != userID {
+		c.Status(404).JSON(fiber.Map{
+			"error": constants.ErrRecordingNotFound,
+		})
+		return nil, false
+	}
+
+	if meeting.Status != types.MeetingStatusScheduled {
+		c.Status(409).JSON(fiber.Map{
+			"error":  "Recording is no longer scheduled",
+			"status": meeting.Status,
+		})
+		return nil, false
+	}
+
+	return meeting, true
+}
+
+// validateSchedule checks the optional scheduling fields of a request.
+// Returns an error message, or "" when they are valid.
+func validateSchedule(scheduledAt *time.Time, maxDuration *int) string {
+	if scheduledAt != nil {
+		now := time.Now()
+		if !scheduledAt.After(now) {
+			return "scheduled_at must be in the future"
+		}
+		if scheduledAt.After(now.Add(constants.ScheduleMaxAhead)) {
+			return fmt.Sprintf("scheduled_at must be within %d days", int(constants.ScheduleMaxAhead.Hours()/24))
+		}
+	}
+
+	if maxDuration != nil {
+		maxSeconds := int(constants.MaxRecordingDuration.Seconds())
+		if *maxDuration < constants.MinRecordingDuration || *maxDuration > maxSeconds {
+			return fmt.Sprintf("max_duration_seconds must be between %d and %d", constants.MinRecordingDuration, maxSeconds)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/services/api-gateway/middleware"
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/types"
+)
+
+// CreateStreamToken handles POST /recordings/:platform/:meeting_id/stream-token
+// Returns a short-lived token that opens this recording's status stream in place
+// of the API key, for clients that cannot set headers (EventSource, WebSocket).
+func (h *RecordingHandler) CreateStreamToken(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	platform := types.Platform(c.Params("platform"))
+	meetingID := c.Params("meeting_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	meeting, err := h.meetingRepo.GetByPlatformAndMeetingID(ctx, userID, platform, meetingID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": constants.ErrRecordingNotFound,
+		})
+	}
+
+	token, expiresAt := h.streamTokens.Issue(userID, meeting.ID)
+
+	return c.Status(201).JSON(types.StreamTokenResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// StreamRecording handles GET /recordings/:platform/:meeting_id/stream
+// Streams status updates as Server-Sent Events, or over a WebSocket when the
+// request is a WebSocket upgrade. The stream ends with an "end" event once the
+// recording reaches a terminal status. Authenticated by the API key header or a
+// stream token (?stream_token=) from CreateStreamToken.
+func (h *RecordingHandler) StreamRecording(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	platform := types.Platform(c.Params("platform"))
+	meetingID := c.Params("meeting_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Scoped to the caller - other users' recordings are not found
+	meeting, err := h.meetingRepo.GetByPlatformAndMeetingID(ctx, userID, platform, meetingID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": constants.ErrRecordingNotFound,
+		})
+	}
+
+	// Stream tokens only open the recording they were issued for (a later occurrence
+	// of the same meeting ID needs a new token)
+	if tokenMeetingID, ok := c.Locals(middleware.StreamMeetingIDLocal).(int64); ok && tokenMeetingID != meeting.ID {
+		return c.Status(403).JSON(fiber.Map{
+			"error": "Stream token was issued for another recording",
+		})
+	}
+
+	if websocket.IsWebSocketUpgrade(c) {
+		return websocket.New(func(conn *websocket.Conn) {
+			h.streamWebSocket(conn, meeting)
+		})(c)
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // Disable proxy buffering
+
+	conn := c.Context().Conn()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		h.streamSSE(w, conn, meeting)
+	})
+
+	return nil
+}
+
+// streamSSE writes recording events as Server-Sent Events
+func (h *RecordingHandler) streamSSE(w *bufio.Writer, conn net.Conn, meeting *types.Meeting) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := h.watchRecording(ctx, meeting, func(event types.RecordingStreamEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		// The server write timeout only covers the first write - extend it per event
+		conn.SetWriteDeadline(time.Now().Add(constants.StreamWriteTimeout))
+
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+			return err
+		}
+		return w.Flush()
+	})
+
+	logStreamEnd(meeting.ID, "sse", err)
+}
+
+// streamWebSocket writes recording events as JSON WebSocket messages
+func (h *RecordingHandler) streamWebSocket(conn *websocket.Conn, meeting *types.Meeting) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Clients do not send anything; reading only detects when they go away
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err := h.watchRecording(ctx, meeting, func(event types.RecordingStreamEvent) error {
+		conn.SetWriteDeadline(time.Now().Add(constants.StreamWriteTimeout))
+		return conn.WriteJSON(event)
+	})
+
+	if err == nil {
+		closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "recording finished")
+		conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+	}
+
+	logStreamEnd(meeting.ID, "websocket", err)
+}
+
+// watchRecording emits the current status, then bot status updates and heartbeats,
+// until the meeting reaches a terminal status (returns nil), ctx is cancelled or
+// emit fails. Bot updates arrive via Redis; the database is polled as well since
+// final statuses are set by bot-manager (after finalization) or by cancellation.
+func (h *RecordingHandler) watchRecording(ctx context.Context, meeting *types.Meeting, emit func(types.RecordingStreamEvent) error) error {
+	if err := emit(h.streamEvent(ctx, types.StreamEventStatus, meeting)); err != nil {
+		return err
+	}
+	if types.IsTerminalStatus(meeting.Status) {
+		return emit(h.streamEvent(ctx, types.StreamEventEnd, meeting))
+	}
+
+	updates := make(chan types.BotStatusUpdate, 16)
+	subscribed := false
+	subscribe := func(sessionID string) {
+		subscribed = true
+		go func() {
+			err := h.redisClient.SubscribeBotStatus(ctx, sessionID, func(update types.BotStatusUpdate) {
+				select {
+				case updates <- update:
+				case <-ctx.Done():
+				}
+			})
+			if err != nil && ctx.Err() == nil {
+				log.Warn().Err(err).Int64("meeting_id", meeting.ID).Msg("Recording stream subscription ended")
+			}
+		}()
+	}
+
+	if meeting.RecordingSessionID != nil && *meeting.RecordingSessionID != "" {
+		subscribe(*meeting.RecordingSessionID)
+	}
+
+	heartbeat := time.NewTicker(constants.StreamHeartbeatInterval)
+	defer heartbeat.Stop()
+	poll := time.NewTicker(constants.StreamPollInterval)
+	defer poll.Stop()
+
+	lastStatus := meeting.Status
+
+	// check reloads the meeting and emits any status change. Returns true once terminal.
+	check := func() (bool, error) {
+		queryCtx, cancel := context.WithTimeout(ctx, constants.DefaultQueryTimeout)
+		defer cancel()
+
+		current, err := h.meetingRepo.GetByID(queryCtx, meeting.ID)
+		if err != nil {
+			log.Warn().Err(err).Int64("meeting_id", meeting.ID).Msg("Failed to reload meeting for stream")
+			return false, nil
+		}
+
+		if !subscribed && current.RecordingSessionID != nil && *current.RecordingSessionID != "" {
+			subscribe(*current.RecordingSessionID)
+		}
+
+		if current.Status != lastStatus {
+			lastStatus = current.Status
+			if err := emit(h.streamEvent(ctx, types.StreamEventStatus, current)); err != nil {
+				return false, err
+			}
+		}
+
+		if types.IsTerminalStatus(current.Status) {
+			return true, emit(h.streamEvent(ctx, types.StreamEventEnd, current))
+		}
+		return false, nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case update := <-updates:
+			// The bot's final status is not the meeting's final status (finalization
+			// may still fail), so terminal updates only trigger a reload
+			if types.IsTerminalStatus(update.Status) {
+				if done, err := check(); done || err != nil {
+					return err
+				}
+				continue
+			}
+
+			lastStatus = update.Status
+			err := emit(types.RecordingStreamEvent{
+				Type:         types.StreamEventStatus,
+				MeetingID:    meeting.ID,
+				Status:       update.Status,
+				ChunkCount:   update.ChunkCount,
+				ErrorMessage: update.ErrorMessage,
+				Timestamp:    update.Timestamp,
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-poll.C:
+			if done, err := check(); done || err != nil {
+				return err
+			}
+
+		case <-heartbeat.C:
+			err := emit(types.RecordingStreamEvent{
+				Type:      types.StreamEventHeartbeat,
+				MeetingID: meeting.ID,
+				Timestamp: time.Now(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// streamEvent builds a stream event from the stored meeting
+func (h *RecordingHandler) streamEvent(ctx context.Context, eventType string, meeting *types.Meeting) types.RecordingStreamEvent {
+	event := types.RecordingStreamEvent{
+		Type:         eventType,
+		MeetingID:    meeting.ID,
+		Status:       meeting.Status,
+		ErrorMessage: meeting.ErrorMessage,
+		Timestamp:    time.Now(),
+		RecordingURL: h.recordingURL(ctx, meeting),
+	}
+
+	return event
+}
+
+// logStreamEnd logs why a recording stream ended
+func logStreamEnd(meetingID int64, transport string, err error) {
+	logger := log.With().Int64("meeting_id", meetingID).Str("transport", transport).Logger()
+	if err != nil {
+		logger.Debug().Err(err).Msg("Recording stream closed by client")
+		return
+	}
+	logger.Debug().Msg("Recording stream finished")
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/types"
+	"github.com/newar/insights/shared/webhooks"
+)
+
+type WebhookHandler struct {
+	webhookRepo *database.WebhookRepository
+}
+
+func NewWebhookHandler(webhookRepo *database.WebhookRepository) *WebhookHandler {
+	return &WebhookHandler{
+		webhookRepo: webhookRepo,
+	}
+}
+
+// CreateWebhook handles POST /webhooks
+// The signing secret is only returned in this response.
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	var req types.CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": constants.ErrBadRequest,
+		})
+	}
+
+	if err := webhooks.ValidateURL(req.URL); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	events, err := webhooks.ValidateEvents(req.Events)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count, err := h.webhookRepo.CountEndpoints(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("Failed to count webhooks")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+	if count >= constants.MaxWebhooksPerUser {
+		return c.Status(400).JSON(fiber.Map{
+			"error": constants.ErrMaxWebhooksReached,
+			"limit": constants.MaxWebhooksPerUser,
+		})
+	}
+
+	secret, err := webhooks.GenerateSecret()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate webhook secret")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	endpoint, err := h.webhookRepo.CreateEndpoint(ctx, userID, req.URL, secret, events, req.Description)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("Failed to create webhook")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	log.Info().
+		Int64("webhook_id", endpoint.ID).
+		Int64("user_id", userID).
+		Str("url", endpoint.URL).
+		Msg("Webhook created")
+
+	return c.Status(201).JSON(endpoint)
+}
+
+// ListWebhooks handles GET /webhooks
+func (h *WebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoints, err := h.webhookRepo.ListEndpoints(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("Failed to list webhooks")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	for _, endpoint := range endpoints {
+		endpoint.Secret = ""
+	}
+
+	return c.JSON(fiber.Map{
+		"webhooks": endpoints,
+		"total":    len(endpoints),
+	})
+}
+
+// GetWebhook handles GET /webhooks/:id
+func (h *WebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	endpoint, ok := h.ownedEndpoint(c)
+	if !ok {
+		return nil
+	}
+
+	endpoint.Secret = ""
+	return c.JSON(endpoint)
+}
+
+// UpdateWebhook handles PATCH /webhooks/:id
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	endpoint, ok := h.ownedEndpoint(c)
+	if !ok {
+		return nil
+	}
+
+	var req types.UpdateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": constants.ErrBadRequest,
+		})
+	}
+
+	if req.URL != nil {
+		if err := webhooks.ValidateURL(*req.URL); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	if req.Events != nil {
+		events, err := webhooks.ValidateEvents(req.Events)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		req.Events = events
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.webhookRepo.UpdateEndpoint(ctx, endpoint.ID, req); err != nil {
+		log.Error().Err(err).Int64("webhook_id", endpoint.ID).Msg("Failed to update webhook")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	updated, err := h.webhookRepo.GetEndpoint(ctx, endpoint.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	updated.Secret = ""
+	return c.JSON(updated)
+}
+
+// DeleteWebhook handles DELETE /webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	endpoint, ok := h.ownedEndpoint(c)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.webhookRepo.DeleteEndpoint(ctx, endpoint.ID); err != nil {
+		log.Error().Err(err).Int64("webhook_id", endpoint.ID).Msg("Failed to delete webhook")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	log.Info().Int64("webhook_id", endpoint.ID).Msg("Webhook deleted")
+
+	return c.JSON(fiber.Map{
+		"message": "Webhook deleted",
+	})
+}
+
+// ListDeliveries handles GET /webhooks/:id/deliveries
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	endpoint, ok := h.ownedEndpoint(c)
+	if !ok {
+		return nil
+	}
+
+	limit := c.QueryInt("limit", 20)
+	offset := c.QueryInt("offset", 0)
+
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	deliveries, total, err := h.webhookRepo.ListDeliveries(ctx, endpoint.ID, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Int64("webhook_id", endpoint.ID).Msg("Failed to list webhook deliveries")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(types.PaginatedResponse{
+		Data:   deliveries,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// Redeliver handles POST /webhooks/:id/deliveries/:delivery_id/redeliver
+func (h *WebhookHandler) Redeliver(c *fiber.Ctx) error {
+	endpoint, ok := h.ownedEndpoint(c)
+	if !ok {
+		return nil
+	}
+
+	deliveryID, err := c.ParamsInt("delivery_id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid delivery ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	delivery, err := h.webhookRepo.GetDelivery(ctx, int64(deliveryID))
+	if err != nil || delivery.EndpointID != endpoint.ID {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Delivery not found",
+		})
+	}
+
+	if err := h.webhookRepo.ResetDelivery(ctx, delivery.ID); err != nil {
+		log.Error().Err(err).Int64("delivery_id", delivery.ID).Msg("Failed to redeliver webhook")
+		return c.Status(500).JSON(fiber.Map{
+			"error": constants.ErrInternalServer,
+		})
+	}
+
+	return c.Status(202).JSON(fiber.Map{
+		"message": "Delivery queued",
+		"id":      delivery.ID,
+	})
+}
+
+// ownedEndpoint loads the :id endpoint, responding 404 unless it belongs to the caller.
+// Returns false once the error response has been written.
+func (h *WebhookHandler) ownedEndpoint(c *fiber.Ctx) (*types.WebhookEndpoint, bool) {
+	userID := c.Locals("user_id").(int64)
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		c.Status(400).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoint, err := h.webhookRepo.GetEndpoint(ctx, int64(id))
+	if err != nil || endpoint.UserID != userID {
+		c.Status(404).JSON(fiber.Map{
+			"error": constants.ErrWebhookNotFound,
+		})
+		return nil, false
+	}
+
+	return endpoint, true
+}
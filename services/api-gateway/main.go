@@ -5,10 +5,15 @@ import (
 
 	"github.com/newar/insights/services/api-gateway/handlers"
 	"github.com/newar/insights/services/api-gateway/middleware"
+	"github.com/newar/insights/shared/calendar"
+	"github.com/newar/insights/shared/constants"
 	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/events"
 	"github.com/newar/insights/shared/redis"
 	"github.com/newar/insights/shared/server"
+	"github.com/newar/insights/shared/storage"
 	"github.com/newar/insights/shared/utils"
+	"github.com/newar/insights/shared/webhooks"
 )
 
 func main() {
@@ -43,10 +48,49 @@ func main() {
 	tokenRepo := database.NewTokenRepository(db)
 	meetingRepo := database.NewMeetingRepository(db)
 	userRepo := database.NewUserRepository(db)
+	eventRepo := database.NewMeetingEventRepository(db)
+	fileRepo := database.NewRecordingFileRepository(db)
+	webhookRepo := database.NewWebhookRepository(db)
+	feedRepo := database.NewCalendarFeedRepository(db)
 
 	// Initialize handlers
 	botManagerURL := utils.GetEnvOrDefault("BOT_MANAGER_URL", "http://localhost:8082")
-	recordingHandler := handlers.NewRecordingHandler(meetingRepo, userRepo, botManagerURL)
+
+	// Spawns are queued for bot-manager via Redis Streams
+	spawnQueue := redisClient.NewJobQueue(constants.SpawnQueueStream, constants.SpawnQueueGroup)
+
+	// Webhook deliveries are queued here and sent by bot-manager
+	notifier := webhooks.NewNotifier(webhookRepo, meetingRepo)
+
+	// Lifecycle events are published on the global meeting:events channel
+	publisher := events.NewPublisher(redisClient)
+
+	// Recordings are downloaded from the storage backend the bot-manager stores them in,
+	// streamed through the gateway or (STORAGE_DOWNLOAD_REDIRECT) fetched from storage
+	// directly through a short-lived signed URL
+	recordingStorage, err := storage.NewStorage(utils.GetEnvOrDefault("STORAGE_TYPE", "local"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize storage")
+	}
+	redirectDownloads := utils.GetEnvOrDefaultBool("STORAGE_DOWNLOAD_REDIRECT", false)
+
+	// Status streams are opened with short-lived stream tokens, so API keys stay out of URLs
+	// (STREAM_TOKEN_SECRET must be shared by all gateway instances)
+	streamTokens := middleware.NewStreamTokens(utils.GetEnvOrDefault("STREAM_TOKEN_SECRET", ""), constants.StreamTokenTTL)
+
+	recordingHandler := handlers.NewRecordingHandler(meetingRepo, userRepo, eventRepo, fileRepo, redisClient, spawnQueue, notifier, publisher, recordingStorage, redirectDownloads, streamTokens, botManagerURL)
+	webhookHandler := handlers.NewWebhookHandler(webhookRepo)
+
+	// Calendar imports create scheduled recordings; feeds are re-synced by bot-manager
+	importer := calendar.NewImporter(meetingRepo, feedRepo, notifier, publisher)
+	calendarHandler := handlers.NewCalendarHandler(feedRepo, importer)
+
+	// Status stream (SSE or WebSocket) - accepts a stream token in place of the API key.
+	// Registered before the /recordings group so the group's API key auth doesn't run first.
+	builder.App().Get("/recordings/:platform/:meeting_id/stream",
+		middleware.StreamAuth(tokenRepo, streamTokens),
+		middleware.RateLimit(redisClient, cfg.RateLimit.RequestsPerMinute),
+		recordingHandler.StreamRecording)
 
 	// API routes (require API key + rate limiting)
 	api := builder.App().Group("/recordings")
@@ -56,10 +100,46 @@ func main() {
 	// Recording management
 	api.Post("/", recordingHandler.CreateRecording)
 	api.Get("/", recordingHandler.ListRecordings)
+
+	// Scheduled recordings (registered before /:platform/:meeting_id so "scheduled" is not a platform)
+	api.Get("/scheduled", recordingHandler.ListScheduledRecordings)
+	api.Patch("/scheduled/:id", recordingHandler.RescheduleRecording)
+	api.Delete("/scheduled/:id", recordingHandler.CancelScheduledRecording)
 	api.Get("/:platform/:meeting_id", recordingHandler.GetRecording)
+	api.Get("/:platform/:meeting_id/events", recordingHandler.GetRecordingEvents)
+	api.Post("/:platform/:meeting_id/stream-token", recordingHandler.CreateStreamToken)
 	api.Delete("/:platform/:meeting_id", recordingHandler.StopRecording)
 	api.Get("/:platform/:meeting_id/download", recordingHandler.DownloadRecording)
 
+	// Signed local storage URLs (authorized by their signature, not an API key)
+	if verifier, ok := recordingStorage.(storage.SignedURLVerifier); ok {
+		fileHandler := handlers.NewFileHandler(recordingStorage, verifier)
+		builder.App().Get(constants.SignedFilesPath+"/*", fileHandler.ServeFile)
+	}
+
+	// Webhook endpoints (same auth + rate limiting)
+	hooks := builder.App().Group("/webhooks")
+	hooks.Use(middleware.Auth(tokenRepo))
+	hooks.Use(middleware.RateLimit(redisClient, cfg.RateLimit.RequestsPerMinute))
+
+	hooks.Post("/", webhookHandler.CreateWebhook)
+	hooks.Get("/", webhookHandler.ListWebhooks)
+	hooks.Get("/:id", webhookHandler.GetWebhook)
+	hooks.Patch("/:id", webhookHandler.UpdateWebhook)
+	hooks.Delete("/:id", webhookHandler.DeleteWebhook)
+	hooks.Get("/:id/deliveries", webhookHandler.ListDeliveries)
+	hooks.Post("/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+
+	// Calendar import endpoints (same auth + rate limiting)
+	calendars := builder.App().Group("/calendars")
+	calendars.Use(middleware.Auth(tokenRepo))
+	calendars.Use(middleware.RateLimit(redisClient, cfg.RateLimit.RequestsPerMinute))
+
+	calendars.Post("/import", calendarHandler.ImportCalendar)
+	calendars.Get("/feeds", calendarHandler.ListFeeds)
+	calendars.Delete("/feeds/:id", calendarHandler.DeleteFeed)
+	calendars.Post("/feeds/:id/sync", calendarHandler.SyncFeed)
+
 	// Start server (blocks until shutdown)
 	builder.MustStart()
 }
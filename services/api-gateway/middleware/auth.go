@@ -12,49 +12,84 @@ import (
 	"github.com/newar/insights/shared/database"
 )
 
+// StreamMeetingIDLocal is set by StreamAuth to the recording a stream token was issued for
+const StreamMeetingIDLocal = "stream_meeting_id"
+
 // Auth validates user API keys and sets user_id in locals
 func Auth(tokenRepo *database.TokenRepository) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		apiKey := c.Get(constants.APIKeyHeader)
-		if apiKey == "" {
-			log.Warn().Msg("Missing API key in request")
-			return c.Status(401).JSON(fiber.Map{
-				"error": constants.ErrMissingAPIKey,
-			})
-		}
+		return authenticate(c, tokenRepo, c.Get(constants.APIKeyHeader))
+	}
+}
 
-		// Validate prefix
-		if !strings.HasPrefix(apiKey, constants.APIKeyPrefix) {
-			log.Warn().Str("prefix", apiKey[:min(10, len(apiKey))]).Msg("Invalid API key prefix")
-			return c.Status(401).JSON(fiber.Map{
-				"error": constants.ErrInvalidAPIKey,
-			})
+// StreamAuth authenticates status streams. Besides the API key header it accepts a
+// short-lived stream token as a query param (EventSource and browser WebSockets
+// cannot set headers), so permanent API keys never appear in URLs. Token requests
+// get user_id and StreamMeetingIDLocal set in locals.
+func StreamAuth(tokenRepo *database.TokenRepository, streamTokens *StreamTokens) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := c.Query(constants.StreamTokenQueryParam)
+		if c.Get(constants.APIKeyHeader) != "" || token == "" {
+			return authenticate(c, tokenRepo, c.Get(constants.APIKeyHeader))
 		}
 
-		// Get user ID from token
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-
-		userID, err := tokenRepo.GetUserIDByToken(ctx, apiKey)
+		userID, meetingID, err := streamTokens.Verify(token)
 		if err != nil {
-			log.Warn().
-				Err(err).
-				Str("api_key_preview", apiKey[:min(20, len(apiKey))]+"...").
-				Msg("Invalid API key")
+			log.Warn().Err(err).Msg("Invalid stream token")
 			return c.Status(401).JSON(fiber.Map{
-				"error": constants.ErrInvalidAPIKey,
+				"error": err.Error(),
 			})
 		}
 
-		// Store user ID in context for handlers
 		c.Locals("user_id", userID)
+		c.Locals(StreamMeetingIDLocal, meetingID)
 
-		log.Debug().Int64("user_id", userID).Msg("API authentication successful")
+		log.Debug().Int64("user_id", userID).Int64("meeting_id", meetingID).Msg("Stream token authentication successful")
 
 		return c.Next()
 	}
 }
 
+// authenticate validates an API key and sets user_id in locals
+func authenticate(c *fiber.Ctx, tokenRepo *database.TokenRepository, apiKey string) error {
+	if apiKey == "" {
+		log.Warn().Msg("Missing API key in request")
+		return c.Status(401).JSON(fiber.Map{
+			"error": constants.ErrMissingAPIKey,
+		})
+	}
+
+	// Validate prefix
+	if !strings.HasPrefix(apiKey, constants.APIKeyPrefix) {
+		log.Warn().Str("prefix", apiKey[:min(10, len(apiKey))]).Msg("Invalid API key prefix")
+		return c.Status(401).JSON(fiber.Map{
+			"error": constants.ErrInvalidAPIKey,
+		})
+	}
+
+	// Get user ID from token
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	userID, err := tokenRepo.GetUserIDByToken(ctx, apiKey)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("api_key_preview", apiKey[:min(20, len(apiKey))]+"...").
+			Msg("Invalid API key")
+		return c.Status(401).JSON(fiber.Map{
+			"error": constants.ErrInvalidAPIKey,
+		})
+	}
+
+	// Store user ID in context for handlers
+	c.Locals("user_id", userID)
+
+	log.Debug().Int64("user_id", userID).Msg("API authentication successful")
+
+	return c.Next()
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestStreamTokens(t *testing.T) {
+	tokens := NewStreamTokens("secret", time.Minute)
+	token, expiresAt := tokens.Issue(7, 42)
+
+	if time.Until(expiresAt) <= 0 || time.Until(expiresAt) > time.Minute {
+		t.Fatalf("expiresAt = %v, want within a minute", expiresAt)
+	}
+
+	userID, meetingID, err := tokens.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if userID != 7 || meetingID != 42 {
+		t.Errorf("Verify() = (%d, %d), want (7, 42)", userID, meetingID)
+	}
+
+	encoded, signature, _ := strings.Cut(token, ".")
+	otherKey, _ := NewStreamTokens("other", time.Minute).Issue(7, 42)
+	otherMeeting, _ := tokens.Issue(7, 43)
+	otherPayload, _, _ := strings.Cut(otherMeeting, ".")
+	expired, _ := NewStreamTokens("secret", -time.Minute).Issue(7, 42)
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"other key", otherKey, ErrInvalidStreamToken},
+		{"payload swapped", otherPayload + "." + signature, ErrInvalidStreamToken},
+		{"signature stripped", encoded, ErrInvalidStreamToken},
+		{"empty", "", ErrInvalidStreamToken},
+		{"not base64", "!!!." + signature, ErrInvalidStreamToken},
+		{"expired", expired, ErrStreamTokenExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tokens.Verify(tt.token); err != tt.want {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamAuthBypassesGroupAuth(t *testing.T) {
+	tokens := NewStreamTokens("secret", time.Minute)
+	token, _ := tokens.Issue(7, 42)
+
+	// Same layout as the gateway: the stream route is registered before the group's auth
+	app := fiber.New()
+	app.Get("/recordings/:platform/:meeting_id/stream", StreamAuth(nil, tokens), func(c *fiber.Ctx) error {
+		if c.Locals("user_id") != int64(7) || c.Locals(StreamMeetingIDLocal) != int64(42) {
+			return c.SendStatus(http.StatusInternalServerError)
+		}
+		return c.SendStatus(http.StatusOK)
+	})
+	api := app.Group("/recordings")
+	api.Use(func(c *fiber.Ctx) error { return c.SendStatus(http.StatusUnauthorized) })
+	api.Get("/:platform/:meeting_id", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"stream with token", "/recordings/google_meet/abc/stream?stream_token=" + token, http.StatusOK},
+		{"stream with bad token", "/recordings/google_meet/abc/stream?stream_token=bad", http.StatusUnauthorized},
+		{"other route with token", "/recordings/google_meet/abc?stream_token=" + token, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
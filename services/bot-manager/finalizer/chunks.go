@@ -0,0 +1,206 @@
+package finalizer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+)
+
+// ebmlMagic starts every WebM file (the EBML header element ID)
+var ebmlMagic = []byte{0x1A, 0x45, 0xDF, 0xA3}
+
+// segment is a playable WebM file assembled from consecutive chunks.
+// The bot records with a timesliced MediaRecorder, so only the first chunk of a
+// recorder session carries the WebM header; the chunks after it are continuations
+// of the same byte stream and cannot be demuxed on their own.
+type segment struct {
+	path   string
+	chunks []int64 // Chunk indices
+}
+
+// droppedChunk is a chunk left out of the final recording
+type droppedChunk struct {
+	index  int64
+	reason string
+}
+
+// prepareSegments validates the chunks and assembles them into segments in workDir.
+// Empty chunks, continuations without a preceding header and segments ffprobe
+// cannot read are dropped.
+func (f *Finalizer) prepareSegments(ctx context.Context, chunks []string, workDir string) ([]*segment, []droppedChunk, error) {
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+
+	segments := []*segment{}
+	dropped := []droppedChunk{}
+	var current *os.File
+
+	closeCurrent := func() error {
+		if current == nil {
+			return nil
+		}
+		err := current.Close()
+		current = nil
+		return err
+	}
+	defer closeCurrent()
+
+	for i, chunkPath := range chunks {
+		index := chunkIndex(chunkPath, i)
+
+		data, err := os.ReadFile(chunkPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read chunk %d: %w", index, err)
+		}
+
+		if len(data) == 0 {
+			dropped = append(dropped, droppedChunk{index: index, reason: "empty"})
+			continue
+		}
+
+		// A header starts a new recorder session
+		if bytes.HasPrefix(data, ebmlMagic) {
+			if err := closeCurrent(); err != nil {
+				return nil, nil, fmt.Errorf("failed to write segment: %w", err)
+			}
+
+			seg := &segment{path: filepath.Join(workDir, fmt.Sprintf("segment_%05d%s", len(segments), constants.FinalRecordingFormat))}
+			current, err = os.Create(seg.path)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to create segment: %w", err)
+			}
+			segments = append(segments, seg)
+		}
+
+		if current == nil {
+			dropped = append(dropped, droppedChunk{index: index, reason: "no WebM header before chunk"})
+			continue
+		}
+
+		if _, err := current.Write(data); err != nil {
+			return nil, nil, fmt.Errorf("failed to write segment: %w", err)
+		}
+		seg := segments[len(segments)-1]
+		seg.chunks = append(seg.chunks, index)
+	}
+
+	if err := closeCurrent(); err != nil {
+		return nil, nil, fmt.Errorf("failed to write segment: %w", err)
+	}
+
+	// Probe segments concurrently; recordings can have hundreds of them after bot restarts
+	valid := make([]bool, len(segments))
+	sem := make(chan struct{}, constants.ChunkValidateWorkers)
+	var wg sync.WaitGroup
+	for i, seg := range segments {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, seg *segment) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			valid[i] = validSegment(ctx, seg.path)
+		}(i, seg)
+	}
+	wg.Wait()
+
+	usable := []*segment{}
+	for i, seg := range segments {
+		if valid[i] {
+			usable = append(usable, seg)
+			continue
+		}
+		for _, index := range seg.chunks {
+			dropped = append(dropped, droppedChunk{index: index, reason: "unreadable audio"})
+		}
+	}
+
+	return usable, dropped, nil
+}
+
+// validSegment reports whether ffprobe can read audio packets from a segment
+func validSegment(ctx context.Context, path string) bool {
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-select_streams", "a:0",
+		"-count_packets",
+		"-show_entries", "stream=nb_read_packets",
+		"-of", "csv=p=0",
+		path,
+	)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+
+	packets, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	return err == nil && packets > 0
+}
+
+// writeConcatList writes an ffconcat list of the segments for the concat demuxer
+func writeConcatList(listPath string, segments []*segment) error {
+	var list strings.Builder
+	list.WriteString("ffconcat version 1.0\n")
+	for _, seg := range segments {
+		absPath, err := filepath.Abs(seg.path)
+		if err != nil {
+			return err
+		}
+		// Single quotes are escaped as '\'' inside a quoted path
+		fmt.Fprintf(&list, "file '%s'\n", strings.ReplaceAll(absPath, "'", `'\''`))
+	}
+
+	return os.WriteFile(listPath, []byte(list.String()), 0644)
+}
+
+// chunkIndex returns the index encoded in a chunk file name (chunk_00042.webm),
+// falling back to the chunk's position
+func chunkIndex(path string, position int) int64 {
+	var index int64
+	if _, err := fmt.Sscanf(filepath.Base(path), constants.ChunkFilePattern, &index); err != nil {
+		return int64(position)
+	}
+	return index
+}
+
+// runFFmpeg runs ffmpeg, logging its output on failure
+func runFFmpeg(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		log.Warn().
+			Err(err).
+			Str("ffmpeg_output", tail(output.String(), 2000)).
+			Msg("FFmpeg failed")
+		return fmt.Errorf("ffmpeg failed: %w", err)
+	}
+
+	log.Debug().
+		Str("ffmpeg_output", tail(output.String(), 2000)).
+		Msg("FFmpeg completed")
+
+	return nil
+}
+
+// tail returns the last n bytes of s
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
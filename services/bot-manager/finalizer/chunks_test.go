@@ -0,0 +1,101 @@
+package finalizer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/newar/insights/shared/constants"
+)
+
+func TestChunkIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		position int
+		want     int64
+	}{
+		{"file name", "chunk_00042.webm", 3, 42},
+		{"full path", "/data/recordings/7/chunk_00007.webm", 0, 7},
+		{"first chunk", "chunk_00000.webm", 5, 0},
+		{"unknown name", "recording.webm", 5, 5},
+		{"non-numeric index", "chunk_abcde.webm", 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chunkIndex(tt.path, tt.position); got != tt.want {
+				t.Errorf("chunkIndex(%q, %d) = %d, want %d", tt.path, tt.position, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareSegments(t *testing.T) {
+	chunkDir := t.TempDir()
+	workDir := filepath.Join(t.TempDir(), "work")
+
+	header := func(data string) []byte { return append(append([]byte{}, ebmlMagic...), data...) }
+
+	// Chunk indices are taken from the file names, so gaps are kept
+	contents := map[int64][]byte{
+		10: []byte("orphan"), // Continuation before any header
+		11: header("first"),
+		12: []byte("-cont"),
+		13: {},               // Empty
+		15: header("second"), // Bot restarted: a new recorder session
+		16: []byte("-cont"),
+	}
+
+	var chunks []string
+	for _, index := range []int64{10, 11, 12, 13, 15, 16} {
+		path := filepath.Join(chunkDir, fmt.Sprintf(constants.ChunkFilePattern, index))
+		if err := os.WriteFile(path, contents[index], 0644); err != nil {
+			t.Fatal(err)
+		}
+		chunks = append(chunks, path)
+	}
+
+	f := &Finalizer{}
+	usable, dropped, err := f.prepareSegments(context.Background(), chunks, workDir)
+	if err != nil {
+		t.Fatalf("prepareSegments() error = %v", err)
+	}
+
+	// Each header starts a segment holding it and the continuations after it
+	wantSegments := [][]byte{
+		append(header("first"), "-cont"...),
+		append(header("second"), "-cont"...),
+	}
+	for i, want := range wantSegments {
+		path := filepath.Join(workDir, fmt.Sprintf("segment_%05d%s", i, constants.FinalRecordingFormat))
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("segment %d: %v", i, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("segment %d = %q, want %q", i, got, want)
+		}
+	}
+
+	// The stub chunks hold no audio, so ffprobe rejects both segments
+	if len(usable) != 0 {
+		t.Errorf("usable segments = %d, want 0", len(usable))
+	}
+
+	want := []droppedChunk{
+		{index: 10, reason: "no WebM header before chunk"},
+		{index: 13, reason: "empty"},
+		{index: 11, reason: "unreadable audio"},
+		{index: 12, reason: "unreadable audio"},
+		{index: 15, reason: "unreadable audio"},
+		{index: 16, reason: "unreadable audio"},
+	}
+	if !reflect.DeepEqual(dropped, want) {
+		t.Errorf("dropped = %+v, want %+v", dropped, want)
+	}
+}
@@ -2,32 +2,55 @@ package finalizer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/storage"
+	"github.com/newar/insights/shared/types"
 )
 
-// Finalizer handles recording finalization (chunk concatenation)
+// ErrNoChunks is returned when a meeting has no uploaded chunks left to finalize
+var ErrNoChunks = errors.New("no chunks found")
+
+// Finalizer handles recording finalization (chunk concatenation).
+// Chunks are read from the configured storage backend, where the bots upload them,
+// and the finished recording is stored there. Recordings are assembled in a local
+// work directory under workPath.
 type Finalizer struct {
-	storagePath string
+	workPath          string
+	storage           storage.Storage
+	layout            storage.Layout
+	postProcessConfig PostProcessConfig
 }
 
 // NewFinalizer creates a new finalizer
-func NewFinalizer(storagePath string) *Finalizer {
+func NewFinalizer(workPath string, store storage.Storage, layout storage.Layout, postProcessConfig PostProcessConfig) *Finalizer {
 	return &Finalizer{
-		storagePath: storagePath,
+		workPath:          workPath,
+		storage:           store,
+		layout:            layout,
+		postProcessConfig: postProcessConfig,
 	}
 }
 
-// FinalizeRecording concatenates audio chunks into a single file
-func (f *Finalizer) FinalizeRecording(ctx context.Context, meetingID int64, containerID string) (string, error) {
+// FinalizeRecording concatenates audio chunks into a single file, post-processes it,
+// probes it for its media metadata and uploads it to storage. The returned file's
+// path is its storage path. Chunks and the local copy in the work directory are kept
+// until RemoveChunks, so renditions can be made from it and a failed finalization
+// can be retried.
+func (f *Finalizer) FinalizeRecording(ctx context.Context, meetingID int64, containerID string) (*types.RecordingFile, error) {
 	log.Info().
 		Int64("meeting_id", meetingID).
 		Str("container_id", containerID).
@@ -36,24 +59,15 @@ func (f *Finalizer) FinalizeRecording(ctx context.Context, meetingID int64, cont
 	start := time.Now()
 
 	// Paths
-	tempDir := filepath.Join(f.storagePath, constants.TempFolderPrefix, fmt.Sprintf("meeting_%d", meetingID))
-	finalDir := filepath.Join(f.storagePath, constants.FinalFolderPrefix)
-	finalFileName := fmt.Sprintf("meeting_%d_%s.webm", meetingID, time.Now().Format("20060102_150405"))
-	finalPath := filepath.Join(finalDir, finalFileName)
-
-	// Check if temp directory exists
-	if _, err := os.Stat(tempDir); os.IsNotExist(err) {
-		return "", fmt.Errorf("temp directory not found: %s", tempDir)
-	}
+	workDir := f.workDir(meetingID)
+	// Fixed per meeting, so a retried attempt overwrites the previous one's files
+	finalFileName := fmt.Sprintf("meeting_%d%s", meetingID, constants.FinalRecordingFormat)
+	finalPath := filepath.Join(workDir, finalFileName)
 
-	// List chunk files
-	chunks, err := f.listChunkFiles(tempDir)
+	// Get chunk files (downloaded first from remote storage)
+	chunks, err := f.fetchChunks(ctx, meetingID)
 	if err != nil {
-		return "", fmt.Errorf("failed to list chunks: %w", err)
-	}
-
-	if len(chunks) == 0 {
-		return "", fmt.Errorf("no chunks found in %s", tempDir)
+		return nil, err
 	}
 
 	log.Info().
@@ -61,60 +75,160 @@ func (f *Finalizer) FinalizeRecording(ctx context.Context, meetingID int64, cont
 		Int("chunk_count", len(chunks)).
 		Msg("Found chunks to concatenate")
 
-	// Ensure final directory exists
-	if err := os.MkdirAll(finalDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create final directory: %w", err)
+	// Concatenate chunks using the FFmpeg concat demuxer
+	// (into an intermediate file when post-processing writes the final file)
+	concatPath := finalPath
+	if f.postProcessConfig.Enabled() {
+		concatPath = filepath.Join(workDir, constants.ConcatOutputFileName)
 	}
 
-	// Concatenate chunks using FFmpeg concat protocol
-	if err := f.concatenateChunks(ctx, chunks, finalPath); err != nil {
-		return "", fmt.Errorf("failed to concatenate chunks: %w", err)
+	dropped, err := f.concatenateChunks(ctx, meetingID, chunks, workDir, concatPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to concatenate chunks: %w", err)
+	}
+
+	var processing []types.ProcessingStep
+	if concatPath != finalPath {
+		processing, err = f.postProcess(ctx, meetingID, concatPath, finalPath)
+		if err != nil {
+			// An unprocessed recording beats none
+			log.Warn().Err(err).Int64("meeting_id", meetingID).Msg("Post-processing failed, keeping unprocessed recording")
+			processing = nil
+		}
+		if processing == nil {
+			if err := os.Rename(concatPath, finalPath); err != nil {
+				return nil, fmt.Errorf("failed to move recording: %w", err)
+			}
+		}
 	}
 
 	// Verify final file exists
 	fileInfo, err := os.Stat(finalPath)
 	if err != nil {
-		return "", fmt.Errorf("final file not found after concatenation: %w", err)
+		return nil, fmt.Errorf("final file not found after concatenation: %w", err)
+	}
+
+	// Return the storage path for database storage
+	file := &types.RecordingFile{
+		MeetingID:     meetingID,
+		Path:          f.layout.RecordingPath(finalFileName),
+		Format:        strings.TrimPrefix(constants.FinalRecordingFormat, "."),
+		SizeBytes:     fileInfo.Size(),
+		DroppedChunks: dropped,
+		Processing:    processing,
+	}
+	f.applyMediaInfo(ctx, file, finalPath)
+
+	if err := f.store(ctx, finalPath, file); err != nil {
+		return nil, err
 	}
 
 	log.Info().
 		Int64("meeting_id", meetingID).
-		Str("final_path", finalPath).
-		Int64("file_size_bytes", fileInfo.Size()).
+		Str("path", file.Path).
+		Int64("file_size_bytes", file.SizeBytes).
+		Int("dropped_chunks", len(dropped)).
+		Int("processing_steps", len(processing)).
 		Int64("duration_ms", time.Since(start).Milliseconds()).
 		Msg("Recording finalized successfully")
 
-	// Clean up temp chunks
-	go func() {
-		time.Sleep(5 * time.Second) // Wait a bit before cleanup
-		if err := os.RemoveAll(tempDir); err != nil {
-			log.Warn().Err(err).Str("temp_dir", tempDir).Msg("Failed to clean up temp directory")
-		} else {
-			log.Info().Str("temp_dir", tempDir).Msg("Temp chunks cleaned up")
+	return file, nil
+}
+
+// CountChunks returns the number of chunks uploaded for a meeting
+func (f *Finalizer) CountChunks(ctx context.Context, meetingID int64) (int, error) {
+	chunks, err := f.listChunks(ctx, meetingID)
+	if err != nil {
+		return 0, err
+	}
+	return len(chunks), nil
+}
+
+// RemoveChunks deletes a meeting's uploaded chunks and its work directory once its
+// recording is finalized
+func (f *Finalizer) RemoveChunks(ctx context.Context, meetingID int64) {
+	chunks, err := f.listChunks(ctx, meetingID)
+	if err != nil {
+		log.Warn().Err(err).Int64("meeting_id", meetingID).Msg("Failed to list chunks for clean up")
+	}
+
+	removed := 0
+	for _, chunk := range chunks {
+		if err := f.storage.Delete(ctx, chunk); err != nil {
+			log.Warn().Err(err).Str("path", chunk).Msg("Failed to delete chunk")
+			continue
 		}
-	}()
+		removed++
+	}
 
-	// Return relative path for database storage
-	relativePath := filepath.Join(constants.FinalFolderPrefix, finalFileName)
-	return relativePath, nil
+	meetingDir := f.meetingWorkDir(meetingID)
+	if err := os.RemoveAll(meetingDir); err != nil {
+		log.Warn().Err(err).Str("work_dir", meetingDir).Msg("Failed to clean up work directory")
+	}
+
+	log.Info().
+		Int64("meeting_id", meetingID).
+		Int("chunks_removed", removed).
+		Msg("Chunks cleaned up")
+}
+
+// meetingWorkDir returns the local directory a meeting is finalized in
+func (f *Finalizer) meetingWorkDir(meetingID int64) string {
+	return filepath.Join(f.workPath, fmt.Sprintf("meeting_%d", meetingID))
+}
+
+// workDir returns the directory a meeting's recording is assembled in
+func (f *Finalizer) workDir(meetingID int64) string {
+	return filepath.Join(f.meetingWorkDir(meetingID), constants.SegmentWorkDir)
+}
+
+// store uploads a finished local file to its storage path
+func (f *Finalizer) store(ctx context.Context, localPath string, file *types.RecordingFile) error {
+	reader, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer reader.Close()
+
+	if _, err := f.storage.Upload(ctx, file.Path, reader, types.OutputFormat(file.Format).ContentType()); err != nil {
+		return fmt.Errorf("failed to upload recording: %w", err)
+	}
+	return nil
 }
 
-// listChunkFiles lists and sorts chunk files
-func (f *Finalizer) listChunkFiles(dir string) ([]string, error) {
-	files, err := os.ReadDir(dir)
+// applyMediaInfo fills in the file's media metadata from ffprobe.
+// A failed probe leaves the metadata empty rather than failing finalization.
+func (f *Finalizer) applyMediaInfo(ctx context.Context, file *types.RecordingFile, path string) {
+	probeCtx, cancel := context.WithTimeout(ctx, constants.ProbeTimeout)
+	defer cancel()
+
+	info, err := probe(probeCtx, path)
+	if err != nil {
+		log.Warn().Err(err).Int64("meeting_id", file.MeetingID).Str("path", path).Msg("Failed to probe recording")
+		return
+	}
+
+	file.DurationSeconds = info.Duration
+	file.Codec = info.Codec
+	file.SampleRate = info.SampleRate
+	file.Channels = info.Channels
+	file.Bitrate = info.Bitrate
+}
+
+// listChunks lists the storage paths of a meeting's chunks, sorted
+func (f *Finalizer) listChunks(ctx context.Context, meetingID int64) ([]string, error) {
+	dir := f.layout.ChunkDir(meetingID)
+
+	files, err := f.storage.List(ctx, dir)
 	if err != nil {
 		return nil, err
 	}
 
 	chunks := []string{}
 	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-
-		// Filter for .webm files
-		if filepath.Ext(file.Name()) == ".webm" {
-			chunks = append(chunks, filepath.Join(dir, file.Name()))
+		// Filter for .webm files directly in the chunk folder
+		if path.Dir(file) == dir && path.Ext(file) == ".webm" {
+			chunks = append(chunks, file)
 		}
 	}
 
@@ -124,53 +238,169 @@ func (f *Finalizer) listChunkFiles(dir string) ([]string, error) {
 	return chunks, nil
 }
 
-// concatenateChunks uses FFmpeg concat protocol to merge chunks
-func (f *Finalizer) concatenateChunks(ctx context.Context, chunks []string, outputPath string) error {
-	// Build concat input string: "concat:chunk_00000.webm|chunk_00001.webm|..."
-	concatInput := "concat:"
+// fetchChunks returns the local paths of a meeting's chunks, in order. Local
+// storage is read in place; remote chunks are downloaded into the work directory,
+// skipping those an earlier attempt already downloaded.
+func (f *Finalizer) fetchChunks(ctx context.Context, meetingID int64) ([]string, error) {
+	chunks, err := f.listChunks(ctx, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chunks: %w", err)
+	}
+
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoChunks, f.layout.ChunkDir(meetingID))
+	}
+
+	paths := make([]string, len(chunks))
+
+	if local, ok := f.storage.(storage.LocalFiles); ok {
+		for i, chunk := range chunks {
+			if paths[i], err = local.LocalPath(chunk); err != nil {
+				return nil, err
+			}
+		}
+		return paths, nil
+	}
+
+	downloadDir := filepath.Join(f.meetingWorkDir(meetingID), constants.ChunkDownloadDir)
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create download directory: %w", err)
+	}
+
+	start := time.Now()
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, constants.ChunkDownloadWorkers)
+	var wg sync.WaitGroup
 	for i, chunk := range chunks {
-		if i > 0 {
-			concatInput += "|"
+		paths[i] = filepath.Join(downloadDir, path.Base(chunk))
+		if _, err := os.Stat(paths[i]); err == nil {
+			continue
 		}
-		concatInput += chunk
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, chunk string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = f.downloadChunk(ctx, chunk, paths[i])
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("failed to download chunks: %w", err)
+	}
+
+	log.Info().
+		Int64("meeting_id", meetingID).
+		Int("chunk_count", len(chunks)).
+		Int64("duration_ms", time.Since(start).Milliseconds()).
+		Msg("Chunks downloaded from storage")
+
+	return paths, nil
+}
+
+// downloadChunk downloads one chunk; the file only appears once it is complete
+func (f *Finalizer) downloadChunk(ctx context.Context, chunk, localPath string) error {
+	reader, err := f.storage.Download(ctx, chunk)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	partPath := localPath + ".part"
+	file, err := os.Create(partPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, reader)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(partPath, localPath)
+	}
+	if err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("failed to download %s: %w", chunk, err)
+	}
+
+	return nil
+}
+
+// concatenateChunks validates the chunks, groups them into playable segments and
+// joins those with the FFmpeg concat demuxer. Stream copy is tried first; if it
+// fails the segments are re-encoded. Returns the indices of dropped chunks.
+func (f *Finalizer) concatenateChunks(ctx context.Context, meetingID int64, chunks []string, workDir, outputPath string) ([]int64, error) {
+	segments, dropped, err := f.prepareSegments(ctx, chunks, workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	droppedIndices := make([]int64, 0, len(dropped))
+	for _, chunk := range dropped {
+		log.Warn().
+			Int64("meeting_id", meetingID).
+			Int64("chunk_index", chunk.index).
+			Str("reason", chunk.reason).
+			Msg("Dropping chunk from recording")
+		droppedIndices = append(droppedIndices, chunk.index)
+	}
+	sort.Slice(droppedIndices, func(i, j int) bool { return droppedIndices[i] < droppedIndices[j] })
+
+	if len(segments) == 0 {
+		return droppedIndices, fmt.Errorf("no usable chunks (%d dropped)", len(dropped))
+	}
+
+	listPath := filepath.Join(workDir, constants.ConcatListFileName)
+	if err := writeConcatList(listPath, segments); err != nil {
+		return droppedIndices, fmt.Errorf("failed to write concat list: %w", err)
 	}
 
 	log.Debug().
-		Str("concat_input", concatInput[:min(100, len(concatInput))]+"...").
+		Int64("meeting_id", meetingID).
+		Int("segment_count", len(segments)).
+		Int("dropped_count", len(dropped)).
 		Str("output", outputPath).
 		Msg("Running FFmpeg concatenation")
 
-	// Run FFmpeg with concat protocol
-	// -i "concat:file1|file2|..." : Input using concat protocol
+	// -f concat -safe 0 : Read inputs from the list (absolute paths allowed)
 	// -c copy : Copy streams without re-encoding (fast)
 	// -y : Overwrite output file
-	cmd := exec.CommandContext(ctx, "ffmpeg",
-		"-i", concatInput,
+	err = runFFmpeg(ctx,
+		"-f", "concat",
+		"-safe", "0",
+		"-i", listPath,
 		"-c", "copy",
 		"-y",
 		outputPath,
 	)
-
-	// Capture output for debugging
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		log.Error().
-			Err(err).
-			Str("ffmpeg_output", string(output)).
-			Msg("FFmpeg concatenation failed")
-		return fmt.Errorf("ffmpeg failed: %w", err)
+	if err == nil {
+		return droppedIndices, nil
 	}
 
-	log.Debug().
-		Str("ffmpeg_output", string(output)).
-		Msg("FFmpeg concatenation completed")
-
-	return nil
-}
+	log.Warn().
+		Err(err).
+		Int64("meeting_id", meetingID).
+		Msg("Stream copy failed, re-encoding recording")
 
-func min(a, b int) int {
-	if a < b {
-		return a
+	// Re-encode audio, skipping packets the decoder rejects
+	err = runFFmpeg(ctx,
+		"-fflags", "+discardcorrupt",
+		"-err_detect", "ignore_err",
+		"-f", "concat",
+		"-safe", "0",
+		"-i", listPath,
+		"-vn",
+		"-c:a", "libopus",
+		"-b:a", strconv.Itoa(constants.DefaultAudioBitrate),
+		"-y",
+		outputPath,
+	)
+	if err != nil {
+		return droppedIndices, err
 	}
-	return b
+
+	return droppedIndices, nil
 }
@@ -0,0 +1,265 @@
+package finalizer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/types"
+	"github.com/newar/insights/shared/utils"
+)
+
+// PostProcessConfig selects the filters applied to a recording after concatenation.
+// Enabled steps run as one ffmpeg filter chain in this order: silence trim,
+// noise gate, mono downmix, loudness normalization.
+type PostProcessConfig struct {
+	TrimSilence        bool
+	SilenceThresholdDB float64 // Audio below this level counts as silence
+	SilenceMinSeconds  float64 // Only longer leading/trailing silences are trimmed
+
+	NoiseGate          bool
+	NoiseGateThreshold float64 // Linear level (0-1) below which audio is attenuated
+
+	Mono bool
+
+	Loudnorm       bool
+	LoudnessTarget float64 // Integrated loudness (LUFS)
+	TruePeak       float64 // Maximum true peak (dBTP)
+	LoudnessRange  float64 // Loudness range (LU)
+}
+
+// DefaultPostProcessConfig returns the default post-processing: EBU R128 loudness
+// normalization and silence trimming
+func DefaultPostProcessConfig() PostProcessConfig {
+	return PostProcessConfig{
+		TrimSilence:        true,
+		SilenceThresholdDB: -50,
+		SilenceMinSeconds:  2,
+		NoiseGateThreshold: 0.01,
+		Loudnorm:           true,
+		LoudnessTarget:     -16,
+		TruePeak:           -1.5,
+		LoudnessRange:      11,
+	}
+}
+
+// PostProcessConfigFromEnv loads the post-processing configuration from
+// POSTPROCESS_* environment variables, falling back to the defaults
+func PostProcessConfigFromEnv() PostProcessConfig {
+	def := DefaultPostProcessConfig()
+
+	return PostProcessConfig{
+		TrimSilence:        utils.GetEnvOrDefaultBool("POSTPROCESS_TRIM_SILENCE", def.TrimSilence),
+		SilenceThresholdDB: utils.GetEnvOrDefaultFloat("POSTPROCESS_SILENCE_THRESHOLD_DB", def.SilenceThresholdDB),
+		SilenceMinSeconds:  utils.GetEnvOrDefaultFloat("POSTPROCESS_SILENCE_MIN_SECONDS", def.SilenceMinSeconds),
+		NoiseGate:          utils.GetEnvOrDefaultBool("POSTPROCESS_NOISE_GATE", def.NoiseGate),
+		NoiseGateThreshold: utils.GetEnvOrDefaultFloat("POSTPROCESS_NOISE_GATE_THRESHOLD", def.NoiseGateThreshold),
+		Mono:               utils.GetEnvOrDefaultBool("POSTPROCESS_MONO", def.Mono),
+		Loudnorm:           utils.GetEnvOrDefaultBool("POSTPROCESS_LOUDNORM", def.Loudnorm),
+		LoudnessTarget:     utils.GetEnvOrDefaultFloat("POSTPROCESS_LOUDNESS_TARGET", def.LoudnessTarget),
+		TruePeak:           utils.GetEnvOrDefaultFloat("POSTPROCESS_TRUE_PEAK", def.TruePeak),
+		LoudnessRange:      utils.GetEnvOrDefaultFloat("POSTPROCESS_LOUDNESS_RANGE", def.LoudnessRange),
+	}
+}
+
+// Enabled reports whether any post-processing step is turned on
+func (c PostProcessConfig) Enabled() bool {
+	return c.TrimSilence || c.NoiseGate || c.Mono || c.Loudnorm
+}
+
+// postProcess applies the configured filter chain to inputPath, writing outputPath.
+// Returns the steps applied; no steps (and no output) if there was nothing to do.
+func (f *Finalizer) postProcess(ctx context.Context, meetingID int64, inputPath, outputPath string) ([]types.ProcessingStep, error) {
+	cfg := f.postProcessConfig
+	steps := []types.ProcessingStep{}
+
+	if cfg.TrimSilence {
+		step, err := f.trimSilenceStep(ctx, inputPath)
+		if err != nil {
+			// Keep the silence rather than lose the remaining steps
+			log.Warn().Err(err).Int64("meeting_id", meetingID).Msg("Silence detection failed, not trimming")
+		} else if step != nil {
+			steps = append(steps, *step)
+		}
+	}
+
+	if cfg.NoiseGate {
+		steps = append(steps, types.ProcessingStep{
+			Name:   "noise_gate",
+			Filter: fmt.Sprintf("agate=threshold=%g", cfg.NoiseGateThreshold),
+			Params: map[string]interface{}{"threshold": cfg.NoiseGateThreshold},
+		})
+	}
+
+	if cfg.Mono {
+		steps = append(steps, types.ProcessingStep{
+			Name:   "mono",
+			Filter: "aformat=channel_layouts=mono",
+		})
+	}
+
+	if cfg.Loudnorm {
+		// loudnorm upsamples to 192 kHz; Opus needs 48 kHz
+		steps = append(steps, types.ProcessingStep{
+			Name:   "loudnorm",
+			Filter: fmt.Sprintf("loudnorm=I=%g:TP=%g:LRA=%g,aresample=48000", cfg.LoudnessTarget, cfg.TruePeak, cfg.LoudnessRange),
+			Params: map[string]interface{}{
+				"integrated_lufs": cfg.LoudnessTarget,
+				"true_peak_db":    cfg.TruePeak,
+				"lra":             cfg.LoudnessRange,
+			},
+		})
+	}
+
+	if len(steps) == 0 {
+		return nil, nil
+	}
+
+	filters := make([]string, 0, len(steps))
+	for _, step := range steps {
+		filters = append(filters, step.Filter)
+	}
+	chain := strings.Join(filters, ",")
+
+	log.Debug().
+		Int64("meeting_id", meetingID).
+		Str("filter", chain).
+		Msg("Running post-processing")
+
+	err := runFFmpeg(ctx,
+		"-i", inputPath,
+		"-vn",
+		"-af", chain,
+		"-c:a", "libopus",
+		"-b:a", strconv.Itoa(constants.DefaultAudioBitrate),
+		"-y",
+		outputPath,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+// trimSilenceStep detects leading and trailing silence and returns an atrim step
+// cutting it, or nil if there is none. A recording that is silent throughout is
+// left alone rather than trimmed to nothing.
+func (f *Finalizer) trimSilenceStep(ctx context.Context, path string) (*types.ProcessingStep, error) {
+	cfg := f.postProcessConfig
+
+	info, err := probe(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Duration == nil {
+		return nil, fmt.Errorf("unknown recording duration")
+	}
+	duration := *info.Duration
+
+	silences, err := detectSilence(ctx, path, cfg.SilenceThresholdDB, cfg.SilenceMinSeconds)
+	if err != nil {
+		return nil, err
+	}
+	if len(silences) == 0 {
+		return nil, nil
+	}
+
+	// Edge tolerance - silencedetect timestamps are not sample-exact
+	const edge = 0.05
+
+	start := 0.0
+	if first := silences[0]; first.start <= edge {
+		if first.end < 0 {
+			return nil, nil // Silent throughout
+		}
+		start = first.end
+	}
+
+	end := 0.0
+	if last := silences[len(silences)-1]; last.end < 0 || last.end >= duration-edge {
+		if last.start > start {
+			end = last.start
+		}
+	}
+
+	if start == 0 && end == 0 {
+		return nil, nil
+	}
+
+	filter := fmt.Sprintf("atrim=start=%.3f", start)
+	params := map[string]interface{}{
+		"threshold_db":        cfg.SilenceThresholdDB,
+		"min_silence_seconds": cfg.SilenceMinSeconds,
+		"start_seconds":       start,
+	}
+	if end > 0 {
+		filter += fmt.Sprintf(":end=%.3f", end)
+		params["end_seconds"] = end
+	}
+
+	return &types.ProcessingStep{
+		Name:   "trim_silence",
+		Filter: filter + ",asetpts=PTS-STARTPTS",
+		Params: params,
+	}, nil
+}
+
+// silence is a silent interval reported by silencedetect (end is -1 if it runs to EOF)
+type silence struct {
+	start float64
+	end   float64
+}
+
+// detectSilence runs ffmpeg's silencedetect filter over a file
+func detectSilence(ctx context.Context, path string, thresholdDB, minSeconds float64) ([]silence, error) {
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner",
+		"-nostats",
+		"-i", path,
+		"-vn",
+		"-af", fmt.Sprintf("silencedetect=noise=%gdB:d=%g", thresholdDB, minSeconds),
+		"-f", "null",
+		"-",
+	)
+
+	var output bytes.Buffer
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("silencedetect failed: %w", err)
+	}
+
+	// [silencedetect @ 0x...] silence_start: 0
+	// [silencedetect @ 0x...] silence_end: 12.48 | silence_duration: 12.48
+	silences := []silence{}
+	scanner := bufio.NewScanner(&output)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if _, value, ok := strings.Cut(line, "silence_start: "); ok {
+			start, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err == nil {
+				silences = append(silences, silence{start: start, end: -1})
+			}
+			continue
+		}
+
+		if _, value, ok := strings.Cut(line, "silence_end: "); ok && len(silences) > 0 {
+			value, _, _ = strings.Cut(value, " ")
+			end, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				silences[len(silences)-1].end = end
+			}
+		}
+	}
+
+	return silences, nil
+}
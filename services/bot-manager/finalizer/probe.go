@@ -0,0 +1,103 @@
+package finalizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// mediaInfo is the metadata ffprobe reports for a recording
+type mediaInfo struct {
+	Duration   *float64 // seconds
+	Codec      *string
+	SampleRate *int // Hz
+	Channels   *int
+	Bitrate    *int64 // bits per second
+}
+
+// ffprobeOutput is the subset of `ffprobe -print_format json` output we use.
+// ffprobe reports most numbers as strings.
+type ffprobeOutput struct {
+	Streams []struct {
+		CodecType  string `json:"codec_type"`
+		CodecName  string `json:"codec_name"`
+		SampleRate string `json:"sample_rate"`
+		Channels   int    `json:"channels"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+		BitRate  string `json:"bit_rate"`
+	} `json:"format"`
+}
+
+// probe reads the duration and audio stream metadata of a media file with ffprobe.
+// Fields ffprobe cannot determine (e.g. the duration of a webm without cues) are nil.
+func probe(ctx context.Context, path string) (*mediaInfo, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		path,
+	)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var parsed ffprobeOutput
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	info := &mediaInfo{
+		Duration: parseFloat(parsed.Format.Duration),
+		Bitrate:  parseInt64(parsed.Format.BitRate),
+	}
+
+	for _, stream := range parsed.Streams {
+		if stream.CodecType != "audio" {
+			continue
+		}
+
+		codec := stream.CodecName
+		info.Codec = &codec
+		if sampleRate := parseInt64(stream.SampleRate); sampleRate != nil {
+			value := int(*sampleRate)
+			info.SampleRate = &value
+		}
+		if stream.Channels > 0 {
+			channels := stream.Channels
+			info.Channels = &channels
+		}
+		// The container bitrate includes overhead; prefer the stream's when known
+		if bitrate := parseInt64(stream.BitRate); bitrate != nil {
+			info.Bitrate = bitrate
+		}
+		break
+	}
+
+	return info, nil
+}
+
+// parseFloat parses an ffprobe number, returning nil for "N/A" or empty values
+func parseFloat(value string) *float64 {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed <= 0 {
+		return nil
+	}
+	return &parsed
+}
+
+// parseInt64 parses an ffprobe integer, returning nil for "N/A" or empty values
+func parseInt64(value string) *int64 {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || parsed <= 0 {
+		return nil
+	}
+	return &parsed
+}
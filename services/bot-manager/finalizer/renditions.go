@@ -0,0 +1,102 @@
+package finalizer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/types"
+)
+
+// renditionCodecArgs are the ffmpeg encoder arguments for each extra output format.
+// The primary recording is Opus in WebM, so Ogg only needs a remux.
+var renditionCodecArgs = map[types.OutputFormat][]string{
+	types.OutputFormatMP3: {"-c:a", "libmp3lame", "-b:a", strconv.Itoa(constants.DefaultAudioBitrate)},
+	types.OutputFormatM4A: {"-c:a", "aac", "-b:a", strconv.Itoa(constants.DefaultAudioBitrate), "-movflags", "+faststart"},
+	types.OutputFormatWAV: {"-c:a", "pcm_s16le"},
+	types.OutputFormatOGG: {"-c:a", "copy"},
+}
+
+// CreateRenditions transcodes a finalized recording into the given output formats
+// and stores them next to it, using the local copy FinalizeRecording left in the
+// work directory. The primary format is skipped. A rendition that fails is logged
+// and left out; the primary file is never affected.
+func (f *Finalizer) CreateRenditions(ctx context.Context, primary *types.RecordingFile, formats []string) []*types.RecordingFile {
+	renditions := []*types.RecordingFile{}
+	inputPath := filepath.Join(f.workDir(primary.MeetingID), path.Base(primary.Path))
+
+	for _, name := range formats {
+		format := types.OutputFormat(name)
+		if name == primary.Format {
+			continue
+		}
+
+		codecArgs, ok := renditionCodecArgs[format]
+		if !ok {
+			log.Warn().Int64("meeting_id", primary.MeetingID).Str("format", name).Msg("Skipping unsupported output format")
+			continue
+		}
+
+		file, err := f.createRendition(ctx, primary, inputPath, format, codecArgs)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Int64("meeting_id", primary.MeetingID).
+				Str("format", name).
+				Msg("Failed to create rendition")
+			continue
+		}
+
+		renditions = append(renditions, file)
+	}
+
+	return renditions
+}
+
+// createRendition transcodes inputPath into one output format next to the primary file
+func (f *Finalizer) createRendition(ctx context.Context, primary *types.RecordingFile, inputPath string, format types.OutputFormat, codecArgs []string) (*types.RecordingFile, error) {
+	relPath := strings.TrimSuffix(primary.Path, path.Ext(primary.Path)) + format.Extension()
+	outputPath := filepath.Join(filepath.Dir(inputPath), path.Base(relPath))
+
+	args := append([]string{"-i", inputPath, "-vn"}, codecArgs...)
+	args = append(args, "-y", outputPath)
+
+	if err := runFFmpeg(ctx, args...); err != nil {
+		os.Remove(outputPath)
+		return nil, err
+	}
+
+	fileInfo, err := os.Stat(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("rendition not found after transcoding: %w", err)
+	}
+
+	file := &types.RecordingFile{
+		MeetingID:  primary.MeetingID,
+		Path:       relPath,
+		Format:     string(format),
+		SizeBytes:  fileInfo.Size(),
+		Processing: primary.Processing, // Transcoded from the processed file
+	}
+	f.applyMediaInfo(ctx, file, outputPath)
+
+	if err := f.store(ctx, outputPath, file); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Int64("meeting_id", primary.MeetingID).
+		Str("format", string(format)).
+		Str("path", relPath).
+		Int64("size_bytes", file.SizeBytes).
+		Msg("Rendition created")
+
+	return file, nil
+}
@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
 
 	"github.com/newar/insights/services/bot-manager/interfaces"
+	"github.com/newar/insights/services/bot-manager/spawner"
 	"github.com/newar/insights/shared/constants"
 	"github.com/newar/insights/shared/types"
 )
@@ -15,20 +17,19 @@ import (
 type BotHandler struct {
 	orchestrator interfaces.BotOrchestrator
 	listener     interfaces.BotListener
-	meetingRepo  interfaces.MeetingRepository
-	userRepo     interfaces.UserRepository
+	spawner      *spawner.Spawner
 }
 
-func NewBotHandler(orchestrator interfaces.BotOrchestrator, listener interfaces.BotListener, meetingRepo interfaces.MeetingRepository, userRepo interfaces.UserRepository) *BotHandler {
+func NewBotHandler(orchestrator interfaces.BotOrchestrator, listener interfaces.BotListener, spawner *spawner.Spawner) *BotHandler {
 	return &BotHandler{
 		orchestrator: orchestrator,
 		listener:     listener,
-		meetingRepo:  meetingRepo,
-		userRepo:     userRepo,
+		spawner:      spawner,
 	}
 }
 
 // SpawnBot handles POST /bots/spawn
+// Spawns normally arrive through the spawn queue; this endpoint is kept for manual and internal use.
 func (h *BotHandler) SpawnBot(c *fiber.Ctx) error {
 	var req types.SpawnBotRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -41,26 +42,22 @@ func (h *BotHandler) SpawnBot(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Get meeting from database by ID
-	meeting, err := h.meetingRepo.GetByID(ctx, req.MeetingID)
+	meeting, err := h.spawner.Spawn(ctx, req)
 	if err != nil {
-		log.Error().Err(err).Int64("meeting_id", req.MeetingID).Msg("Meeting not found")
-		return c.Status(404).JSON(fiber.Map{"error": "Meeting not found"})
-	}
-
-	// Get user from database
-	user, err := h.userRepo.GetByID(ctx, req.UserID)
-	if err != nil {
-		log.Error().Err(err).Int64("user_id", req.UserID).Msg("User not found")
-		return c.Status(404).JSON(fiber.Map{"error": "User not found"})
-	}
-
-	// Spawn bot container (orchestrator updates meeting internally)
-	if err := h.orchestrator.SpawnBot(ctx, meeting, user); err != nil {
 		log.Error().
 			Err(err).
 			Int64("meeting_id", req.MeetingID).
-			Msg("Failed to spawn bot container")
+			Msg("Failed to spawn bot")
+
+		switch {
+		case errors.Is(err, spawner.ErrMeetingNotFound):
+			return c.Status(404).JSON(fiber.Map{"error": "Meeting not found"})
+		case errors.Is(err, spawner.ErrUserNotFound):
+			return c.Status(404).JSON(fiber.Map{"error": "User not found"})
+		case errors.Is(err, spawner.ErrNotAwaitingBot):
+			return c.Status(409).JSON(fiber.Map{"error": "Meeting is not awaiting a bot"})
+		}
+
 		return c.Status(500).JSON(fiber.Map{
 			"error": "Failed to spawn bot",
 			"details": fiber.Map{
@@ -69,39 +66,14 @@ func (h *BotHandler) SpawnBot(c *fiber.Ctx) error {
 		})
 	}
 
-	// Fetch updated meeting to get recording_session_id
-	updatedMeeting, err := h.meetingRepo.GetByID(ctx, req.MeetingID)
-	if err != nil {
-		log.Error().Err(err).Int64("meeting_id", req.MeetingID).Msg("Failed to fetch updated meeting")
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch meeting after spawn"})
-	}
-
 	sessionID := ""
-	if updatedMeeting.RecordingSessionID != nil {
-		sessionID = *updatedMeeting.RecordingSessionID
-	}
-
-	// Start listening for status updates from this recording session
-	if sessionID != "" {
-		go func() {
-			listenerCtx := context.Background() // Long-lived context
-			if err := h.listener.ListenForContainer(listenerCtx, sessionID); err != nil {
-				log.Error().
-					Err(err).
-					Str("session_id", sessionID).
-					Msg("Status listener stopped")
-			}
-		}()
+	if meeting.RecordingSessionID != nil {
+		sessionID = *meeting.RecordingSessionID
 	}
 
-	log.Info().
-		Int64("meeting_id", req.MeetingID).
-		Str("session_id", sessionID).
-		Msg("Bot spawned successfully")
-
 	return c.Status(201).JSON(types.SpawnBotResponse{
 		ContainerID: sessionID,
-		Status:      string(updatedMeeting.Status),
+		Status:      string(meeting.Status),
 	})
 }
 
@@ -129,3 +101,11 @@ func (h *BotHandler) StopBot(c *fiber.Ctx) error {
 		"message": constants.MsgRecordingStopped,
 	})
 }
+
+// GetListeners handles GET /bots/listeners
+func (h *BotHandler) GetListeners(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"active_listeners": h.listener.ActiveListeners(),
+		"dispatcher":       h.listener.DispatchStats(),
+	})
+}
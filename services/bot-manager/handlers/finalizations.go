@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/services/bot-manager/finalizer"
+	"github.com/newar/insights/services/bot-manager/orchestrator"
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/database"
+)
+
+type FinalizationHandler struct {
+	worker  *orchestrator.FinalizationWorker
+	jobRepo *database.FinalizationJobRepository
+}
+
+func NewFinalizationHandler(worker *orchestrator.FinalizationWorker, jobRepo *database.FinalizationJobRepository) *FinalizationHandler {
+	return &FinalizationHandler{
+		worker:  worker,
+		jobRepo: jobRepo,
+	}
+}
+
+// GetFinalization handles GET /finalizations/{meeting_id}
+func (h *FinalizationHandler) GetFinalization(c *fiber.Ctx) error {
+	meetingID, err := c.ParamsInt("meeting_id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid meeting ID"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	job, err := h.jobRepo.GetByMeeting(ctx, int64(meetingID))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": constants.ErrNotFound})
+	}
+
+	return c.JSON(job)
+}
+
+// RetryFinalization handles POST /finalizations/{meeting_id}/retry
+// Re-runs the finalization of a failed (or stuck finalizing) recording whose chunks still exist.
+func (h *FinalizationHandler) RetryFinalization(c *fiber.Ctx) error {
+	meetingID, err := c.ParamsInt("meeting_id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid meeting ID"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	err = h.worker.Rerun(ctx, int64(meetingID))
+	switch {
+	case err == nil:
+	case errors.Is(err, orchestrator.ErrMeetingNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Meeting not found"})
+	case errors.Is(err, orchestrator.ErrFinalizationActive):
+		return c.Status(409).JSON(fiber.Map{"error": "Finalization is already queued"})
+	case errors.Is(err, orchestrator.ErrNotFinalizable):
+		return c.Status(409).JSON(fiber.Map{"error": constants.ErrCannotFinalize})
+	case errors.Is(err, finalizer.ErrNoChunks):
+		return c.Status(410).JSON(fiber.Map{"error": "Recording chunks are no longer available"})
+	default:
+		log.Error().Err(err).Int("meeting_id", meetingID).Msg("Failed to re-run finalization")
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to queue finalization",
+		})
+	}
+
+	log.Info().Int("meeting_id", meetingID).Msg("Finalization re-run queued")
+
+	return c.Status(202).JSON(fiber.Map{
+		"meeting_id": meetingID,
+		"status":     "queued",
+	})
+}
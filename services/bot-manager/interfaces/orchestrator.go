@@ -3,6 +3,7 @@ package interfaces
 import (
 	"context"
 
+	"github.com/newar/insights/shared/redis"
 	"github.com/newar/insights/shared/types"
 )
 
@@ -13,11 +14,15 @@ import (
 // Implementations: orchestrator.DockerOrchestrator
 type BotOrchestrator interface {
 	// SpawnBot creates and starts a new recording bot container
+	// On success, meeting.BotContainerID, meeting.RecordingSessionID and meeting.BotHost are set
 	SpawnBot(ctx context.Context, meeting *types.Meeting, user *types.User) error
 
 	// StopBot stops and removes a recording bot container
 	StopBot(ctx context.Context, sessionID string) error
 
+	// RemoveBot force-removes a bot container, stopping it if it is running
+	RemoveBot(ctx context.Context, containerID string) error
+
 	// GetBotStatus retrieves the current status of a bot
 	GetBotStatus(ctx context.Context, sessionID string) (*types.BotStatusUpdate, error)
 }
@@ -30,12 +35,39 @@ type BotListener interface {
 	// This runs in a long-lived goroutine and should handle context cancellation
 	ListenForContainer(ctx context.Context, containerID string) error
 
-	// StartListening begins listening for status updates from a bot (legacy compatibility)
+	// StartListening begins listening for status updates from a bot in the background
+	// Listeners stop on their own once a terminal status arrives
 	StartListening(sessionID string, meetingID int64)
 
 	// StopListening stops listening for status updates from a bot
 	// This should be called when a recording completes or fails
 	StopListening(sessionID string)
+
+	// ActiveListeners returns the number of running status listeners
+	ActiveListeners() int
+
+	// DispatchStats returns delivered/dropped/unknown counters for routed status updates
+	DispatchStats() redis.DispatcherStats
+}
+
+// StatusNotifier defines operations for announcing meeting status changes to webhooks.
+//
+// Implementations: webhooks.Notifier
+type StatusNotifier interface {
+	// Notify queues webhook deliveries for a meeting's new status
+	// Failures are logged, never returned, so they cannot affect the status change
+	Notify(ctx context.Context, meetingID int64, status types.MeetingStatus)
+}
+
+// EventPublisher defines operations for publishing meeting events on the event bus.
+//
+// Implementations: events.Publisher
+type EventPublisher interface {
+	// Publish publishes an event with the given payload (best-effort, never fails)
+	Publish(ctx context.Context, eventType types.MeetingEventType, meetingID, userID int64, payload interface{})
+
+	// PublishStatusChanged publishes a meeting.status_changed event
+	PublishStatusChanged(ctx context.Context, meetingID, userID int64, status types.MeetingStatus, errorMsg *string, chunkCount int)
 }
 
 // MeetingRepository defines operations for managing meetings/recordings.
@@ -53,8 +85,16 @@ type MeetingRepository interface {
 	Update(ctx context.Context, filter types.MeetingFilter, update types.MeetingUpdate) error
 
 	// UpdateStatus updates only the status of a meeting
+	// Illegal transitions are rejected with *database.InvalidTransitionError
 	UpdateStatus(ctx context.Context, meetingID int64, status types.MeetingStatus, recordingPath *string, errorMsg *string, recordingDuration *int) error
 
+	// AttachBot stores a spawned bot's container, session and host IDs if the meeting
+	// is still awaiting a bot; returns false if another spawn got there first
+	AttachBot(ctx context.Context, id int64, containerID, sessionID, botHost string) (bool, error)
+
+	// RecordSpawnAttempt increments the spawn attempt counter and stores the last spawn error
+	RecordSpawnAttempt(ctx context.Context, id int64, lastError *string) (int, error)
+
 	// GetActiveRecordings retrieves all recordings in active states
 	// Used by reconciliation logic to re-attach listeners on restart
 	GetActiveRecordings(ctx context.Context) ([]*types.Meeting, error)
@@ -1,15 +1,24 @@
 package main
 
 import (
+	"context"
+	"os"
+
 	"github.com/rs/zerolog/log"
 
 	"github.com/newar/insights/services/bot-manager/finalizer"
 	"github.com/newar/insights/services/bot-manager/handlers"
 	"github.com/newar/insights/services/bot-manager/orchestrator"
+	"github.com/newar/insights/services/bot-manager/spawner"
+	"github.com/newar/insights/shared/calendar"
+	"github.com/newar/insights/shared/constants"
 	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/events"
 	"github.com/newar/insights/shared/redis"
 	"github.com/newar/insights/shared/server"
+	"github.com/newar/insights/shared/storage"
 	"github.com/newar/insights/shared/utils"
+	"github.com/newar/insights/shared/webhooks"
 )
 
 func main() {
@@ -38,14 +47,20 @@ func main() {
 
 	// Initialize Docker orchestrator
 	botImage := utils.GetEnvOrDefault("BOT_IMAGE", "newar-recording-bot:latest")
-	storageType := utils.GetEnvOrDefault("STORAGE_TYPE", "local")
-	storagePath := utils.GetEnvOrDefault("STORAGE_PATH", "./storage/recordings")
+	// Bots upload chunks to the same storage backend the recordings are finalized in
+	storageConfig, err := storage.ConfigFromEnv(utils.GetEnvOrDefault("STORAGE_TYPE", "local"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid storage configuration")
+	}
+	storageLayout := storage.LayoutFromEnv()
 
+	// Meetings record the Docker host their bot runs on, so with several replicas
+	// each one only reconciles and reaps its own bots (defaults to the daemon ID)
 	dockerOrch, err := orchestrator.NewDockerOrchestrator(
 		botImage,
 		cfg.Redis.URL,
-		storageType,
-		storagePath,
+		storageConfig.BotEnv(storageLayout),
+		utils.GetEnvOrDefault("BOT_MANAGER_HOST_ID", ""),
 	)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize Docker orchestrator")
@@ -60,19 +75,119 @@ func main() {
 	// Initialize repositories
 	meetingRepo := database.NewMeetingRepository(db)
 	userRepo := database.NewUserRepository(db)
+	eventRepo := database.NewMeetingEventRepository(db)
+	fileRepo := database.NewRecordingFileRepository(db)
+	webhookRepo := database.NewWebhookRepository(db)
+	feedRepo := database.NewCalendarFeedRepository(db)
+
+	// Queue webhook deliveries on status changes and send them in the background
+	notifier := webhooks.NewNotifier(webhookRepo, meetingRepo)
+	webhookWorker := webhooks.NewWorker(webhookRepo, builder.Metrics())
+	webhookCtx, stopWebhooks := context.WithCancel(context.Background())
+	go webhookWorker.Run(webhookCtx)
+	builder.Shutdown().Register("webhook_worker", stopWebhooks)
+
+	// Lifecycle events are published on the global meeting:events channel
+	publisher := events.NewPublisher(redisClient)
+
+	recordingStorage, err := storage.New(storageConfig)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize storage")
+	}
 
 	// Initialize finalizer
-	fin := finalizer.NewFinalizer(storagePath)
+	// Recordings are assembled locally in FINALIZE_WORK_DIR; post-processing filters
+	// are configured with POSTPROCESS_* variables
+	workPath := utils.GetEnvOrDefault("FINALIZE_WORK_DIR", "./storage/work")
+	fin := finalizer.NewFinalizer(workPath, recordingStorage, storageLayout, finalizer.PostProcessConfigFromEnv())
+
+	// Receive status updates for all bots over a single subscription
+	dispatcher := redisClient.NewStatusDispatcher(builder.Metrics())
+	dispatcherCtx, stopDispatcher := context.WithCancel(context.Background())
+	if err := dispatcher.Start(dispatcherCtx); err != nil {
+		log.Fatal().Err(err).Msg("Failed to start status dispatcher")
+	}
+	builder.Shutdown().Register("status_dispatcher", stopDispatcher)
+
+	consumerName, err := os.Hostname()
+	if err != nil {
+		consumerName = "bot-manager"
+	}
+
+	// Finalize recordings in the background, retried with backoff
+	finalizeQueue := redisClient.NewJobQueue(constants.FinalizeQueueStream, constants.FinalizeQueueGroup)
+	finalizationJobRepo := database.NewFinalizationJobRepository(db)
+	finalizationWorker := orchestrator.NewFinalizationWorker(finalizeQueue, finalizationJobRepo, meetingRepo, userRepo, eventRepo, fileRepo, fin, notifier, publisher)
+	finalizeCtx, stopFinalize := context.WithCancel(context.Background())
+	go func() {
+		if err := finalizationWorker.Consume(finalizeCtx, consumerName); err != nil && err != context.Canceled {
+			log.Error().Err(err).Msg("Finalization queue consumer stopped")
+		}
+	}()
+	// Jobs can run for hours - don't wait for them; unacknowledged jobs are retried after restart
+	builder.Shutdown().Register("finalize_consumer", stopFinalize)
 
 	// Initialize status listener
-	statusListener := orchestrator.NewStatusListener(redisClient, meetingRepo, fin)
+	statusListener := orchestrator.NewStatusListener(dispatcher, meetingRepo, eventRepo, finalizationWorker, notifier, publisher)
+	builder.Shutdown().Register("status_listeners", statusListener.Drain)
+
+	// Re-attach listeners and resolve meetings whose bots exited while we were down
+	reconciler := orchestrator.NewReconciler(dockerOrch, statusListener, meetingRepo)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), constants.ReconcileTimeout)
+		defer cancel()
+		if _, err := reconciler.Reconcile(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to reconcile active recordings")
+		}
+	}()
+
+	// Periodically clean up stuck meetings and leftover bot containers
+	reaper := orchestrator.NewReaper(dockerOrch, statusListener, meetingRepo, userRepo, redisClient, builder.Metrics())
+	reaperCtx, stopReaper := context.WithCancel(context.Background())
+	go reaper.Run(reaperCtx)
+	builder.Shutdown().Register("reaper", stopReaper)
+
+	// Initialize spawner and consume the spawn queue
+	botSpawner := spawner.NewSpawner(dockerOrch, statusListener, meetingRepo, userRepo, notifier, publisher)
+
+	spawnQueue := redisClient.NewJobQueue(constants.SpawnQueueStream, constants.SpawnQueueGroup)
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
+	consumerDone := make(chan struct{})
+	go func() {
+		defer close(consumerDone)
+		if err := botSpawner.Consume(consumerCtx, spawnQueue, consumerName); err != nil && err != context.Canceled {
+			log.Error().Err(err).Msg("Spawn queue consumer stopped")
+		}
+	}()
+	builder.Shutdown().Register("spawn_consumer", func() {
+		stopConsumer()
+		<-consumerDone
+	})
+
+	// Release scheduled recordings onto the spawn queue when they are due
+	scheduler := spawner.NewScheduler(meetingRepo, spawnQueue, notifier, publisher)
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	go scheduler.Run(schedulerCtx)
+	builder.Shutdown().Register("scheduler", stopScheduler)
+
+	// Re-sync calendar feeds so new, moved and removed events reach the schedule
+	calendarSync := calendar.NewImporter(meetingRepo, feedRepo, notifier, publisher)
+	calendarCtx, stopCalendarSync := context.WithCancel(context.Background())
+	go calendarSync.Run(calendarCtx)
+	builder.Shutdown().Register("calendar_sync", stopCalendarSync)
 
 	// Initialize handlers
-	botHandler := handlers.NewBotHandler(dockerOrch, statusListener, meetingRepo, userRepo)
+	botHandler := handlers.NewBotHandler(dockerOrch, statusListener, botSpawner)
+	finalizationHandler := handlers.NewFinalizationHandler(finalizationWorker, finalizationJobRepo)
 
 	// Bot management endpoints
 	builder.App().Post("/bots/spawn", botHandler.SpawnBot)
 	builder.App().Post("/bots/:container_id/stop", botHandler.StopBot)
+	builder.App().Get("/bots/listeners", botHandler.GetListeners)
+
+	// Finalization endpoints
+	builder.App().Get("/finalizations/:meeting_id", finalizationHandler.GetFinalization)
+	builder.App().Post("/finalizations/:meeting_id/retry", finalizationHandler.RetryFinalization)
 
 	// Start server (blocks until shutdown)
 	builder.MustStart()
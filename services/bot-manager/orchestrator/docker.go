@@ -2,11 +2,15 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/rs/zerolog/log"
 
@@ -14,33 +18,64 @@ import (
 	"github.com/newar/insights/shared/types"
 )
 
+// MeetingIDLabel is the container label linking a bot container to its meeting
+const MeetingIDLabel = "newar.meeting_id"
+
+// ErrBotNotFound is returned when a bot container does not exist
+var ErrBotNotFound = errors.New("bot container not found")
+
 // DockerOrchestrator manages Docker container lifecycle for recording bots
 type DockerOrchestrator struct {
 	client  *client.Client
 	botImage string
 	redisURL string
-	storageType string
-	storagePath string
+	storageEnv []string // Storage settings for the bots' chunk uploads
+	hostID   string     // Identifies the Docker host the bots run on (meetings.bot_host)
 }
 
-// NewDockerOrchestrator creates a new Docker orchestrator
-func NewDockerOrchestrator(botImage, redisURL, storageType, storagePath string) (*DockerOrchestrator, error) {
+// NewDockerOrchestrator creates a new Docker orchestrator.
+// hostID identifies the Docker host in meetings.bot_host; if empty, the Docker
+// daemon ID is used, so bot-manager replicas sharing a daemon share its bots.
+func NewDockerOrchestrator(botImage, redisURL string, storageEnv []string, hostID string) (*DockerOrchestrator, error) {
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client: %w", err)
 	}
 
-	log.Info().Str("bot_image", botImage).Msg("Docker orchestrator initialized")
+	if hostID == "" {
+		ctx, cancel := context.WithTimeout(context.Background(), constants.ContainerStartTimeout)
+		defer cancel()
+
+		info, err := cli.Info(ctx)
+		if err != nil {
+			cli.Close()
+			return nil, fmt.Errorf("failed to get Docker host ID: %w", err)
+		}
+		hostID = info.ID
+	}
+
+	log.Info().Str("bot_image", botImage).Str("bot_host", hostID).Msg("Docker orchestrator initialized")
 
 	return &DockerOrchestrator{
 		client:      cli,
 		botImage:    botImage,
 		redisURL:    redisURL,
-		storageType: storageType,
-		storagePath: storagePath,
+		storageEnv:  storageEnv,
+		hostID:      hostID,
 	}, nil
 }
 
+// HostID returns the ID of the Docker host this orchestrator spawns bots on
+func (o *DockerOrchestrator) HostID() string {
+	return o.hostID
+}
+
+// OwnsMeeting reports whether a meeting's bot runs on this orchestrator's Docker host.
+// Meetings spawned before bot hosts were recorded are treated as local.
+func (o *DockerOrchestrator) OwnsMeeting(meeting *types.Meeting) bool {
+	return meeting.BotHost == nil || *meeting.BotHost == "" || *meeting.BotHost == o.hostID
+}
+
 // SpawnBot creates and starts a new recording bot container
 func (o *DockerOrchestrator) SpawnBot(ctx context.Context, meeting *types.Meeting, user *types.User) error {
 	containerName := fmt.Sprintf("%s%d-%d", constants.BotContainerPrefix, meeting.ID, time.Now().Unix())
@@ -56,24 +91,25 @@ func (o *DockerOrchestrator) SpawnBot(ctx context.Context, meeting *types.Meetin
 	}
 
 	// Container configuration
+	// The container name doubles as hostname, which the bot uses as its
+	// recording session ID for the bot:status/bot:command Redis channels.
 	config := &container.Config{
-		Image: o.botImage,
-		Env: []string{
+		Image:    o.botImage,
+		Hostname: containerName,
+		Env: append([]string{
 			fmt.Sprintf("MEETING_ID=%d", meeting.ID),
 			fmt.Sprintf("USER_ID=%d", user.ID),
 			fmt.Sprintf("PLATFORM=%s", meeting.Platform),
 			fmt.Sprintf("MEETING_URL=%s", meeting.MeetingURL),
 			fmt.Sprintf("BOT_NAME=%s", botName),
 			fmt.Sprintf("REDIS_URL=%s", o.redisURL),
-			fmt.Sprintf("STORAGE_TYPE=%s", o.storageType),
-			fmt.Sprintf("STORAGE_PATH=%s", o.storagePath),
 			fmt.Sprintf("CHUNK_DURATION=%d", constants.ChunkDurationSeconds),
 			fmt.Sprintf("AUDIO_BITRATE=%d", constants.DefaultAudioBitrate),
-		},
+		}, o.storageEnv...),
 		Labels: map[string]string{
-			"newar.meeting_id": fmt.Sprintf("%d", meeting.ID),
-			"newar.user_id":    fmt.Sprintf("%d", user.ID),
-			"newar.platform":   string(meeting.Platform),
+			MeetingIDLabel:   fmt.Sprintf("%d", meeting.ID),
+			"newar.user_id":  fmt.Sprintf("%d", user.ID),
+			"newar.platform": string(meeting.Platform),
 		},
 	}
 
@@ -101,6 +137,10 @@ func (o *DockerOrchestrator) SpawnBot(ctx context.Context, meeting *types.Meetin
 
 	// Start container
 	if err := o.client.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
+		// Don't leave the created container behind for a retry to duplicate
+		if removeErr := o.RemoveBot(context.Background(), containerID); removeErr != nil {
+			log.Error().Err(removeErr).Str("container_id", containerID).Msg("Failed to remove container that did not start")
+		}
 		return fmt.Errorf("failed to start container: %w", err)
 	}
 
@@ -108,6 +148,10 @@ func (o *DockerOrchestrator) SpawnBot(ctx context.Context, meeting *types.Meetin
 		Str("container_id", containerID).
 		Msg("Container started successfully")
 
+	meeting.BotContainerID = &containerID
+	meeting.RecordingSessionID = &containerName
+	meeting.BotHost = &o.hostID
+
 	return nil
 }
 
@@ -164,6 +208,74 @@ func (o *DockerOrchestrator) GetContainerLogs(ctx context.Context, containerID s
 	return string(logs), nil
 }
 
+// InspectBot retrieves the runtime state of a bot container
+// Returns ErrBotNotFound if the container no longer exists
+func (o *DockerOrchestrator) InspectBot(ctx context.Context, containerID string) (*types.BotContainerState, error) {
+	info, err := o.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil, ErrBotNotFound
+		}
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	state := &types.BotContainerState{
+		ContainerID: info.ID,
+		Name:        strings.TrimPrefix(info.Name, "/"),
+	}
+
+	if info.Config != nil {
+		state.MeetingID, _ = strconv.ParseInt(info.Config.Labels[MeetingIDLabel], 10, 64)
+	}
+	if createdAt, err := time.Parse(time.RFC3339Nano, info.Created); err == nil {
+		state.CreatedAt = createdAt
+	}
+
+	if info.State != nil {
+		state.Status = info.State.Status
+		state.Running = info.State.Running || info.State.Restarting
+		state.ExitCode = info.State.ExitCode
+
+		if finishedAt, err := time.Parse(time.RFC3339Nano, info.State.FinishedAt); err == nil && !finishedAt.IsZero() {
+			state.FinishedAt = &finishedAt
+		}
+	}
+
+	return state, nil
+}
+
+// ListBots lists all bot containers (running and exited), identified by their meeting label
+func (o *DockerOrchestrator) ListBots(ctx context.Context) ([]*types.BotContainerState, error) {
+	containers, err := o.client.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", MeetingIDLabel)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	bots := make([]*types.BotContainerState, 0, len(containers))
+	for _, c := range containers {
+		meetingID, _ := strconv.ParseInt(c.Labels[MeetingIDLabel], 10, 64)
+
+		name := ""
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+
+		bots = append(bots, &types.BotContainerState{
+			ContainerID: c.ID,
+			Name:        name,
+			MeetingID:   meetingID,
+			Status:      c.State,
+			Running:     c.State == "running" || c.State == "restarting",
+			CreatedAt:   time.Unix(c.Created, 0),
+		})
+	}
+
+	return bots, nil
+}
+
 // GetBotStatus retrieves the current status of a bot
 func (o *DockerOrchestrator) GetBotStatus(ctx context.Context, sessionID string) (*types.BotStatusUpdate, error) {
 	// This is a stub implementation - in a real scenario, you'd query the bot's status
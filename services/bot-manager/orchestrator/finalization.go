@@ -0,0 +1,368 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/services/bot-manager/finalizer"
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/events"
+	"github.com/newar/insights/shared/redis"
+	"github.com/newar/insights/shared/types"
+	"github.com/newar/insights/shared/webhooks"
+)
+
+var (
+	// ErrFinalizationActive is returned when a meeting's finalization is already queued or running
+	ErrFinalizationActive = errors.New("finalization already queued")
+
+	// ErrMeetingNotFound is returned when the meeting to re-run finalization for does not exist
+	ErrMeetingNotFound = errors.New("meeting not found")
+
+	// ErrNotFinalizable is returned when a meeting is neither finalizing nor a failed recording
+	ErrNotFinalizable = errors.New("meeting cannot be finalized in its current status")
+)
+
+// FinalizationWorker finalizes recordings from the finalization queue.
+// Meetings stay "finalizing" while their job is queued; the worker completes them
+// once the recording is stored, or fails them after the last attempt.
+type FinalizationWorker struct {
+	queue       *redis.JobQueue
+	jobRepo     *database.FinalizationJobRepository
+	meetingRepo *database.MeetingRepository
+	userRepo    *database.UserRepository
+	eventRepo   *database.MeetingEventRepository
+	fileRepo    *database.RecordingFileRepository
+	finalizer   *finalizer.Finalizer
+	notifier    *webhooks.Notifier
+	publisher   *events.Publisher
+}
+
+// NewFinalizationWorker creates a new finalization worker
+func NewFinalizationWorker(queue *redis.JobQueue, jobRepo *database.FinalizationJobRepository, meetingRepo *database.MeetingRepository, userRepo *database.UserRepository, eventRepo *database.MeetingEventRepository, fileRepo *database.RecordingFileRepository, fin *finalizer.Finalizer, notifier *webhooks.Notifier, publisher *events.Publisher) *FinalizationWorker {
+	return &FinalizationWorker{
+		queue:       queue,
+		jobRepo:     jobRepo,
+		meetingRepo: meetingRepo,
+		userRepo:    userRepo,
+		eventRepo:   eventRepo,
+		fileRepo:    fileRepo,
+		finalizer:   fin,
+		notifier:    notifier,
+		publisher:   publisher,
+	}
+}
+
+// Enqueue queues the finalization of a meeting's uploaded chunks.
+// Returns ErrFinalizationActive if one is already queued or running.
+func (w *FinalizationWorker) Enqueue(ctx context.Context, meetingID int64, containerID string) error {
+	chunkCount, err := w.finalizer.CountChunks(ctx, meetingID)
+	if err != nil {
+		return fmt.Errorf("failed to count chunks: %w", err)
+	}
+
+	queued, err := w.jobRepo.Queue(ctx, meetingID, containerID, chunkCount)
+	if err != nil {
+		return err
+	}
+	if !queued {
+		return ErrFinalizationActive
+	}
+
+	req := types.FinalizeJobRequest{
+		MeetingID:   meetingID,
+		ContainerID: containerID,
+		ChunkCount:  chunkCount,
+	}
+	if _, err := w.queue.Enqueue(ctx, req); err != nil {
+		if failErr := w.jobRepo.Fail(ctx, meetingID, err.Error()); failErr != nil {
+			log.Error().Err(failErr).Int64("meeting_id", meetingID).Msg("Failed to record finalization failure")
+		}
+		return err
+	}
+
+	log.Info().
+		Int64("meeting_id", meetingID).
+		Int("chunk_count", chunkCount).
+		Dur("timeout", finalizeTimeout(chunkCount)).
+		Msg("Finalization queued")
+
+	return nil
+}
+
+// Rerun queues the finalization of a meeting again, e.g. after it failed. Failed
+// recordings are moved back to finalizing first; their chunks must still exist.
+func (w *FinalizationWorker) Rerun(ctx context.Context, meetingID int64) error {
+	meeting, err := w.meetingRepo.GetByID(ctx, meetingID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMeetingNotFound, err)
+	}
+
+	containerID := ""
+	if meeting.RecordingSessionID != nil {
+		containerID = *meeting.RecordingSessionID
+	}
+
+	switch meeting.Status {
+	case types.MeetingStatusFinalizing:
+		// Queue it unless a job is already on its way
+	case types.MeetingStatusFailed:
+		chunkCount, err := w.finalizer.CountChunks(ctx, meetingID)
+		if err != nil {
+			return fmt.Errorf("failed to count chunks: %w", err)
+		}
+		if chunkCount == 0 {
+			return fmt.Errorf("%w for meeting %d", finalizer.ErrNoChunks, meetingID)
+		}
+
+		var transitionErr *database.InvalidTransitionError
+		if err := w.meetingRepo.ReopenForFinalization(ctx, meetingID); errors.As(err, &transitionErr) {
+			return ErrNotFinalizable
+		} else if err != nil {
+			return err
+		}
+
+		w.notifier.Notify(ctx, meetingID, types.StatusFinalizing)
+		w.publisher.PublishStatusChanged(ctx, meetingID, meeting.UserID, types.StatusFinalizing, nil, 0)
+	default:
+		return ErrNotFinalizable
+	}
+
+	// A reopened meeting whose job fails to queue is picked up again by the reaper
+	return w.Enqueue(ctx, meetingID, containerID)
+}
+
+// Active reports whether a meeting's finalization is queued, running or awaiting a retry
+func (w *FinalizationWorker) Active(ctx context.Context, meetingID int64) bool {
+	job, err := w.jobRepo.GetByMeeting(ctx, meetingID)
+	if err != nil {
+		return false
+	}
+	return job.Status != types.FinalizationCompleted && job.Status != types.FinalizationFailed
+}
+
+// Consume processes finalization jobs until ctx is cancelled
+func (w *FinalizationWorker) Consume(ctx context.Context, consumerName string) error {
+	return w.queue.Consume(ctx, redis.ConsumerConfig{
+		Name:          consumerName,
+		Concurrency:   constants.FinalizeConcurrency,
+		MaxDeliveries: constants.FinalizeMaxAttempts,
+		JobTimeout:    constants.FinalizeBaseTimeout,
+		RetryBackoff:  constants.FinalizeRetryBackoff,
+		MaxBackoff:    constants.FinalizeMaxBackoff,
+		Timeout: func(job redis.Job) time.Duration {
+			var req types.FinalizeJobRequest
+			if err := job.Decode(&req); err != nil {
+				return constants.FinalizeBaseTimeout
+			}
+			return finalizeTimeout(req.ChunkCount)
+		},
+		OnDeadLetter: w.handleDeadLetter,
+	}, w.handleJob)
+}
+
+// finalizeTimeout scales the job timeout with the number of chunks
+func finalizeTimeout(chunkCount int) time.Duration {
+	timeout := constants.FinalizeBaseTimeout + time.Duration(chunkCount)*constants.FinalizeChunkTimeout
+	if timeout > constants.FinalizeMaxTimeout {
+		return constants.FinalizeMaxTimeout
+	}
+	return timeout
+}
+
+// handleJob finalizes a single meeting
+func (w *FinalizationWorker) handleJob(ctx context.Context, job redis.Job) error {
+	var req types.FinalizeJobRequest
+	if err := job.Decode(&req); err != nil {
+		return &redis.PermanentJobError{Err: fmt.Errorf("invalid finalize request: %w", err)}
+	}
+
+	meeting, err := w.meetingRepo.GetByID(ctx, req.MeetingID)
+	if err != nil {
+		return &redis.PermanentJobError{Err: err}
+	}
+
+	// Cancelled or already settled while the job was waiting
+	if meeting.Status != types.MeetingStatusFinalizing {
+		log.Info().
+			Int64("meeting_id", req.MeetingID).
+			Str("status", string(meeting.Status)).
+			Msg("Finalization skipped - meeting is not finalizing")
+		if err := w.jobRepo.Fail(ctx, req.MeetingID, "Meeting is "+string(meeting.Status)); err != nil {
+			log.Error().Err(err).Int64("meeting_id", req.MeetingID).Msg("Failed to record skipped finalization")
+		}
+		return nil
+	}
+
+	if err := w.jobRepo.Start(ctx, req.MeetingID); err != nil {
+		log.Warn().Err(err).Int64("meeting_id", req.MeetingID).Msg("Failed to record finalization attempt")
+	}
+
+	err = w.finalize(ctx, meeting, req)
+	if err == nil {
+		if err := w.jobRepo.Complete(ctx, req.MeetingID); err != nil {
+			log.Error().Err(err).Int64("meeting_id", req.MeetingID).Msg("Failed to record finalization result")
+		}
+		return nil
+	}
+
+	log.Error().
+		Err(err).
+		Int64("meeting_id", req.MeetingID).
+		Int64("attempt", job.Deliveries).
+		Msg("Failed to finalize recording")
+
+	// Dead-lettered jobs are recorded as failed by handleDeadLetter
+	if errors.Is(err, finalizer.ErrNoChunks) {
+		return &redis.PermanentJobError{Err: err}
+	}
+	if job.Deliveries < constants.FinalizeMaxAttempts {
+		if err := w.jobRepo.Retry(ctx, req.MeetingID, err.Error()); err != nil {
+			log.Error().Err(err).Int64("meeting_id", req.MeetingID).Msg("Failed to record finalization failure")
+		}
+	}
+
+	return err
+}
+
+// finalize concatenates the meeting's chunks, stores the recording and its
+// renditions and marks the meeting as completed. Chunks are only removed once
+// the meeting is completed, so a failed attempt can be retried.
+func (w *FinalizationWorker) finalize(ctx context.Context, meeting *types.Meeting, req types.FinalizeJobRequest) error {
+	file, err := w.finalizer.FinalizeRecording(ctx, meeting.ID, req.ContainerID)
+	if err != nil {
+		return err
+	}
+
+	var recordingDuration *int
+	if file.DurationSeconds != nil {
+		seconds := int(math.Round(*file.DurationSeconds))
+		recordingDuration = &seconds
+	}
+
+	if err := w.fileRepo.Create(ctx, file); err != nil {
+		log.Error().Err(err).Int64("meeting_id", meeting.ID).Msg("Failed to store recording file metadata")
+	}
+
+	renditions := w.finalizer.CreateRenditions(ctx, file, w.outputFormats(ctx, meeting))
+	for _, rendition := range renditions {
+		if err := w.fileRepo.Create(ctx, rendition); err != nil {
+			log.Error().Err(err).Int64("meeting_id", meeting.ID).Str("format", rendition.Format).Msg("Failed to store rendition metadata")
+		}
+	}
+
+	err = w.meetingRepo.UpdateStatus(ctx, meeting.ID, types.StatusCompleted, &file.Path, nil, recordingDuration)
+
+	var transitionErr *database.InvalidTransitionError
+	if errors.As(err, &transitionErr) {
+		// Cancelled while finalizing - keep the file, leave the status alone
+		log.Warn().
+			Int64("meeting_id", meeting.ID).
+			Str("current_status", string(transitionErr.From)).
+			Msg("Meeting left finalizing before the recording was stored")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to complete meeting: %w", err)
+	}
+
+	log.Info().
+		Int64("meeting_id", meeting.ID).
+		Str("recording_path", file.Path).
+		Int64("size_bytes", file.SizeBytes).
+		Int("renditions", len(renditions)).
+		Msg("Recording finalized successfully")
+
+	w.appendEvent(ctx, req, types.MeetingStatusCompleted, nil)
+	w.notifier.Notify(ctx, meeting.ID, types.StatusCompleted)
+	w.publisher.Publish(ctx, types.EventRecordingFinalized, meeting.ID, meeting.UserID, types.RecordingFinalizedPayload{
+		Success:       true,
+		RecordingPath: &file.Path,
+		File:          file,
+		Renditions:    renditions,
+	})
+	w.publisher.PublishStatusChanged(ctx, meeting.ID, meeting.UserID, types.StatusCompleted, nil, 0)
+
+	w.finalizer.RemoveChunks(ctx, meeting.ID)
+
+	return nil
+}
+
+// handleDeadLetter fails the meeting once its finalization is given up
+func (w *FinalizationWorker) handleDeadLetter(job redis.Job, jobErr error) {
+	var req types.FinalizeJobRequest
+	if err := job.Decode(&req); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	errMsg := "Finalization failed: " + jobErr.Error()
+	if err := w.jobRepo.Fail(ctx, req.MeetingID, jobErr.Error()); err != nil {
+		log.Error().Err(err).Int64("meeting_id", req.MeetingID).Msg("Failed to record finalization failure")
+	}
+
+	meeting, err := w.meetingRepo.GetByID(ctx, req.MeetingID)
+	if err != nil {
+		return
+	}
+
+	err = w.meetingRepo.UpdateStatus(ctx, req.MeetingID, types.StatusFailed, nil, &errMsg, nil)
+
+	var transitionErr *database.InvalidTransitionError
+	if errors.As(err, &transitionErr) {
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Int64("meeting_id", req.MeetingID).Msg("Failed to mark meeting as failed")
+		return
+	}
+
+	w.appendEvent(ctx, req, types.MeetingStatusFailed, &errMsg)
+	w.notifier.Notify(ctx, req.MeetingID, types.StatusFailed)
+
+	finalizeErr := jobErr.Error()
+	w.publisher.Publish(ctx, types.EventRecordingFinalized, req.MeetingID, meeting.UserID, types.RecordingFinalizedPayload{
+		Success: false,
+		Error:   &finalizeErr,
+	})
+	w.publisher.PublishStatusChanged(ctx, req.MeetingID, meeting.UserID, types.StatusFailed, &errMsg, 0)
+}
+
+// outputFormats returns the extra output formats for a meeting's recording:
+// the meeting's own setting, or else its owner's default
+func (w *FinalizationWorker) outputFormats(ctx context.Context, meeting *types.Meeting) []string {
+	if len(meeting.OutputFormats) > 0 {
+		return meeting.OutputFormats
+	}
+
+	user, err := w.userRepo.GetByID(ctx, meeting.UserID)
+	if err != nil {
+		log.Warn().Err(err).Int64("user_id", meeting.UserID).Msg("Failed to get user for default output formats")
+		return nil
+	}
+
+	return user.DefaultOutputFormats()
+}
+
+// appendEvent records the finalization outcome in the meeting event log
+func (w *FinalizationWorker) appendEvent(ctx context.Context, req types.FinalizeJobRequest, status types.MeetingStatus, errorMsg *string) {
+	err := w.eventRepo.Append(ctx, types.BotStatusUpdate{
+		ContainerID:  req.ContainerID,
+		MeetingID:    req.MeetingID,
+		Status:       status,
+		ErrorMessage: errorMsg,
+		ChunkCount:   req.ChunkCount,
+		Timestamp:    time.Now(),
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("meeting_id", req.MeetingID).Str("status", string(status)).Msg("Failed to record meeting event")
+	}
+}
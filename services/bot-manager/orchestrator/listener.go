@@ -2,29 +2,42 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
-	"github.com/newar/insights/services/bot-manager/finalizer"
+	"github.com/newar/insights/shared/constants"
 	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/events"
 	"github.com/newar/insights/shared/redis"
 	"github.com/newar/insights/shared/types"
+	"github.com/newar/insights/shared/webhooks"
 )
 
-// StatusListener listens for bot status updates from Redis
+// StatusListener listens for bot status updates from Redis.
+// Updates arrive through a shared StatusDispatcher, so listeners do not hold
+// their own Redis connections.
 type StatusListener struct {
-	redisClient *redis.Client
-	meetingRepo *database.MeetingRepository
-	finalizer   *finalizer.Finalizer
+	dispatcher   *redis.StatusDispatcher
+	meetingRepo  *database.MeetingRepository
+	eventRepo    *database.MeetingEventRepository
+	finalization *FinalizationWorker
+	registry     *ListenerRegistry
+	notifier     *webhooks.Notifier
+	publisher    *events.Publisher
 }
 
 // NewStatusListener creates a new status listener
-func NewStatusListener(redisClient *redis.Client, meetingRepo *database.MeetingRepository, fin *finalizer.Finalizer) *StatusListener {
+func NewStatusListener(dispatcher *redis.StatusDispatcher, meetingRepo *database.MeetingRepository, eventRepo *database.MeetingEventRepository, finalization *FinalizationWorker, notifier *webhooks.Notifier, publisher *events.Publisher) *StatusListener {
 	return &StatusListener{
-		redisClient: redisClient,
-		meetingRepo: meetingRepo,
-		finalizer:   fin,
+		dispatcher:   dispatcher,
+		meetingRepo:  meetingRepo,
+		eventRepo:    eventRepo,
+		finalization: finalization,
+		registry:     NewListenerRegistry(),
+		notifier:     notifier,
+		publisher:    publisher,
 	}
 }
 
@@ -32,7 +45,7 @@ func NewStatusListener(redisClient *redis.Client, meetingRepo *database.MeetingR
 func (l *StatusListener) ListenForContainer(ctx context.Context, containerID string) error {
 	log.Info().Str("container_id", containerID).Msg("Starting status listener for container")
 
-	return l.redisClient.SubscribeBotStatus(ctx, containerID, func(status types.BotStatusUpdate) {
+	return l.dispatcher.Listen(ctx, containerID, func(status types.BotStatusUpdate) {
 		l.handleStatusUpdate(status)
 	})
 }
@@ -49,66 +62,191 @@ func (l *StatusListener) handleStatusUpdate(status types.BotStatusUpdate) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Update meeting status in database
-	var recordingPath *string
-	if status.Status == types.StatusCompleted && status.ErrorMessage == nil {
-		// Trigger finalization
-		path, err := l.finalizer.FinalizeRecording(ctx, status.MeetingID, status.ContainerID)
-		if err != nil {
-			log.Error().
-				Err(err).
+	// Only finalize if the meeting can still complete (it may have been cancelled)
+	if status.Status == types.StatusCompleted {
+		meeting, err := l.meetingRepo.GetByID(ctx, status.MeetingID)
+		if err == nil && !types.CanTransition(meeting.Status, types.MeetingStatusCompleted) {
+			l.appendEvent(ctx, status)
+			log.Warn().
 				Int64("meeting_id", status.MeetingID).
-				Msg("Failed to finalize recording")
-
-			errMsg := "Finalization failed: " + err.Error()
-			status.ErrorMessage = &errMsg
-			status.Status = types.StatusFailed
-		} else {
-			recordingPath = &path
-			log.Info().
+				Str("current_status", string(meeting.Status)).
+				Msg("Ignoring completed status - meeting cannot complete")
+			l.StopListening(status.ContainerID)
+			return
+		}
+	}
+
+	// The recording is complete once finalized - the meeting stays "finalizing"
+	// until the finalization worker has stored it, and the worker records the event.
+	// A completion carrying an error is finalized too: its chunks were uploaded.
+	if status.Status == types.StatusCompleted {
+		if status.ErrorMessage != nil {
+			log.Warn().
 				Int64("meeting_id", status.MeetingID).
-				Str("recording_path", path).
-				Msg("Recording finalized successfully")
+				Str("error_message", *status.ErrorMessage).
+				Msg("Bot completed with an error - finalizing uploaded chunks")
 		}
+
+		err := l.finalization.Enqueue(ctx, status.MeetingID, status.ContainerID)
+		if err == nil || errors.Is(err, ErrFinalizationActive) {
+			l.StopListening(status.ContainerID)
+			return
+		}
+
+		log.Error().
+			Err(err).
+			Int64("meeting_id", status.MeetingID).
+			Msg("Failed to queue finalization")
+
+		errMsg := "Failed to queue finalization: " + err.Error()
+		status.ErrorMessage = &errMsg
+		status.Status = types.StatusFailed
+		status.Timestamp = time.Now()
 	}
 
+	// Keep the full history - meetings.status only holds the latest status
+	l.appendEvent(ctx, status)
+
 	// Update database
 	err := l.meetingRepo.UpdateStatus(
 		ctx,
 		status.MeetingID,
 		status.Status,
-		recordingPath,
+		nil,
 		status.ErrorMessage,
-		nil, // recordingDuration - will be calculated from chunks later
+		nil,
 	)
 
-	if err != nil {
+	var transitionErr *database.InvalidTransitionError
+	if errors.As(err, &transitionErr) {
+		// Late or out-of-order update (e.g. "joining" after the meeting completed)
+		log.Warn().
+			Int64("meeting_id", status.MeetingID).
+			Str("current_status", string(transitionErr.From)).
+			Str("status", string(status.Status)).
+			Msg("Ignored out-of-order status update")
+	} else if err != nil {
 		log.Error().
 			Err(err).
 			Int64("meeting_id", status.MeetingID).
 			Str("status", string(status.Status)).
 			Msg("Failed to update meeting status")
+	} else {
+		l.notifier.Notify(ctx, status.MeetingID, status.Status)
+		l.publish(ctx, types.EventStatusChanged, status.MeetingID, types.StatusChangedPayload{
+			Status:       status.Status,
+			ErrorMessage: status.ErrorMessage,
+			ChunkCount:   status.ChunkCount,
+		})
+	}
+
+	// No further updates are expected once the recording has ended
+	if types.IsTerminalStatus(status.Status) {
+		l.StopListening(status.ContainerID)
 	}
 }
 
-// StartListening begins listening for status updates from a bot (legacy compatibility)
-func (l *StatusListener) StartListening(sessionID string, meetingID int64) {
-	// Legacy method for backward compatibility
-	// Spawns a goroutine to listen for this session
-	go func() {
-		ctx := context.Background()
-		if err := l.ListenForContainer(ctx, sessionID); err != nil {
-			log.Error().
-				Err(err).
-				Str("session_id", sessionID).
-				Int64("meeting_id", meetingID).
-				Msg("Status listener stopped")
+// appendEvent records a status update in the meeting event log
+func (l *StatusListener) appendEvent(ctx context.Context, status types.BotStatusUpdate) {
+	if err := l.eventRepo.Append(ctx, status); err != nil {
+		log.Error().
+			Err(err).
+			Int64("meeting_id", status.MeetingID).
+			Str("status", string(status.Status)).
+			Msg("Failed to record meeting event")
+	}
+}
+
+// publish publishes a meeting event on the event bus, looking up the meeting owner
+func (l *StatusListener) publish(ctx context.Context, eventType types.MeetingEventType, meetingID int64, payload interface{}) {
+	meeting, err := l.meetingRepo.GetByID(ctx, meetingID)
+	if err != nil {
+		log.Warn().Err(err).Int64("meeting_id", meetingID).Str("event_type", string(eventType)).Msg("Skipping meeting event - meeting not found")
+		return
+	}
+
+	l.publisher.Publish(ctx, eventType, meetingID, meeting.UserID, payload)
+}
+
+// resolveGoneBot settles a meeting whose bot exited without reporting a final status.
+// Meetings that were recording are queued for finalization so uploaded chunks are
+// kept (they stay "finalizing" until the worker is done); all others are marked as
+// failed with the given reason. Returns the resulting status.
+func (l *StatusListener) resolveGoneBot(meeting *types.Meeting, sessionID, reason string) types.MeetingStatus {
+	logger := log.With().
+		Int64("meeting_id", meeting.ID).
+		Str("session_id", sessionID).
+		Str("status", string(meeting.Status)).
+		Str("reason", reason).
+		Logger()
+
+	// Bot was recording - salvage whatever chunks were uploaded
+	if meeting.Status == types.StatusRecording || meeting.Status == types.StatusFinalizing {
+		logger.Warn().Msg("Bot gone while recording - finalizing recording")
+		if meeting.Status == types.StatusRecording {
+			l.handleStatusUpdate(types.BotStatusUpdate{
+				ContainerID: sessionID,
+				MeetingID:   meeting.ID,
+				Status:      types.StatusFinalizing,
+				Timestamp:   time.Now(),
+			})
 		}
-	}()
+		l.handleStatusUpdate(types.BotStatusUpdate{
+			ContainerID: sessionID,
+			MeetingID:   meeting.ID,
+			Status:      types.StatusCompleted,
+			Timestamp:   time.Now(),
+		})
+		return types.StatusFinalizing
+	}
+
+	logger.Warn().Msg("Bot gone before recording - marking meeting as failed")
+	l.handleStatusUpdate(types.BotStatusUpdate{
+		ContainerID:  sessionID,
+		MeetingID:    meeting.ID,
+		Status:       types.StatusFailed,
+		ErrorMessage: &reason,
+		Timestamp:    time.Now(),
+	})
+	return types.StatusFailed
+}
+
+// StartListening begins listening for status updates from a bot in the background.
+// Calling it again for a session that is already being listened to is a no-op.
+func (l *StatusListener) StartListening(sessionID string, meetingID int64) {
+	started := l.registry.Start(sessionID, func(ctx context.Context) error {
+		return l.ListenForContainer(ctx, sessionID)
+	})
+
+	log.Debug().
+		Str("session_id", sessionID).
+		Int64("meeting_id", meetingID).
+		Bool("started", started).
+		Int("active_listeners", l.registry.Count()).
+		Msg("StartListening called")
 }
 
 // StopListening stops listening for status updates from a bot
 func (l *StatusListener) StopListening(sessionID string) {
-	// This is a stub - actual implementation would need context cancellation
-	log.Info().Str("session_id", sessionID).Msg("StopListening called (no-op)")
+	if l.registry.Stop(sessionID) {
+		log.Info().
+			Str("session_id", sessionID).
+			Int("active_listeners", l.registry.Count()).
+			Msg("Status listener cancelled")
+	}
+}
+
+// ActiveListeners returns the number of running status listeners
+func (l *StatusListener) ActiveListeners() int {
+	return l.registry.Count()
+}
+
+// DispatchStats returns the status dispatcher counters
+func (l *StatusListener) DispatchStats() redis.DispatcherStats {
+	return l.dispatcher.Stats()
+}
+
+// Drain stops all status listeners and waits for them to exit (used on shutdown)
+func (l *StatusListener) Drain() {
+	l.registry.Drain(constants.BotShutdownTimeout)
 }
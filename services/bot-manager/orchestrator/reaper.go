@@ -0,0 +1,320 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/metrics"
+	"github.com/newar/insights/shared/redis"
+	"github.com/newar/insights/shared/types"
+)
+
+// Reaper actions
+const (
+	ReapActionRemoveExited  = "remove_exited"  // Exited container past the cleanup delay
+	ReapActionStopOrphan    = "stop_orphan"    // Running container without an active meeting
+	ReapActionJoinTimeout   = "join_timeout"   // Bot never got into the meeting
+	ReapActionMaxDuration   = "max_duration"   // Recording hit its max duration - finalizing and stop requested
+	ReapActionKillOverdue   = "kill_overdue"   // Bot ignored the stop request
+	ReapActionResolveExited = "resolve_exited" // Bot exited without reporting a final status
+	ReapActionResolveLost   = "resolve_lost"   // Active meeting whose container is gone
+)
+
+// Reaper periodically cleans up bot containers and meetings that got stuck.
+// It cross-references containers (by their meeting label) against the meetings
+// table, enforces join and recording length limits, and removes exited containers.
+// Only containers on this replica's Docker host, and meetings whose bots run there,
+// are reaped.
+// Every action is logged and counted so cleanups can be audited.
+type Reaper struct {
+	orchestrator *DockerOrchestrator
+	listener     *StatusListener
+	meetingRepo  *database.MeetingRepository
+	userRepo     *database.UserRepository
+	redisClient  *redis.Client
+	metrics      *metrics.Collector
+	interval     time.Duration
+}
+
+// NewReaper creates a new reaper
+func NewReaper(orchestrator *DockerOrchestrator, listener *StatusListener, meetingRepo *database.MeetingRepository, userRepo *database.UserRepository, redisClient *redis.Client, collector *metrics.Collector) *Reaper {
+	return &Reaper{
+		orchestrator: orchestrator,
+		listener:     listener,
+		meetingRepo:  meetingRepo,
+		userRepo:     userRepo,
+		redisClient:  redisClient,
+		metrics:      collector,
+		interval:     constants.ReaperInterval,
+	}
+}
+
+// Run reaps on every interval until ctx is cancelled
+func (r *Reaper) Run(ctx context.Context) {
+	log.Info().Dur("interval", r.interval).Msg("Reaper started")
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Reaper stopped")
+			return
+		case <-ticker.C:
+			if err := r.Reap(ctx); err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("Reaper pass failed")
+			}
+		}
+	}
+}
+
+// Reap runs a single reaper pass
+func (r *Reaper) Reap(ctx context.Context) error {
+	// Meetings are loaded before containers are listed, so a bot spawned in between
+	// is seen as a container rather than mistaken for a lost one
+	meetings, err := r.meetingRepo.GetActiveRecordings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load active recordings: %w", err)
+	}
+
+	bots, err := r.orchestrator.ListBots(ctx)
+	if err != nil {
+		return err
+	}
+
+	seen := map[int64]bool{}
+	for _, bot := range bots {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		seen[bot.MeetingID] = true
+		r.reapBot(ctx, bot)
+	}
+
+	// Meetings that think they have a bot, but no container exists for them
+	for _, meeting := range meetings {
+		if seen[meeting.ID] || meeting.RecordingSessionID == nil || *meeting.RecordingSessionID == "" {
+			continue
+		}
+		// Bot runs on another bot-manager's Docker host
+		if !r.orchestrator.OwnsMeeting(meeting) {
+			continue
+		}
+		// Recently updated - the bot may have just been spawned or just exited
+		if time.Since(meeting.UpdatedAt) < constants.ReaperLostGracePeriod {
+			continue
+		}
+		// Bot is gone by design once the recording is handed to the finalization queue
+		if r.awaitingFinalization(ctx, meeting) {
+			continue
+		}
+		r.record(ReapActionResolveLost, meeting.ID, "", string(meeting.Status))
+		r.listener.resolveGoneBot(meeting, *meeting.RecordingSessionID, "Bot container no longer exists (detected by reaper)")
+	}
+
+	return nil
+}
+
+// reapBot applies the reaper rules to a single bot container
+func (r *Reaper) reapBot(ctx context.Context, bot *types.BotContainerState) {
+	meeting, err := r.meetingRepo.GetByID(ctx, bot.MeetingID)
+	if err != nil {
+		meeting = nil
+	}
+
+	// Not the meeting's tracked bot (a duplicate spawn, or the meeting's bot runs on
+	// another bot-manager's host) - the container is an orphan and must not settle the meeting
+	if meeting != nil && meeting.BotContainerID != nil && *meeting.BotContainerID != "" && *meeting.BotContainerID != bot.ContainerID {
+		meeting = nil
+	}
+
+	if !bot.Running {
+		r.reapExited(ctx, bot, meeting)
+		return
+	}
+
+	// Container has no meeting, or its meeting already ended
+	if meeting == nil || types.IsTerminalStatus(meeting.Status) {
+		if meeting != nil && time.Since(meeting.UpdatedAt) < constants.BotShutdownTimeout {
+			return // Bot is still shutting down on its own
+		}
+		r.record(ReapActionStopOrphan, bot.MeetingID, bot.ContainerID, "no active meeting")
+		r.stopBot(ctx, bot)
+		return
+	}
+
+	switch meeting.Status {
+	case types.StatusRequested, types.StatusJoining:
+		joinDeadline := constants.BotStartTimeout + constants.BotJoinTimeout
+		if time.Since(bot.CreatedAt) < joinDeadline {
+			return
+		}
+
+		reason := fmt.Sprintf("Bot did not join the meeting within %s", joinDeadline)
+		r.record(ReapActionJoinTimeout, meeting.ID, bot.ContainerID, reason)
+		r.stopBot(ctx, bot)
+		r.listener.handleStatusUpdate(types.BotStatusUpdate{
+			ContainerID:  bot.Name,
+			MeetingID:    meeting.ID,
+			Status:       types.StatusFailed,
+			ErrorMessage: &reason,
+			Timestamp:    time.Now(),
+		})
+
+	case types.StatusActive, types.StatusRecording, types.StatusFinalizing:
+		// Stop already requested (by the user or at the max duration) - the bot gets
+		// a grace period to upload its last chunk
+		if meeting.StopRequestedAt != nil {
+			if time.Since(*meeting.StopRequestedAt) >= constants.BotStopGracePeriod {
+				r.record(ReapActionKillOverdue, meeting.ID, bot.ContainerID, "bot ignored stop command")
+				r.stopBot(ctx, bot)
+			}
+			return
+		}
+
+		// The bot reported it is finishing on its own
+		if meeting.Status == types.StatusFinalizing {
+			return
+		}
+
+		startedAt := bot.CreatedAt
+		if meeting.StartedAt != nil {
+			startedAt = *meeting.StartedAt
+		}
+		maxDuration := r.maxDuration(ctx, meeting)
+		if time.Since(startedAt) < maxDuration {
+			return
+		}
+
+		r.record(ReapActionMaxDuration, meeting.ID, bot.ContainerID, maxDuration.String())
+		r.stopAtMaxDuration(ctx, bot, meeting)
+	}
+}
+
+// maxDuration returns how long a meeting may be recorded: its own max duration,
+// else the owner's default, capped at constants.MaxRecordingDuration
+func (r *Reaper) maxDuration(ctx context.Context, meeting *types.Meeting) time.Duration {
+	seconds := meeting.MaxDuration
+	if seconds == nil {
+		if user, err := r.userRepo.GetByID(ctx, meeting.UserID); err == nil {
+			seconds = user.DefaultMaxDuration()
+		}
+	}
+
+	if seconds == nil || *seconds <= 0 {
+		return constants.MaxRecordingDuration
+	}
+
+	maxDuration := time.Duration(*seconds) * time.Second
+	if maxDuration > constants.MaxRecordingDuration {
+		return constants.MaxRecordingDuration
+	}
+	return maxDuration
+}
+
+// stopAtMaxDuration moves a recording that hit its max duration to finalizing and
+// asks the bot to stop, so it uploads its last chunk and reports completion.
+// Bots that never started recording have nothing to finalize and are failed instead.
+// The stop request is stored first, so later passes (even after a restart) kill
+// the bot once the grace period is over.
+func (r *Reaper) stopAtMaxDuration(ctx context.Context, bot *types.BotContainerState, meeting *types.Meeting) {
+	reason := constants.MaxDurationStopReason
+	if err := r.meetingRepo.RequestStop(ctx, meeting.ID, reason); err != nil {
+		log.Error().Err(err).Int64("meeting_id", meeting.ID).Msg("Reaper failed to store stop request")
+		return // Retried on the next pass
+	}
+
+	// The reason is kept in stop_reason - a finalizing recording is not an error
+	update := types.BotStatusUpdate{
+		ContainerID: bot.Name,
+		MeetingID:   meeting.ID,
+		Status:      types.MeetingStatusFinalizing,
+		Timestamp:   time.Now(),
+	}
+	if meeting.Status != types.StatusRecording {
+		errMsg := "Bot did not start recording before the max duration was reached"
+		update.Status = types.MeetingStatusFailed
+		update.ErrorMessage = &errMsg
+	}
+
+	r.listener.handleStatusUpdate(update)
+
+	r.requestStop(ctx, bot)
+}
+
+// reapExited settles and removes a container that has exited
+func (r *Reaper) reapExited(ctx context.Context, bot *types.BotContainerState, meeting *types.Meeting) {
+	state, err := r.orchestrator.InspectBot(ctx, bot.ContainerID)
+	if err != nil {
+		log.Warn().Err(err).Str("container_id", bot.ContainerID).Msg("Reaper failed to inspect exited container")
+		return
+	}
+
+	exitedFor := time.Duration(0)
+	if state.FinishedAt != nil {
+		exitedFor = time.Since(*state.FinishedAt)
+	}
+
+	// Bot exited but its meeting is still active - the final status never arrived
+	if meeting != nil && !types.IsTerminalStatus(meeting.Status) && !r.awaitingFinalization(ctx, meeting) {
+		if exitedFor < constants.ReaperExitGracePeriod {
+			return
+		}
+		reason := fmt.Sprintf("Bot container %s with exit code %d (detected by reaper)", state.Status, state.ExitCode)
+		r.record(ReapActionResolveExited, meeting.ID, bot.ContainerID, reason)
+		r.listener.resolveGoneBot(meeting, bot.Name, reason)
+		return
+	}
+
+	if exitedFor < constants.ContainerCleanupDelay {
+		return
+	}
+
+	r.record(ReapActionRemoveExited, bot.MeetingID, bot.ContainerID, fmt.Sprintf("exit code %d", state.ExitCode))
+	if err := r.orchestrator.RemoveBot(ctx, bot.ContainerID); err != nil {
+		log.Error().Err(err).Str("container_id", bot.ContainerID).Msg("Reaper failed to remove container")
+	}
+}
+
+// awaitingFinalization reports whether a meeting's recording is in the finalization queue
+func (r *Reaper) awaitingFinalization(ctx context.Context, meeting *types.Meeting) bool {
+	return meeting.Status == types.StatusFinalizing && r.listener.finalization.Active(ctx, meeting.ID)
+}
+
+// requestStop sends a stop command to the bot
+func (r *Reaper) requestStop(ctx context.Context, bot *types.BotContainerState) {
+	err := r.redisClient.PublishBotCommand(ctx, bot.Name, types.BotCommand{
+		Command:   constants.BotCommandStop,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("container_id", bot.ContainerID).Msg("Reaper failed to send stop command")
+	}
+}
+
+// stopBot stops a running bot container (it is removed on a later pass)
+func (r *Reaper) stopBot(ctx context.Context, bot *types.BotContainerState) {
+	if err := r.orchestrator.StopBot(ctx, bot.ContainerID); err != nil {
+		log.Error().Err(err).Str("container_id", bot.ContainerID).Msg("Reaper failed to stop container")
+	}
+}
+
+// record logs and counts a reaper action
+func (r *Reaper) record(action string, meetingID int64, containerID, detail string) {
+	log.Warn().
+		Str("reaper_action", action).
+		Int64("meeting_id", meetingID).
+		Str("container_id", containerID).
+		Str("detail", detail).
+		Msg("Reaper action")
+
+	if r.metrics != nil {
+		r.metrics.IncrementCounter("bot_reaper_actions_total", map[string]string{"action": action})
+	}
+}
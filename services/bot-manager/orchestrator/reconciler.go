@@ -0,0 +1,120 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/types"
+)
+
+// Reconciler restores bot tracking after a bot-manager restart.
+// Status subscriptions live in memory, so on startup every active meeting whose
+// bot runs on this replica's Docker host is checked against its container: live
+// bots get a new listener, while meetings whose bots are gone are finalized (if
+// they were recording) or failed. Other replicas' meetings are left alone.
+type Reconciler struct {
+	orchestrator *DockerOrchestrator
+	listener     *StatusListener
+	meetingRepo  *database.MeetingRepository
+}
+
+// ReconcileResult summarizes a reconciliation run
+type ReconcileResult struct {
+	Reattached int
+	Finalized  int
+	Failed     int
+	Skipped    int
+}
+
+// NewReconciler creates a new reconciler
+func NewReconciler(orchestrator *DockerOrchestrator, listener *StatusListener, meetingRepo *database.MeetingRepository) *Reconciler {
+	return &Reconciler{
+		orchestrator: orchestrator,
+		listener:     listener,
+		meetingRepo:  meetingRepo,
+	}
+}
+
+// Reconcile loads all active meetings and re-attaches or resolves each of them
+func (r *Reconciler) Reconcile(ctx context.Context) (*ReconcileResult, error) {
+	meetings, err := r.meetingRepo.GetActiveRecordings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load active recordings: %w", err)
+	}
+
+	log.Info().Int("count", len(meetings)).Msg("Reconciling active recordings")
+
+	result := &ReconcileResult{}
+	for _, meeting := range meetings {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		r.reconcileMeeting(ctx, meeting, result)
+	}
+
+	log.Info().
+		Int("reattached", result.Reattached).
+		Int("finalized", result.Finalized).
+		Int("failed", result.Failed).
+		Int("skipped", result.Skipped).
+		Msg("Reconciliation completed")
+
+	return result, nil
+}
+
+// reconcileMeeting resolves a single active meeting against its container state
+func (r *Reconciler) reconcileMeeting(ctx context.Context, meeting *types.Meeting, result *ReconcileResult) {
+	// Not spawned yet - the spawn queue still owns this meeting
+	if meeting.RecordingSessionID == nil || *meeting.RecordingSessionID == "" {
+		result.Skipped++
+		return
+	}
+
+	// Bot runs on another bot-manager's Docker host - that replica reconciles it
+	if !r.orchestrator.OwnsMeeting(meeting) {
+		result.Skipped++
+		return
+	}
+
+	sessionID := *meeting.RecordingSessionID
+	containerID := sessionID
+	if meeting.BotContainerID != nil && *meeting.BotContainerID != "" {
+		containerID = *meeting.BotContainerID
+	}
+
+	logger := log.With().
+		Int64("meeting_id", meeting.ID).
+		Str("session_id", sessionID).
+		Str("status", string(meeting.Status)).
+		Logger()
+
+	state, err := r.orchestrator.InspectBot(ctx, containerID)
+	if err != nil && !errors.Is(err, ErrBotNotFound) {
+		// Docker unavailable - leave the meeting alone rather than guessing
+		logger.Error().Err(err).Msg("Failed to inspect bot container - skipping")
+		result.Skipped++
+		return
+	}
+
+	if state != nil && state.Running {
+		logger.Info().Msg("Bot still running - re-attaching status listener")
+		r.listener.StartListening(sessionID, meeting.ID)
+		result.Reattached++
+		return
+	}
+
+	reason := "Bot container no longer exists"
+	if state != nil {
+		reason = fmt.Sprintf("Bot container %s with exit code %d", state.Status, state.ExitCode)
+	}
+
+	if r.listener.resolveGoneBot(meeting, sessionID, reason+" (detected on bot-manager startup)") == types.StatusFinalizing {
+		result.Finalized++
+	} else {
+		result.Failed++
+	}
+}
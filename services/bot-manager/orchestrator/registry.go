@@ -0,0 +1,117 @@
+package orchestrator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ListenerRegistry tracks running status listeners by session ID so they can be
+// cancelled individually (when a recording ends) or all at once (on shutdown).
+type ListenerRegistry struct {
+	mu        sync.Mutex
+	listeners map[string]*registeredListener
+	wg        sync.WaitGroup
+	draining  bool
+}
+
+type registeredListener struct {
+	cancel context.CancelFunc
+}
+
+// NewListenerRegistry creates an empty listener registry
+func NewListenerRegistry() *ListenerRegistry {
+	return &ListenerRegistry{
+		listeners: map[string]*registeredListener{},
+	}
+}
+
+// Start runs listen in a goroutine under a cancellable context registered for sessionID.
+// Returns false if a listener for the session is already running or the registry is draining.
+func (r *ListenerRegistry) Start(sessionID string, listen func(ctx context.Context) error) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.draining {
+		return false
+	}
+	if _, exists := r.listeners[sessionID]; exists {
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	entry := &registeredListener{cancel: cancel}
+	r.listeners[sessionID] = entry
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer r.remove(sessionID, entry)
+
+		if err := listen(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Str("session_id", sessionID).Msg("Status listener stopped")
+		}
+	}()
+
+	return true
+}
+
+// Stop cancels the listener for sessionID. It does not wait for it to exit,
+// so it is safe to call from within the listener itself.
+func (r *ListenerRegistry) Stop(sessionID string) bool {
+	r.mu.Lock()
+	entry, exists := r.listeners[sessionID]
+	if exists {
+		delete(r.listeners, sessionID)
+	}
+	r.mu.Unlock()
+
+	if exists {
+		entry.cancel()
+	}
+	return exists
+}
+
+// Count returns the number of running listeners
+func (r *ListenerRegistry) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.listeners)
+}
+
+// Drain cancels all listeners, rejects new ones and waits up to timeout for them to exit
+func (r *ListenerRegistry) Drain(timeout time.Duration) {
+	r.mu.Lock()
+	r.draining = true
+	for sessionID, entry := range r.listeners {
+		entry.cancel()
+		delete(r.listeners, sessionID)
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Info().Msg("All status listeners stopped")
+	case <-time.After(timeout):
+		log.Warn().Dur("timeout", timeout).Msg("Timed out waiting for status listeners to stop")
+	}
+}
+
+// remove drops the entry for sessionID if it still belongs to the exiting listener
+func (r *ListenerRegistry) remove(sessionID string, entry *registeredListener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if current, exists := r.listeners[sessionID]; exists && current == entry {
+		delete(r.listeners, sessionID)
+		entry.cancel()
+	}
+}
@@ -0,0 +1,86 @@
+package spawner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/redis"
+	"github.com/newar/insights/shared/types"
+)
+
+// Consume processes spawn jobs from the queue until ctx is cancelled.
+// Failed spawns are retried with backoff; once a job is dead-lettered the
+// meeting is marked as failed.
+func (s *Spawner) Consume(ctx context.Context, queue *redis.JobQueue, consumerName string) error {
+	return queue.Consume(ctx, redis.ConsumerConfig{
+		Name:          consumerName,
+		Concurrency:   constants.SpawnWorkerConcurrency,
+		MaxDeliveries: constants.SpawnMaxAttempts,
+		JobTimeout:    constants.SpawnJobTimeout,
+		RetryBackoff:  constants.SpawnRetryBackoff,
+		MaxBackoff:    constants.SpawnMaxBackoff,
+		OnDeadLetter:  s.handleDeadLetter,
+	}, s.handleJob)
+}
+
+// handleJob spawns the bot for a single queued request
+func (s *Spawner) handleJob(ctx context.Context, job redis.Job) error {
+	var req types.SpawnBotRequest
+	if err := job.Decode(&req); err != nil {
+		return &redis.PermanentJobError{Err: fmt.Errorf("invalid spawn request: %w", err)}
+	}
+
+	_, err := s.Spawn(ctx, req)
+	if errors.Is(err, ErrNotAwaitingBot) {
+		log.Info().Int64("meeting_id", req.MeetingID).Err(err).Msg("Spawn skipped")
+		return nil
+	}
+
+	// Persist every attempt so stuck recordings can be diagnosed
+	var lastError *string
+	if err != nil {
+		errMsg := err.Error()
+		lastError = &errMsg
+	}
+	if _, recErr := s.meetingRepo.RecordSpawnAttempt(ctx, req.MeetingID, lastError); recErr != nil {
+		log.Warn().Err(recErr).Int64("meeting_id", req.MeetingID).Msg("Failed to record spawn attempt")
+	}
+
+	if errors.Is(err, ErrMeetingNotFound) || errors.Is(err, ErrUserNotFound) {
+		return &redis.PermanentJobError{Err: err}
+	}
+
+	return err
+}
+
+// handleDeadLetter marks the meeting as failed once its spawn job is given up
+func (s *Spawner) handleDeadLetter(job redis.Job, jobErr error) {
+	var req types.SpawnBotRequest
+	if err := job.Decode(&req); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	errMsg := fmt.Sprintf("Failed to spawn bot after %d attempt(s): %s", job.Deliveries, jobErr.Error())
+	err := s.meetingRepo.UpdateStatus(ctx, req.MeetingID, types.StatusFailed, nil, &errMsg, nil)
+
+	var transitionErr *database.InvalidTransitionError
+	if errors.As(err, &transitionErr) {
+		// Meeting already ended (e.g. cancelled while the spawn was retrying)
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Int64("meeting_id", req.MeetingID).Msg("Failed to mark meeting as failed")
+		return
+	}
+
+	s.notifier.Notify(ctx, req.MeetingID, types.StatusFailed)
+	s.publisher.PublishStatusChanged(ctx, req.MeetingID, req.UserID, types.StatusFailed, &errMsg, 0)
+}
@@ -0,0 +1,157 @@
+package spawner
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/services/bot-manager/interfaces"
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/redis"
+	"github.com/newar/insights/shared/types"
+)
+
+// Scheduler releases scheduled recordings when they are due.
+// Due meetings move from "scheduled" to "requested" and their spawn is queued,
+// so they go through the same retries and dead-lettering as immediate recordings.
+// Like immediate recordings, they are held to the owner's max_concurrent_bots.
+// Requested meetings whose spawn job was lost are re-queued.
+type Scheduler struct {
+	meetingRepo *database.MeetingRepository
+	queue       *redis.JobQueue
+	notifier    interfaces.StatusNotifier
+	publisher   interfaces.EventPublisher
+	interval    time.Duration
+}
+
+// NewScheduler creates a new recording scheduler
+func NewScheduler(meetingRepo *database.MeetingRepository, queue *redis.JobQueue, notifier interfaces.StatusNotifier, publisher interfaces.EventPublisher) *Scheduler {
+	return &Scheduler{
+		meetingRepo: meetingRepo,
+		queue:       queue,
+		notifier:    notifier,
+		publisher:   publisher,
+		interval:    constants.SchedulerInterval,
+	}
+}
+
+// Run releases due recordings on every interval until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	log.Info().Dur("interval", s.interval).Msg("Recording scheduler started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Recording scheduler stopped")
+			return
+		case <-ticker.C:
+			if err := s.Tick(ctx); err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("Scheduler pass failed")
+			}
+		}
+	}
+}
+
+// Tick runs a single scheduler pass
+func (s *Scheduler) Tick(ctx context.Context) error {
+	now := time.Now()
+
+	// Meetings that should have started long ago are not joined late
+	missedMsg := "Scheduled start time missed"
+	missed, err := s.meetingRepo.FailMissedSchedules(ctx, now.Add(-constants.ScheduleMissedWindow), missedMsg)
+	if err != nil {
+		return err
+	}
+	for _, id := range missed {
+		log.Warn().Int64("meeting_id", id).Msg("Scheduled recording missed its start time")
+		s.announce(ctx, id, types.MeetingStatusFailed, &missedMsg)
+	}
+
+	// Spawn jobs lost between a meeting being requested and its job being queued
+	// (e.g. a crash right after ReleaseDue) are queued again
+	stalled, err := s.meetingRepo.ReclaimStalledRequests(ctx, now.Add(-constants.SpawnStallTimeout), constants.SchedulerBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, meeting := range stalled {
+		log.Warn().Int64("meeting_id", meeting.ID).Msg("Re-queueing spawn of requested meeting without a bot")
+		s.enqueue(ctx, meeting)
+	}
+
+	// Release in batches until nothing more can be released. Meetings of users at
+	// their concurrent bot limit stay scheduled and are retried on the next pass
+	// (and failed as missed if no bot frees up in time).
+	for {
+		meetings, err := s.meetingRepo.ReleaseDue(ctx, now.Add(constants.ScheduleLeadTime), constants.SchedulerBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, meeting := range meetings {
+			s.release(ctx, meeting)
+		}
+
+		if len(meetings) == 0 || ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// release queues the bot spawn for a meeting that was just moved to "requested"
+func (s *Scheduler) release(ctx context.Context, meeting *types.Meeting) {
+	if !s.enqueue(ctx, meeting) {
+		return
+	}
+
+	log.Info().
+		Int64("meeting_id", meeting.ID).
+		Int64("user_id", meeting.UserID).
+		Time("scheduled_at", *meeting.ScheduledAt).
+		Msg("Scheduled recording released")
+
+	s.publisher.PublishStatusChanged(ctx, meeting.ID, meeting.UserID, types.MeetingStatusRequested, nil, 0)
+}
+
+// enqueue queues the bot spawn for a requested meeting. If that fails the meeting
+// is marked as failed and false is returned.
+func (s *Scheduler) enqueue(ctx context.Context, meeting *types.Meeting) bool {
+	spawnReq := types.SpawnBotRequest{
+		MeetingID:  meeting.ID,
+		UserID:     meeting.UserID,
+		Platform:   meeting.Platform,
+		MeetingURL: meeting.MeetingURL,
+	}
+	if meeting.BotName != nil {
+		spawnReq.BotName = *meeting.BotName
+	}
+
+	if _, err := s.queue.Enqueue(ctx, spawnReq); err != nil {
+		log.Error().Err(err).Int64("meeting_id", meeting.ID).Msg("Failed to enqueue scheduled bot spawn")
+
+		errMsg := "Failed to queue bot spawn"
+		if err := s.meetingRepo.UpdateStatus(ctx, meeting.ID, types.StatusFailed, nil, &errMsg, nil); err != nil {
+			log.Error().Err(err).Int64("meeting_id", meeting.ID).Msg("Failed to mark meeting as failed")
+			return false
+		}
+		s.announce(ctx, meeting.ID, types.MeetingStatusFailed, &errMsg)
+		return false
+	}
+
+	return true
+}
+
+// announce sends webhooks and publishes the status change for a meeting
+func (s *Scheduler) announce(ctx context.Context, meetingID int64, status types.MeetingStatus, errorMsg *string) {
+	s.notifier.Notify(ctx, meetingID, status)
+
+	meeting, err := s.meetingRepo.GetByID(ctx, meetingID)
+	if err != nil {
+		return
+	}
+	s.publisher.PublishStatusChanged(ctx, meetingID, meeting.UserID, status, errorMsg, 0)
+}
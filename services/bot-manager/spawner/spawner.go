@@ -0,0 +1,151 @@
+package spawner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/services/bot-manager/interfaces"
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/types"
+)
+
+var (
+	// ErrMeetingNotFound is returned when the meeting to spawn a bot for does not exist
+	ErrMeetingNotFound = errors.New("meeting not found")
+
+	// ErrUserNotFound is returned when the meeting owner does not exist
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrNotAwaitingBot is returned when the meeting is no longer waiting for a bot
+	// (e.g. it was cancelled before the spawn was processed)
+	ErrNotAwaitingBot = errors.New("meeting is not awaiting a bot")
+)
+
+// Spawner starts recording bots for meetings.
+// It is used by both the HTTP spawn endpoint and the spawn queue consumer.
+type Spawner struct {
+	orchestrator interfaces.BotOrchestrator
+	listener     interfaces.BotListener
+	meetingRepo  interfaces.MeetingRepository
+	userRepo     interfaces.UserRepository
+	notifier     interfaces.StatusNotifier
+	publisher    interfaces.EventPublisher
+}
+
+// NewSpawner creates a new spawner
+func NewSpawner(orchestrator interfaces.BotOrchestrator, listener interfaces.BotListener, meetingRepo interfaces.MeetingRepository, userRepo interfaces.UserRepository, notifier interfaces.StatusNotifier, publisher interfaces.EventPublisher) *Spawner {
+	return &Spawner{
+		orchestrator: orchestrator,
+		listener:     listener,
+		meetingRepo:  meetingRepo,
+		userRepo:     userRepo,
+		notifier:     notifier,
+		publisher:    publisher,
+	}
+}
+
+// Spawn starts a bot for the requested meeting and returns the updated meeting.
+// Spawning is idempotent: if the meeting already has a bot, it is returned unchanged.
+func (s *Spawner) Spawn(ctx context.Context, req types.SpawnBotRequest) (*types.Meeting, error) {
+	// Get meeting from database by ID
+	meeting, err := s.meetingRepo.GetByID(ctx, req.MeetingID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMeetingNotFound, err)
+	}
+
+	// Spawns may be redelivered, so a repeated request must not start a second bot
+	if meeting.BotContainerID != nil && *meeting.BotContainerID != "" {
+		log.Info().
+			Int64("meeting_id", req.MeetingID).
+			Str("container_id", *meeting.BotContainerID).
+			Msg("Bot already spawned for meeting")
+		return meeting, nil
+	}
+
+	if meeting.Status != types.StatusRequested {
+		return nil, fmt.Errorf("%w (status: %s)", ErrNotAwaitingBot, meeting.Status)
+	}
+
+	// Get user from database
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUserNotFound, err)
+	}
+
+	// Spawn bot container (orchestrator fills in container and session IDs)
+	if err := s.orchestrator.SpawnBot(ctx, meeting, user); err != nil {
+		return nil, fmt.Errorf("failed to spawn bot container: %w", err)
+	}
+
+	// Persist container and session IDs so the bot can be addressed later. Duplicate
+	// jobs for one meeting may spawn concurrently; only the first bot is attached.
+	attached, err := s.meetingRepo.AttachBot(ctx, meeting.ID, *meeting.BotContainerID, *meeting.RecordingSessionID, *meeting.BotHost)
+	if err != nil {
+		// The spawn is retried, and the retry can't see this bot - remove it so the
+		// meeting doesn't end up with two
+		s.removeUntracked(*meeting.BotContainerID, meeting.ID)
+		return nil, fmt.Errorf("failed to store bot container ID: %w", err)
+	}
+	if !attached {
+		s.removeUntracked(*meeting.BotContainerID, meeting.ID)
+		return s.alreadySpawned(ctx, meeting.ID)
+	}
+
+	// Start listening for status updates from this recording session
+	sessionID := *meeting.RecordingSessionID
+	s.listener.StartListening(sessionID, meeting.ID)
+
+	s.publisher.Publish(ctx, types.EventBotSpawned, meeting.ID, meeting.UserID, types.BotSpawnedPayload{
+		ContainerID: *meeting.BotContainerID,
+		SessionID:   sessionID,
+	})
+
+	log.Info().
+		Int64("meeting_id", req.MeetingID).
+		Str("session_id", sessionID).
+		Msg("Bot spawned successfully")
+
+	return meeting, nil
+}
+
+// alreadySpawned returns a meeting whose bot was attached by a concurrent spawn
+func (s *Spawner) alreadySpawned(ctx context.Context, meetingID int64) (*types.Meeting, error) {
+	meeting, err := s.meetingRepo.GetByID(ctx, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMeetingNotFound, err)
+	}
+
+	if meeting.BotContainerID == nil || *meeting.BotContainerID == "" {
+		return nil, fmt.Errorf("%w (status: %s)", ErrNotAwaitingBot, meeting.Status)
+	}
+
+	log.Info().
+		Int64("meeting_id", meetingID).
+		Str("container_id", *meeting.BotContainerID).
+		Msg("Bot already spawned for meeting by a concurrent spawn")
+	return meeting, nil
+}
+
+// removeUntracked removes a bot container whose ID could not be stored
+func (s *Spawner) removeUntracked(containerID string, meetingID int64) {
+	// The spawn's context may be what failed
+	ctx, cancel := context.WithTimeout(context.Background(), constants.BotShutdownTimeout)
+	defer cancel()
+
+	if err := s.orchestrator.RemoveBot(ctx, containerID); err != nil {
+		log.Error().
+			Err(err).
+			Int64("meeting_id", meetingID).
+			Str("container_id", containerID).
+			Msg("Failed to remove untracked bot container")
+		return
+	}
+
+	log.Warn().
+		Int64("meeting_id", meetingID).
+		Str("container_id", containerID).
+		Msg("Removed bot container that could not be tracked")
+}
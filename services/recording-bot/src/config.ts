@@ -1,4 +1,5 @@
 // Configuration loaded from environment variables
+import { S3Config } from './s3';
 
 export interface Config {
   meetingId: number;
@@ -7,8 +8,10 @@ export interface Config {
   meetingUrl: string;
   botName: string;
   redisUrl: string;
-  storageType: 'local' | 'supabase';
+  storageType: 'local' | 'supabase' | 's3';
   storagePath: string;
+  chunksDir: string; // Chunk folder within the storage root
+  s3?: S3Config; // Remote storage (supabase, s3)
   chunkDuration: number; // seconds
   audioBitrate: number;
 }
@@ -20,8 +23,9 @@ export function loadConfig(): Config {
   const meetingUrl = process.env.MEETING_URL || '';
   const botName = process.env.BOT_NAME || 'Newar Recorder';
   const redisUrl = process.env.REDIS_URL || 'redis://localhost:6379';
-  const storageType = (process.env.STORAGE_TYPE || 'local') as 'local' | 'supabase';
+  const storageType = (process.env.STORAGE_TYPE || 'local') as 'local' | 'supabase' | 's3';
   const storagePath = process.env.STORAGE_PATH || './storage/recordings';
+  const chunksDir = process.env.STORAGE_CHUNKS_DIR || 'temp';
   const chunkDuration = parseInt(process.env.CHUNK_DURATION || '10');
   const audioBitrate = parseInt(process.env.AUDIO_BITRATE || '128000');
 
@@ -30,6 +34,20 @@ export function loadConfig(): Config {
     throw new Error('Missing required environment variables: MEETING_ID, USER_ID, MEETING_URL');
   }
 
+  let s3: S3Config | undefined;
+  if (storageType !== 'local') {
+    s3 = {
+      endpoint: process.env.STORAGE_ENDPOINT || '',
+      bucket: process.env.STORAGE_BUCKET || '',
+      region: process.env.STORAGE_REGION || 'us-east-1',
+      accessKey: process.env.STORAGE_ACCESS_KEY || '',
+      secretKey: process.env.STORAGE_SECRET_KEY || '',
+    };
+    if (!s3.endpoint || !s3.bucket || !s3.accessKey || !s3.secretKey) {
+      throw new Error('Missing required environment variables: STORAGE_ENDPOINT, STORAGE_BUCKET, STORAGE_ACCESS_KEY, STORAGE_SECRET_KEY');
+    }
+  }
+
   return {
     meetingId,
     userId,
@@ -39,6 +57,8 @@ export function loadConfig(): Config {
     redisUrl,
     storageType,
     storagePath,
+    chunksDir,
+    s3,
     chunkDuration,
     audioBitrate,
   };
@@ -84,7 +84,7 @@ async function main() {
     console.log('✅ Browser launched');
 
     // Initialize storage uploader
-    const uploader = new ChunkUploader(config.storagePath, config.meetingId);
+    const uploader = new ChunkUploader(config);
     await uploader.initialize();
 
     // Join meeting based on platform
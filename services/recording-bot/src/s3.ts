@@ -0,0 +1,66 @@
+// Minimal S3 client for chunk uploads (Supabase Storage, MinIO or any S3-compatible service)
+import * as crypto from 'crypto';
+
+export interface S3Config {
+  endpoint: string; // e.g. https://<project>.storage.supabase.co/storage/v1/s3
+  bucket: string;
+  region: string;
+  accessKey: string;
+  secretKey: string;
+}
+
+function sha256(data: string | Buffer): string {
+  return crypto.createHash('sha256').update(data).digest('hex');
+}
+
+function hmac(key: string | Buffer, data: string): Buffer {
+  return crypto.createHmac('sha256', key).update(data).digest();
+}
+
+// RFC 3986 encoding of one path segment, as SigV4 expects
+function encodeSegment(segment: string): string {
+  return encodeURIComponent(segment).replace(/[!'()*]/g, c => `%${c.charCodeAt(0).toString(16).toUpperCase()}`);
+}
+
+// putObject uploads a file with a path-style, SigV4-signed PUT request
+export async function putObject(config: S3Config, key: string, body: Buffer, contentType: string): Promise<void> {
+  const endpoint = new URL(config.endpoint);
+  const objectPath = [config.bucket, ...key.split('/')].map(encodeSegment).join('/');
+  const canonicalUri = `${endpoint.pathname.replace(/\/$/, '')}/${objectPath}`;
+
+  const amzDate = new Date().toISOString().replace(/[:-]|\.\d{3}/g, ''); // 20261017T120000Z
+  const dateStamp = amzDate.slice(0, 8);
+  const payloadHash = sha256(body);
+
+  const headers: Record<string, string> = {
+    'content-type': contentType,
+    host: endpoint.host,
+    'x-amz-content-sha256': payloadHash,
+    'x-amz-date': amzDate,
+  };
+  const signedHeaders = Object.keys(headers).sort().join(';');
+  const canonicalHeaders = Object.keys(headers).sort().map(name => `${name}:${headers[name]}\n`).join('');
+
+  const canonicalRequest = ['PUT', canonicalUri, '', canonicalHeaders, signedHeaders, payloadHash].join('\n');
+  const scope = `${dateStamp}/${config.region}/s3/aws4_request`;
+  const stringToSign = ['AWS4-HMAC-SHA256', amzDate, scope, sha256(canonicalRequest)].join('\n');
+
+  const signingKey = hmac(hmac(hmac(hmac(`AWS4${config.secretKey}`, dateStamp), config.region), 's3'), 'aws4_request');
+  const signature = crypto.createHmac('sha256', signingKey).update(stringToSign).digest('hex');
+
+  const response = await fetch(`${endpoint.origin}${canonicalUri}`, {
+    method: 'PUT',
+    headers: {
+      'Content-Type': contentType,
+      'X-Amz-Content-Sha256': payloadHash,
+      'X-Amz-Date': amzDate,
+      Authorization: `AWS4-HMAC-SHA256 Credential=${config.accessKey}/${scope}, SignedHeaders=${signedHeaders}, Signature=${signature}`,
+    },
+    body,
+  });
+
+  if (!response.ok) {
+    const text = await response.text();
+    throw new Error(`S3 upload of ${key} failed: ${response.status} ${text.slice(0, 200)}`);
+  }
+}
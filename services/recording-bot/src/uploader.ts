@@ -1,18 +1,31 @@
 import * as fs from 'fs';
 import * as path from 'path';
+import { Config } from './config';
+import { putObject, S3Config } from './s3';
+
+const UPLOAD_ATTEMPTS = 3;
 
 export class ChunkUploader {
   private storagePath: string;
   private meetingId: number;
   private tempDir: string;
+  private chunkPrefix: string; // Storage path of the chunk folder
+  private s3?: S3Config;
 
-  constructor(storagePath: string, meetingId: number) {
-    this.storagePath = storagePath;
-    this.meetingId = meetingId;
-    this.tempDir = path.join(storagePath, 'temp', `meeting_${meetingId}`);
+  constructor(config: Config) {
+    this.storagePath = config.storagePath;
+    this.meetingId = config.meetingId;
+    this.tempDir = path.join(config.storagePath, config.chunksDir, `meeting_${config.meetingId}`);
+    this.chunkPrefix = `${config.chunksDir}/meeting_${config.meetingId}`;
+    this.s3 = config.storageType === 'local' ? undefined : config.s3;
   }
 
   async initialize(): Promise<void> {
+    if (this.s3) {
+      console.log(`✅ Uploading chunks to ${this.s3.bucket}/${this.chunkPrefix}`);
+      return;
+    }
+
     // Ensure temp directory exists
     await fs.promises.mkdir(this.tempDir, { recursive: true });
     console.log(`✅ Initialized storage at ${this.tempDir}`);
@@ -20,14 +33,33 @@ export class ChunkUploader {
 
   async uploadChunk(blob: Buffer, chunkIndex: number): Promise<void> {
     const fileName = `chunk_${String(chunkIndex).padStart(5, '0')}.webm`;
-    const filePath = path.join(this.tempDir, fileName);
 
-    await fs.promises.writeFile(filePath, blob);
+    if (this.s3) {
+      await this.uploadRemote(`${this.chunkPrefix}/${fileName}`, blob);
+    } else {
+      await fs.promises.writeFile(path.join(this.tempDir, fileName), blob);
+    }
 
     const sizeKB = (blob.length / 1024).toFixed(2);
     console.log(`✅ Uploaded ${fileName} (${sizeKB} KB)`);
   }
 
+  // Remote uploads are retried; a lost chunk leaves a gap in the recording
+  private async uploadRemote(key: string, blob: Buffer): Promise<void> {
+    for (let attempt = 1; ; attempt++) {
+      try {
+        await putObject(this.s3!, key, blob, 'audio/webm');
+        return;
+      } catch (error) {
+        if (attempt >= UPLOAD_ATTEMPTS) {
+          throw error;
+        }
+        console.warn(`⚠️  Upload of ${key} failed (attempt ${attempt}/${UPLOAD_ATTEMPTS}):`, error);
+        await new Promise(resolve => setTimeout(resolve, attempt * 1000));
+      }
+    }
+  }
+
   async cleanup(): Promise<void> {
     // Cleanup is handled by bot-manager after finalization
     console.log(`ℹ️  Temp files will be cleaned up by bot-manager`);
@@ -28,6 +28,16 @@ func (a *UserAdapter) ToEntity(dto *types.User) (*entities.User, error) {
 		return nil, err
 	}
 
+	// Restore custom data so saving the entity does not drop it
+	if len(dto.Data) > 0 {
+		var data map[string]interface{}
+		if err := json.Unmarshal(dto.Data, &data); err == nil {
+			for key, value := range data {
+				user.SetData(key, value)
+			}
+		}
+	}
+
 	return user, nil
 }
 
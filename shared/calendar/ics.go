@@ -0,0 +1,276 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	_ "time/tzdata" // TZID lookups must not depend on the host's zoneinfo
+)
+
+// Event is a VEVENT from an iCalendar file
+type Event struct {
+	UID          string
+	Summary      string
+	Location     string
+	Description  string
+	Status       string // TENTATIVE, CONFIRMED or CANCELLED
+	Start        time.Time
+	AllDay       bool
+	RRule        string      // Raw RRULE value, empty for single events
+	RDates       []time.Time // Extra occurrences
+	ExDates      []time.Time // Excluded occurrences
+	RecurrenceID *time.Time  // Set on overrides of a single occurrence of a recurring event
+}
+
+// Cancelled reports whether the event was cancelled by the organizer
+func (e *Event) Cancelled() bool {
+	return strings.EqualFold(e.Status, "CANCELLED")
+}
+
+// property is a single content line: NAME;PARAM=value:VALUE
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the VEVENTs from an iCalendar (RFC 5545) stream.
+// Components nested in events (e.g. VALARM) and other top-level components are ignored.
+func Parse(r io.Reader) ([]*Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*Event{}
+	var current *Event
+	depth := 0 // Nesting inside the current VEVENT
+	sawCalendar := false
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			if strings.EqualFold(prop.value, "VCALENDAR") {
+				sawCalendar = true
+			}
+			if current != nil {
+				depth++
+			} else if strings.EqualFold(prop.value, "VEVENT") {
+				current = &Event{}
+			}
+			continue
+
+		case "END":
+			if current == nil {
+				continue
+			}
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if current.UID == "" {
+				return nil, fmt.Errorf("line %d: event without UID", i+1)
+			}
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("line %d: event %s without DTSTART", i+1, current.UID)
+			}
+			events = append(events, current)
+			current = nil
+			continue
+		}
+
+		if current == nil || depth > 0 {
+			continue
+		}
+
+		if err := current.apply(prop); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+
+	if !sawCalendar {
+		return nil, fmt.Errorf("not an iCalendar file (missing BEGIN:VCALENDAR)")
+	}
+
+	return events, nil
+}
+
+// apply sets the event field for a property
+func (e *Event) apply(prop property) error {
+	switch prop.name {
+	case "UID":
+		e.UID = prop.value
+	case "SUMMARY":
+		e.Summary = unescapeText(prop.value)
+	case "LOCATION":
+		e.Location = unescapeText(prop.value)
+	case "DESCRIPTION":
+		e.Description = unescapeText(prop.value)
+	case "STATUS":
+		e.Status = strings.ToUpper(prop.value)
+	case "RRULE":
+		e.RRule = prop.value
+
+	case "DTSTART":
+		start, allDay, err := parseDateTime(prop)
+		if err != nil {
+			return fmt.Errorf("invalid DTSTART: %w", err)
+		}
+		e.Start = start
+		e.AllDay = allDay
+
+	case "RECURRENCE-ID":
+		id, _, err := parseDateTime(prop)
+		if err != nil {
+			return fmt.Errorf("invalid RECURRENCE-ID: %w", err)
+		}
+		e.RecurrenceID = &id
+
+	case "EXDATE", "RDATE":
+		for _, value := range strings.Split(prop.value, ",") {
+			t, _, err := parseDateTime(property{name: prop.name, params: prop.params, value: value})
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", prop.name, err)
+			}
+			if prop.name == "EXDATE" {
+				e.ExDates = append(e.ExDates, t)
+			} else {
+				e.RDates = append(e.RDates, t)
+			}
+		}
+	}
+	return nil
+}
+
+// unfold reads content lines, joining continuation lines (RFC 5545 section 3.1)
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	lines := []string{}
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseProperty splits a content line into name, parameters and value.
+// Parameter values may be quoted and contain ':' or ';'.
+func parseProperty(line string) (property, error) {
+	prop := property{params: map[string]string{}}
+
+	inQuotes := false
+	valueStart := -1
+	for i, ch := range line {
+		if ch == '"' {
+			inQuotes = !inQuotes
+		} else if ch == ':' && !inQuotes {
+			valueStart = i
+			break
+		}
+	}
+	if valueStart < 0 {
+		return prop, fmt.Errorf("malformed content line")
+	}
+
+	head := line[:valueStart]
+	prop.value = line[valueStart+1:]
+
+	parts := splitParams(head)
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			continue
+		}
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+
+	return prop, nil
+}
+
+// splitParams splits "NAME;A=1;B="x;y"" on unquoted semicolons
+func splitParams(head string) []string {
+	parts := []string{}
+	inQuotes := false
+	last := 0
+	for i, ch := range head {
+		if ch == '"' {
+			inQuotes = !inQuotes
+		} else if ch == ';' && !inQuotes {
+			parts = append(parts, head[last:i])
+			last = i + 1
+		}
+	}
+	return append(parts, head[last:])
+}
+
+// parseDateTime parses a DATE or DATE-TIME value, honouring TZID.
+// Floating times and unknown time zones are treated as UTC.
+func parseDateTime(prop property) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+
+	if prop.params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, time.UTC)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	loc := time.UTC
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// unescapeText reverses TEXT value escaping (RFC 5545 section 3.3.11)
+func unescapeText(value string) string {
+	var b strings.Builder
+	escaped := false
+	for _, ch := range value {
+		if escaped {
+			switch ch {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(ch)
+			}
+			escaped = false
+			continue
+		}
+		if ch == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
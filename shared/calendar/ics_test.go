@@ -0,0 +1,179 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:weekly@example.com",
+		"SUMMARY:Weekly sync\\, team",
+		"DESCRIPTION:Join: https://meet.google.com/abc-defg-hij\\nSee you",
+		"LOCATION:Room 1",
+		"DTSTART;TZID=Europe/Berlin:20261020T100000",
+		"RRULE:FREQ=WEEKLY;COUNT=4",
+		"EXDATE;TZID=Europe/Berlin:20261027T100000,20261103T100000",
+		"RDATE:20261105T090000Z",
+		"BEGIN:VALARM",
+		"UID:alarm-must-not-override",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:weekly@example.com",
+		"RECURRENCE-ID;TZID=Europe/Berlin:20261110T100000",
+		"DTSTART;TZID=Europe/Berlin:20261110T140000",
+		"SUMMARY:Weekly sync (moved)",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:folded@example.com",
+		"DTSTART:20261021T080000Z",
+		"SUMMARY:A very long summary that the calendar app",
+		"  folded over two lines",
+		"STATUS:cancelled",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:all-day@example.com",
+		"DTSTART;VALUE=DATE:20261022",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"UID:todo@example.com",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	events, err := Parse(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("Parse() returned %d events, want 4", len(events))
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	weekly := events[0]
+	if weekly.UID != "weekly@example.com" {
+		t.Errorf("UID = %q, VALARM properties must not leak into the event", weekly.UID)
+	}
+	if weekly.Summary != "Weekly sync, team" {
+		t.Errorf("Summary = %q", weekly.Summary)
+	}
+	if weekly.Description != "Join: https://meet.google.com/abc-defg-hij\nSee you" {
+		t.Errorf("Description = %q", weekly.Description)
+	}
+	if want := time.Date(2026, 10, 20, 10, 0, 0, 0, berlin); !weekly.Start.Equal(want) || weekly.Start.Location().String() != "Europe/Berlin" {
+		t.Errorf("Start = %v, want %v", weekly.Start, want)
+	}
+	if weekly.RRule != "FREQ=WEEKLY;COUNT=4" {
+		t.Errorf("RRule = %q", weekly.RRule)
+	}
+	if len(weekly.ExDates) != 2 || !weekly.ExDates[1].Equal(time.Date(2026, 11, 3, 10, 0, 0, 0, berlin)) {
+		t.Errorf("ExDates = %v", weekly.ExDates)
+	}
+	if len(weekly.RDates) != 1 || !weekly.RDates[0].Equal(time.Date(2026, 11, 5, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("RDates = %v", weekly.RDates)
+	}
+	if weekly.RecurrenceID != nil {
+		t.Errorf("RecurrenceID = %v, want nil", weekly.RecurrenceID)
+	}
+
+	override := events[1]
+	if override.RecurrenceID == nil || !override.RecurrenceID.Equal(time.Date(2026, 11, 10, 10, 0, 0, 0, berlin)) {
+		t.Errorf("override RecurrenceID = %v", override.RecurrenceID)
+	}
+
+	folded := events[2]
+	if folded.Summary != "A very long summary that the calendar app folded over two lines" {
+		t.Errorf("folded Summary = %q", folded.Summary)
+	}
+	if !folded.Cancelled() {
+		t.Errorf("Cancelled() = false for STATUS:cancelled")
+	}
+
+	allDay := events[3]
+	if !allDay.AllDay || !allDay.Start.Equal(time.Date(2026, 10, 22, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("all-day event: AllDay = %v, Start = %v", allDay.AllDay, allDay.Start)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ics  string
+	}{
+		{"not a calendar", "hello world"},
+		{"empty", ""},
+		{"event without UID", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20261020T100000Z\nEND:VEVENT\nEND:VCALENDAR"},
+		{"event without DTSTART", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:x\nEND:VEVENT\nEND:VCALENDAR"},
+		{"invalid DTSTART", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:x\nDTSTART:tomorrow\nEND:VEVENT\nEND:VCALENDAR"},
+		{"malformed line", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:x\nNO COLON HERE\nEND:VEVENT\nEND:VCALENDAR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.ics)); err == nil {
+				t.Errorf("Parse() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseProperty(t *testing.T) {
+	prop, err := parseProperty(`ATTENDEE;CN="Doe; Jane";ROLE=REQ-PARTICIPANT:mailto:jane@example.com`)
+	if err != nil {
+		t.Fatalf("parseProperty() error = %v", err)
+	}
+	if prop.name != "ATTENDEE" {
+		t.Errorf("name = %q", prop.name)
+	}
+	if prop.params["CN"] != "Doe; Jane" || prop.params["ROLE"] != "REQ-PARTICIPANT" {
+		t.Errorf("params = %v", prop.params)
+	}
+	if prop.value != "mailto:jane@example.com" {
+		t.Errorf("value = %q", prop.value)
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	tests := []struct {
+		name   string
+		params map[string]string
+		value  string
+		want   time.Time
+		allDay bool
+	}{
+		{"UTC", nil, "20261020T100000Z", time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC), false},
+		{"TZID", map[string]string{"TZID": "America/New_York"}, "20261020T100000", time.Date(2026, 10, 20, 10, 0, 0, 0, newYork), false},
+		{"unknown TZID is UTC", map[string]string{"TZID": "Custom/Zone"}, "20261020T100000", time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC), false},
+		{"floating is UTC", nil, "20261020T100000", time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC), false},
+		{"DATE value", map[string]string{"VALUE": "DATE"}, "20261020", time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), true},
+		{"bare date", nil, "20261020", time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			if params == nil {
+				params = map[string]string{}
+			}
+			got, allDay, err := parseDateTime(property{name: "DTSTART", params: params, value: tt.value})
+			if err != nil {
+				t.Fatalf("parseDateTime() error = %v", err)
+			}
+			if !got.Equal(tt.want) || allDay != tt.allDay {
+				t.Errorf("parseDateTime() = %v, %v; want %v, %v", got, allDay, tt.want, tt.allDay)
+			}
+		})
+	}
+}
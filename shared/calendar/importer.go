@@ -0,0 +1,237 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/domain/valueobjects"
+	"github.com/newar/insights/shared/events"
+	"github.com/newar/insights/shared/safehttp"
+	"github.com/newar/insights/shared/types"
+	"github.com/newar/insights/shared/utils"
+	"github.com/newar/insights/shared/webhooks"
+)
+
+var (
+	// ErrTooLarge is returned for calendars larger than constants.ICSMaxSize
+	ErrTooLarge = fmt.Errorf("calendar exceeds %d bytes", constants.ICSMaxSize)
+
+	// ErrInvalid is returned for calendars that cannot be parsed or expanded
+	ErrInvalid = errors.New("invalid calendar")
+
+	// ErrFetchFailed is returned when a calendar URL cannot be downloaded
+	ErrFetchFailed = errors.New("failed to fetch calendar")
+)
+
+// Options controls how imported occurrences become recordings
+type Options struct {
+	UserID      int64
+	FeedID      *int64 // Set when importing a feed
+	BotName     string
+	MaxDuration *int // seconds
+}
+
+// occurrenceKey identifies an imported occurrence (UID + original start)
+type occurrenceKey struct {
+	uid   string
+	start int64
+}
+
+// Importer turns calendar events with Google Meet or Teams links into
+// scheduled recordings. Each occurrence is imported at most once per user,
+// so re-importing a calendar or re-syncing a feed only adds new occurrences.
+type Importer struct {
+	meetingRepo *database.MeetingRepository
+	feedRepo    *database.CalendarFeedRepository
+	notifier    *webhooks.Notifier
+	publisher   *events.Publisher
+	client      *http.Client
+}
+
+// NewImporter creates a new calendar importer
+func NewImporter(meetingRepo *database.MeetingRepository, feedRepo *database.CalendarFeedRepository, notifier *webhooks.Notifier, publisher *events.Publisher) *Importer {
+	return &Importer{
+		meetingRepo: meetingRepo,
+		feedRepo:    feedRepo,
+		notifier:    notifier,
+		publisher:   publisher,
+		client:      safehttp.NewClient(constants.ICSFetchTimeout),
+	}
+}
+
+// Import creates scheduled recordings for the occurrences in an ICS stream
+// starting within the import horizon
+func (i *Importer) Import(ctx context.Context, r io.Reader, opts Options) (*types.CalendarImportResult, error) {
+	result, _, _, _, err := i.importCalendar(ctx, r, opts)
+	return result, err
+}
+
+// importCalendar imports a calendar and also returns the occurrences it contained
+// and the window [from, until) in which that list is complete (it may be truncated)
+func (i *Importer) importCalendar(ctx context.Context, r io.Reader, opts Options) (*types.CalendarImportResult, map[occurrenceKey]bool, time.Time, time.Time, error) {
+	data, err := io.ReadAll(io.LimitReader(r, constants.ICSMaxSize+1))
+	if err != nil {
+		return nil, nil, time.Time{}, time.Time{}, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	if len(data) > constants.ICSMaxSize {
+		return nil, nil, time.Time{}, time.Time{}, ErrTooLarge
+	}
+
+	parsed, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, time.Time{}, time.Time{}, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	from := time.Now().Add(constants.ScheduleLeadTime)
+	until := from.Add(constants.ICSImportHorizon)
+
+	occurrences, err := Expand(parsed, from, until, constants.ICSMaxOccurrences)
+	if err != nil {
+		return nil, nil, time.Time{}, time.Time{}, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	// A truncated list says nothing about occurrences after the last one kept
+	if len(occurrences) == constants.ICSMaxOccurrences {
+		until = occurrences[len(occurrences)-1].Start
+	}
+
+	result := &types.CalendarImportResult{
+		Events:      len(parsed),
+		Occurrences: len(occurrences),
+		Skipped:     []types.CalendarImportSkip{},
+		Meetings:    []*types.Meeting{},
+	}
+	seen := map[occurrenceKey]bool{}
+
+	for _, occurrence := range occurrences {
+		event := occurrence.Event
+		original := occurrence.OriginalStart()
+		seen[occurrenceKey{uid: event.UID, start: original.Unix()}] = true
+
+		req, reason := recordingRequest(occurrence, opts)
+		if reason != "" {
+			result.Skipped = append(result.Skipped, types.CalendarImportSkip{
+				UID:     event.UID,
+				Summary: event.Summary,
+				Start:   occurrence.Start,
+				Reason:  reason,
+			})
+			continue
+		}
+
+		meetingURL := utils.BuildMeetingURL(string(req.Platform), req.MeetingID)
+		meeting, created, err := i.meetingRepo.CreateFromCalendar(ctx, opts.UserID, req, meetingURL, opts.FeedID, event.UID, original)
+		if err != nil {
+			return nil, nil, time.Time{}, time.Time{}, err
+		}
+		if !created {
+			result.Duplicates++
+			continue
+		}
+
+		i.publisher.Publish(ctx, types.EventMeetingCreated, meeting.ID, opts.UserID, types.MeetingCreatedPayload{
+			Platform:        meeting.Platform,
+			NativeMeetingID: meeting.MeetingID,
+			MeetingURL:      meeting.MeetingURL,
+			BotName:         meeting.BotName,
+		})
+
+		result.Created++
+		result.Meetings = append(result.Meetings, meeting)
+	}
+
+	log.Info().
+		Int64("user_id", opts.UserID).
+		Int("events", result.Events).
+		Int("occurrences", result.Occurrences).
+		Int("created", result.Created).
+		Int("duplicates", result.Duplicates).
+		Int("skipped", len(result.Skipped)).
+		Msg("Calendar imported")
+
+	return result, seen, from, until, nil
+}
+
+// recordingRequest builds the scheduled recording for an occurrence.
+// Returns a skip reason instead when the event has no supported meeting link.
+func recordingRequest(occurrence Occurrence, opts Options) (types.CreateRecordingRequest, string) {
+	event := occurrence.Event
+
+	link, ok := valueobjects.FindMeetingURL(event.Location + "\n" + event.Description)
+	if !ok {
+		return types.CreateRecordingRequest{}, "no Google Meet or Teams link"
+	}
+
+	platform := types.PlatformTeams
+	if link.IsGoogleMeet() {
+		platform = types.PlatformGoogleMeet
+	}
+
+	meetingID := link.NativeMeetingID()
+	if meetingID == "" {
+		return types.CreateRecordingRequest{}, "invalid meeting link"
+	}
+	if len(meetingID) > 255 {
+		return types.CreateRecordingRequest{}, "meeting link too long"
+	}
+
+	start := occurrence.Start
+	return types.CreateRecordingRequest{
+		Platform:    platform,
+		MeetingID:   meetingID,
+		BotName:     opts.BotName,
+		ScheduledAt: &start,
+		MaxDuration: opts.MaxDuration,
+	}, ""
+}
+
+// ValidateFeedURL checks that a calendar feed URL is an absolute http(s) URL.
+// webcal:// links (as shared by most calendar apps) are accepted as https.
+// Feeds on private or loopback addresses are refused when they are fetched.
+func ValidateFeedURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", errors.New("invalid calendar URL")
+	}
+
+	switch u.Scheme {
+	case "webcal":
+		u.Scheme = "https"
+	case "http", "https":
+	default:
+		return "", errors.New("calendar URL must use http, https or webcal")
+	}
+
+	return u.String(), nil
+}
+
+// Fetch downloads an ICS feed
+func (i *Importer) Fetch(ctx context.Context, feedURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: HTTP %d", ErrFetchFailed, resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
@@ -0,0 +1,164 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/safehttp"
+)
+
+// feed serves an ICS calendar the way calendar apps publish them
+func feed(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/calendar" {
+			t.Errorf("Accept = %q, want text/calendar", r.Header.Get("Accept"))
+		}
+		switch r.URL.Path {
+		case "/calendar.ics":
+			w.Header().Set("Content-Type", "text/calendar")
+			fmt.Fprint(w, body)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// calendarICS is a calendar with one weekly event without a meeting link,
+// which the importer reports as skipped without touching the database
+func calendarICS(start time.Time) string {
+	return strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"SUMMARY:Standup",
+		"LOCATION:Room 1",
+		"DTSTART:" + start.UTC().Format("20060102T150405Z"),
+		"RRULE:FREQ=WEEKLY;COUNT=3",
+		"EXDATE:" + start.AddDate(0, 0, 7).UTC().Format("20060102T150405Z"),
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+}
+
+func TestImporterFetchAndImport(t *testing.T) {
+	t.Setenv("ALLOW_PRIVATE_NETWORK_TARGETS", "true") // httptest listens on loopback
+
+	start := time.Now().Add(constants.ScheduleLeadTime + time.Hour).Truncate(time.Second)
+	server := feed(t, calendarICS(start))
+	importer := NewImporter(nil, nil, nil, nil)
+
+	body, err := importer.Fetch(context.Background(), server.URL+"/calendar.ics")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	defer body.Close()
+
+	result, err := importer.Import(context.Background(), body, Options{UserID: 1})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if result.Events != 1 || result.Occurrences != 2 || result.Created != 0 {
+		t.Errorf("result = %d events, %d occurrences, %d created; want 1, 2, 0",
+			result.Events, result.Occurrences, result.Created)
+	}
+	if len(result.Skipped) != 2 {
+		t.Fatalf("skipped %d occurrences, want 2", len(result.Skipped))
+	}
+	if skip := result.Skipped[1]; !skip.Start.Equal(start.AddDate(0, 0, 14)) || skip.Reason != "no Google Meet or Teams link" {
+		t.Errorf("second skip = %+v", skip)
+	}
+}
+
+func TestImporterFetchErrors(t *testing.T) {
+	t.Setenv("ALLOW_PRIVATE_NETWORK_TARGETS", "true")
+
+	server := feed(t, calendarICS(time.Now()))
+	importer := NewImporter(nil, nil, nil, nil)
+
+	if _, err := importer.Fetch(context.Background(), server.URL+"/missing.ics"); !errors.Is(err, ErrFetchFailed) {
+		t.Errorf("Fetch() of a missing feed error = %v, want ErrFetchFailed", err)
+	}
+}
+
+func TestImporterFetchRefusesPrivateAddresses(t *testing.T) {
+	t.Setenv("ALLOW_PRIVATE_NETWORK_TARGETS", "false")
+
+	server := feed(t, calendarICS(time.Now()))
+	importer := NewImporter(nil, nil, nil, nil)
+
+	_, err := importer.Fetch(context.Background(), server.URL+"/calendar.ics")
+	if !errors.Is(err, ErrFetchFailed) || !strings.Contains(err.Error(), safehttp.ErrBlockedAddress.Error()) {
+		t.Errorf("Fetch() of a loopback feed error = %v, want blocked address", err)
+	}
+}
+
+func TestImportRejectsInvalidCalendars(t *testing.T) {
+	importer := NewImporter(nil, nil, nil, nil)
+
+	_, err := importer.Import(context.Background(), strings.NewReader("<html>not a calendar</html>"), Options{UserID: 1})
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("Import() of HTML error = %v, want ErrInvalid", err)
+	}
+
+	huge := strings.NewReader(strings.Repeat("X", constants.ICSMaxSize+1))
+	if _, err := importer.Import(context.Background(), huge, Options{UserID: 1}); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Import() of an oversized calendar error = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestValidateFeedURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{url: "https://calendar.example.com/feed.ics", want: "https://calendar.example.com/feed.ics"},
+		{url: "webcal://calendar.example.com/feed.ics", want: "https://calendar.example.com/feed.ics"},
+		{url: "http://calendar.example.com/feed.ics", want: "http://calendar.example.com/feed.ics"},
+		{url: "ftp://calendar.example.com/feed.ics", wantErr: true},
+		{url: "/feed.ics", wantErr: true},
+		{url: "://bad", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := ValidateFeedURL(tt.url)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ValidateFeedURL(%q) = %q, %v; want %q, error %v", tt.url, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportCalendarWindow(t *testing.T) {
+	importer := NewImporter(nil, nil, nil, nil)
+
+	// Starts inside the lead time, so it is not expanded and must not be judged missing
+	soon := time.Now().Add(constants.ScheduleLeadTime / 2).Truncate(time.Second)
+
+	_, seen, from, until, err := importer.importCalendar(context.Background(), strings.NewReader(calendarICS(soon)), Options{UserID: 1})
+	if err != nil {
+		t.Fatalf("importCalendar() error = %v", err)
+	}
+
+	if !soon.Before(from) {
+		t.Errorf("window starts at %v, want after %v", from, soon)
+	}
+	if want := from.Add(constants.ICSImportHorizon); !until.Equal(want) {
+		t.Errorf("window ends at %v, want %v", until, want)
+	}
+	if seen[occurrenceKey{uid: "standup@example.com", start: soon.Unix()}] {
+		t.Error("occurrence before the window was reported as seen")
+	}
+}
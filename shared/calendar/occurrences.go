@@ -0,0 +1,118 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Occurrence is a single instance of an event
+type Occurrence struct {
+	Event *Event
+	Start time.Time
+}
+
+// Expand returns the event occurrences starting within [from, to), soonest first.
+// Recurring events are expanded with their RRULE, RDATEs and EXDATEs; overrides
+// (events with a RECURRENCE-ID) replace the occurrence they modify, and cancelled
+// events or overrides produce no occurrence. At most limit occurrences are returned.
+func Expand(events []*Event, from, to time.Time, limit int) ([]Occurrence, error) {
+	// Overrides keyed by UID, then by the start of the occurrence they replace
+	overrides := map[string]map[int64]*Event{}
+	for _, event := range events {
+		if event.RecurrenceID == nil {
+			continue
+		}
+		if overrides[event.UID] == nil {
+			overrides[event.UID] = map[int64]*Event{}
+		}
+		overrides[event.UID][event.RecurrenceID.Unix()] = event
+	}
+
+	occurrences := []Occurrence{}
+	add := func(event *Event, start time.Time) {
+		if !start.Before(from) && start.Before(to) {
+			occurrences = append(occurrences, Occurrence{Event: event, Start: start})
+		}
+	}
+
+	for _, event := range events {
+		if event.AllDay || event.Cancelled() {
+			continue
+		}
+
+		// Overrides are single occurrences in their own right
+		if event.RecurrenceID != nil {
+			add(event, event.Start)
+			continue
+		}
+
+		starts, err := event.starts(from, to)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.UID, err)
+		}
+
+		for _, start := range starts {
+			if _, overridden := overrides[event.UID][start.Unix()]; overridden {
+				continue
+			}
+			add(event, start)
+		}
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].Start.Before(occurrences[j].Start)
+	})
+
+	if limit > 0 && len(occurrences) > limit {
+		occurrences = occurrences[:limit]
+	}
+
+	return occurrences, nil
+}
+
+// starts returns the start times of a master event within [from, to)
+func (e *Event) starts(from, to time.Time) ([]time.Time, error) {
+	if e.RRule == "" && len(e.RDates) == 0 {
+		return []time.Time{e.Start}, nil
+	}
+
+	set := &rrule.Set{}
+	set.DTStart(e.Start)
+
+	if e.RRule != "" {
+		option, err := rrule.StrToROptionInLocation(e.RRule, e.Start.Location())
+		if err != nil {
+			return nil, fmt.Errorf("invalid RRULE: %w", err)
+		}
+		option.Dtstart = e.Start
+
+		rule, err := rrule.NewRRule(*option)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RRULE: %w", err)
+		}
+		set.RRule(rule)
+	} else {
+		set.RDate(e.Start) // DTSTART is always the first occurrence
+	}
+
+	for _, rdate := range e.RDates {
+		set.RDate(rdate)
+	}
+	for _, exdate := range e.ExDates {
+		set.ExDate(exdate)
+	}
+
+	return set.Between(from, to, true), nil
+}
+
+// OriginalStart returns the start the occurrence has in the recurrence set, which
+// identifies it even after an override moves it
+func (o Occurrence) OriginalStart() time.Time {
+	if o.Event.RecurrenceID != nil {
+		return *o.Event.RecurrenceID
+	}
+	return o.Start
+}
@@ -0,0 +1,153 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpand(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, berlin)
+	}
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	from := at(10, 1, 0)
+	to := at(12, 31, 0)
+
+	tests := []struct {
+		name   string
+		events []*Event
+		limit  int
+		want   []time.Time
+	}{
+		{
+			name:   "single event",
+			events: []*Event{{UID: "a", Start: at(10, 20, 10)}},
+			want:   []time.Time{at(10, 20, 10)},
+		},
+		{
+			name:   "single event outside the window",
+			events: []*Event{{UID: "a", Start: at(9, 20, 10)}},
+			want:   []time.Time{},
+		},
+		{
+			name:   "weekly RRULE",
+			events: []*Event{{UID: "a", Start: at(10, 20, 10), RRule: "FREQ=WEEKLY;COUNT=3"}},
+			want:   []time.Time{at(10, 20, 10), at(10, 27, 10), at(11, 3, 10)},
+		},
+		{
+			// Keeps 10:00 local time across the DST change on 25 October
+			name:   "RRULE in a time zone",
+			events: []*Event{{UID: "a", Start: at(10, 20, 10), RRule: "FREQ=WEEKLY;UNTIL=20261028T000000Z"}},
+			want:   []time.Time{at(10, 20, 10), at(10, 27, 10)},
+		},
+		{
+			name: "EXDATE removes occurrences",
+			events: []*Event{{
+				UID:     "a",
+				Start:   at(10, 20, 10),
+				RRule:   "FREQ=WEEKLY;COUNT=4",
+				ExDates: []time.Time{at(10, 27, 10), at(11, 10, 10)},
+			}},
+			want: []time.Time{at(10, 20, 10), at(11, 3, 10)},
+		},
+		{
+			name: "RDATE adds occurrences",
+			events: []*Event{{
+				UID:    "a",
+				Start:  at(10, 20, 10),
+				RDates: []time.Time{at(10, 22, 15)},
+			}},
+			want: []time.Time{at(10, 20, 10), at(10, 22, 15)},
+		},
+		{
+			name: "RECURRENCE-ID override moves an occurrence",
+			events: []*Event{
+				{UID: "a", Start: at(10, 20, 10), RRule: "FREQ=WEEKLY;COUNT=3"},
+				{UID: "a", Start: at(10, 28, 14), RecurrenceID: ptr(at(10, 27, 10))},
+			},
+			want: []time.Time{at(10, 20, 10), at(10, 28, 14), at(11, 3, 10)},
+		},
+		{
+			name: "cancelled override removes an occurrence",
+			events: []*Event{
+				{UID: "a", Start: at(10, 20, 10), RRule: "FREQ=WEEKLY;COUNT=3"},
+				{UID: "a", Start: at(10, 27, 10), RecurrenceID: ptr(at(10, 27, 10)), Status: "CANCELLED"},
+			},
+			want: []time.Time{at(10, 20, 10), at(11, 3, 10)},
+		},
+		{
+			name: "override of another event is ignored",
+			events: []*Event{
+				{UID: "a", Start: at(10, 20, 10), RRule: "FREQ=WEEKLY;COUNT=2"},
+				{UID: "b", Start: at(10, 21, 9), RecurrenceID: ptr(at(10, 27, 10))},
+			},
+			want: []time.Time{at(10, 20, 10), at(10, 21, 9), at(10, 27, 10)},
+		},
+		{
+			name: "cancelled and all-day events are skipped",
+			events: []*Event{
+				{UID: "a", Start: at(10, 20, 10), Status: "CANCELLED"},
+				{UID: "b", Start: time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC), AllDay: true},
+			},
+			want: []time.Time{},
+		},
+		{
+			name: "sorted across events and limited",
+			events: []*Event{
+				{UID: "a", Start: at(10, 20, 10), RRule: "FREQ=DAILY"},
+				{UID: "b", Start: at(10, 20, 9)},
+			},
+			limit: 3,
+			want:  []time.Time{at(10, 20, 9), at(10, 20, 10), at(10, 21, 10)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Expand(tt.events, from, to, tt.limit)
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expand() returned %d occurrences %v, want %v", len(got), starts(got), tt.want)
+			}
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i]) {
+					t.Errorf("occurrence %d starts at %v, want %v", i, got[i].Start, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExpandInvalidRRule(t *testing.T) {
+	events := []*Event{{UID: "a", Start: time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC), RRule: "FREQ=SOMETIMES"}}
+	if _, err := Expand(events, time.Time{}, time.Now().AddDate(10, 0, 0), 0); err == nil {
+		t.Errorf("Expand() error = nil, want error")
+	}
+}
+
+func TestOriginalStart(t *testing.T) {
+	original := time.Date(2026, 10, 27, 10, 0, 0, 0, time.UTC)
+	moved := time.Date(2026, 10, 28, 14, 0, 0, 0, time.UTC)
+
+	occurrence := Occurrence{Event: &Event{UID: "a", Start: moved, RecurrenceID: &original}, Start: moved}
+	if got := occurrence.OriginalStart(); !got.Equal(original) {
+		t.Errorf("OriginalStart() of an override = %v, want %v", got, original)
+	}
+
+	occurrence = Occurrence{Event: &Event{UID: "a", Start: original}, Start: original}
+	if got := occurrence.OriginalStart(); !got.Equal(original) {
+		t.Errorf("OriginalStart() = %v, want %v", got, original)
+	}
+}
+
+func starts(occurrences []Occurrence) []time.Time {
+	times := make([]time.Time, len(occurrences))
+	for i, occurrence := range occurrences {
+		times[i] = occurrence.Start
+	}
+	return times
+}
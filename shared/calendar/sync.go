@@ -0,0 +1,149 @@
+package calendar
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/types"
+)
+
+// Run syncs calendar feeds every constants.ICSSyncInterval until ctx is cancelled
+func (i *Importer) Run(ctx context.Context) {
+	log.Info().Dur("interval", constants.ICSSyncInterval).Msg("Calendar sync started")
+
+	// Poll more often than the sync interval so new feeds are not delayed a full interval
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Calendar sync stopped")
+			return
+		case <-ticker.C:
+			i.SyncDue(ctx)
+		}
+	}
+}
+
+// SyncDue syncs every active feed not synced within constants.ICSSyncInterval
+func (i *Importer) SyncDue(ctx context.Context) {
+	feeds, err := i.feedRepo.ListDue(ctx, time.Now().Add(-constants.ICSSyncInterval))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list calendar feeds due for sync")
+		return
+	}
+
+	for _, feed := range feeds {
+		if ctx.Err() != nil {
+			return
+		}
+		// Failures are recorded on the feed
+		i.SyncFeed(ctx, feed)
+	}
+}
+
+// SyncFeed fetches a feed and imports its new occurrences. Scheduled recordings of
+// occurrences that disappeared from the feed (deleted, cancelled or moved) are
+// cancelled. The outcome is recorded on the feed.
+func (i *Importer) SyncFeed(ctx context.Context, feed *types.CalendarFeed) (*types.CalendarImportResult, error) {
+	result, err := i.syncFeed(ctx, feed)
+
+	var syncErr *string
+	if err != nil {
+		msg := err.Error()
+		syncErr = &msg
+		log.Warn().Err(err).Int64("feed_id", feed.ID).Msg("Calendar feed sync failed")
+	}
+
+	if recordErr := i.feedRepo.RecordSync(ctx, feed.ID, syncErr); recordErr != nil {
+		log.Error().Err(recordErr).Int64("feed_id", feed.ID).Msg("Failed to record calendar feed sync")
+	}
+
+	return result, err
+}
+
+func (i *Importer) syncFeed(ctx context.Context, feed *types.CalendarFeed) (*types.CalendarImportResult, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, constants.ICSFetchTimeout)
+	defer cancel()
+
+	body, err := i.Fetch(fetchCtx, feed.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	opts := Options{
+		UserID:      feed.UserID,
+		FeedID:      &feed.ID,
+		BotName:     constants.DefaultBotName,
+		MaxDuration: feed.MaxDuration,
+	}
+	if feed.BotName != nil {
+		opts.BotName = *feed.BotName
+	}
+
+	result, seen, from, until, err := i.importCalendar(ctx, body, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled, err := i.meetingRepo.ListScheduledByFeed(ctx, feed.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, meeting := range scheduled {
+		// Only occurrences the import looked at can be known to be gone
+		if meeting.Start.Before(from) || !meeting.Start.Before(until) {
+			continue
+		}
+		if seen[occurrenceKey{uid: meeting.UID, start: meeting.Start.Unix()}] {
+			continue
+		}
+		if i.cancel(ctx, meeting.ID, meeting.UserID, "Removed from calendar") {
+			result.Cancelled++
+		}
+	}
+
+	return result, nil
+}
+
+// CancelFeedMeetings cancels the still-scheduled recordings imported from a feed
+func (i *Importer) CancelFeedMeetings(ctx context.Context, feedID int64, reason string) (int, error) {
+	scheduled, err := i.meetingRepo.ListScheduledByFeed(ctx, feedID)
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, meeting := range scheduled {
+		if i.cancel(ctx, meeting.ID, meeting.UserID, reason) {
+			cancelled++
+		}
+	}
+
+	return cancelled, nil
+}
+
+// cancel fails a scheduled recording. Recordings that left the scheduled status
+// in the meantime (e.g. were just released to a bot) are left alone.
+func (i *Importer) cancel(ctx context.Context, meetingID, userID int64, reason string) bool {
+	if err := i.meetingRepo.CancelScheduled(ctx, meetingID, reason); err != nil {
+		log.Warn().Err(err).Int64("meeting_id", meetingID).Msg("Failed to cancel calendar recording")
+		return false
+	}
+
+	i.notifier.Notify(ctx, meetingID, types.StatusFailed)
+	i.publisher.PublishStatusChanged(ctx, meetingID, userID, types.StatusFailed, &reason, 0)
+
+	log.Info().
+		Int64("meeting_id", meetingID).
+		Str("reason", reason).
+		Msg("Calendar recording cancelled")
+
+	return true
+}
@@ -21,6 +21,17 @@ const (
 	// Request Limits
 	MaxRequestBodySize     = 10 * 1024 * 1024 // 10MB
 	RequestTimeout         = 30 * time.Second
+
+	// Status Streams (SSE / WebSocket)
+	StreamTokenQueryParam   = "stream_token"   // Short-lived token for clients that cannot set headers (EventSource, WebSocket)
+	StreamTokenTTL          = 5 * time.Minute  // Only checked when the stream is opened
+	StreamHeartbeatInterval = 15 * time.Second
+	StreamPollInterval      = 5 * time.Second  // Picks up status changes not published by the bot
+	StreamWriteTimeout      = 10 * time.Second // Per event; drops dead clients
+
+	// Recording Downloads
+	SignedURLTTL           = 15 * time.Minute // Lifetime of the recording links in API responses
+	SignedFilesPath        = "/files"         // Gateway route signed local storage URLs are served under
 )
 
 // =====================================================
@@ -48,18 +59,54 @@ const (
 	ChunkDurationSeconds   = 10
 	ChunkUploadTimeout     = 30 * time.Second
 	MaxChunksPerRecording  = 3600 // 10 hours max (10s chunks)
+	MaxRecordingDuration   = MaxChunksPerRecording * ChunkDurationSeconds * time.Second
+	ChunkValidateWorkers   = 4 // Concurrent ffprobe runs during finalization
+	ConcatListFileName     = "concat.ffconcat" // ffconcat list written next to the segments
+	SegmentWorkDir         = "segments" // Subdirectory of a meeting's finalization work dir
+	ChunkDownloadDir       = "chunks" // Remote chunks are downloaded here, next to SegmentWorkDir
+	ChunkDownloadWorkers   = 8 // Concurrent chunk downloads from remote storage
+	ConcatOutputFileName   = "concat.webm" // Concatenation before post-processing
 
 	// Audio Settings
 	DefaultAudioBitrate    = 128000 // 128 kbps
 	AudioCodec             = "opus"
 	AudioMimeType          = "audio/webm;codecs=opus"
 	FinalRecordingFormat   = ".webm"
+	ProbeTimeout           = 30 * time.Second // ffprobe of a finalized recording
 
 	// Bot Timeouts
 	BotJoinTimeout         = 60 * time.Second  // Wait for admission
 	BotStartTimeout        = 120 * time.Second // Browser startup
 	BotShutdownTimeout     = 30 * time.Second
 	BotHealthCheckInterval = 10 * time.Second
+	BotStopGracePeriod     = 30 * time.Second  // Wait for bot to flush last chunk after stop
+
+	// Bot Spawn Queue (api-gateway → bot-manager)
+	SpawnMaxAttempts       = 5
+	SpawnJobTimeout        = 30 * time.Second
+	SpawnRetryBackoff      = 45 * time.Second // Must exceed SpawnJobTimeout
+	SpawnMaxBackoff        = 5 * time.Minute
+	SpawnWorkerConcurrency = 4
+	SpawnStallTimeout      = 15 * time.Minute // Requested meetings without a bot or spawn attempt for this long are re-queued; must exceed SpawnMaxBackoff
+	StaleRequestTimeout    = 30 * time.Minute // Admin cleanup fails requested meetings not updated for this long; must exceed SpawnStallTimeout
+
+	// Finalization Queue (bot-manager status listener → finalization workers)
+	// Job timeout is FinalizeBaseTimeout plus FinalizeChunkTimeout per chunk, capped at FinalizeMaxTimeout
+	FinalizeMaxAttempts    = 3
+	FinalizeBaseTimeout    = 2 * time.Minute
+	FinalizeChunkTimeout   = 2 * time.Second
+	FinalizeMaxTimeout     = 2 * time.Hour
+	FinalizeRetryBackoff   = 1 * time.Minute // In-flight jobs are kept claimed by a heartbeat
+	FinalizeMaxBackoff     = 10 * time.Minute
+	FinalizeConcurrency    = 2
+
+	// Max Duration (per recording, or a per-user default in users.data)
+	UserMaxDurationKey     = "default_max_duration_seconds"
+	MaxDurationStopReason  = "max duration reached"
+	UserStopReason         = "stopped by user"
+
+	// Output Formats (per recording, or a per-user default in users.data)
+	UserOutputFormatsKey   = "default_output_formats"
 )
 
 // =====================================================
@@ -80,6 +127,23 @@ const (
 	// Bot Names
 	DefaultBotName         = "Newar Recorder"
 	BotNameMaxLength       = 100
+
+	// Scheduled Recordings
+	SchedulerInterval      = 15 * time.Second
+	ScheduleLeadTime       = 30 * time.Second // Spawn early so the bot is in the meeting on time
+	ScheduleMissedWindow   = 15 * time.Minute // Later than this the meeting is failed instead of joined
+	ScheduleMaxAhead       = 90 * 24 * time.Hour
+	SchedulerBatchSize     = 50
+	MinRecordingDuration   = 60 // seconds (lower bound for max_duration_seconds)
+
+	// Calendar (ICS) Import
+	ICSMaxSize             = 2 * 1024 * 1024 // bytes
+	ICSFetchTimeout        = 30 * time.Second
+	ICSSyncInterval        = 15 * time.Minute
+	ICSImportHorizon       = 30 * 24 * time.Hour // Occurrences further out are picked up by later syncs
+	ICSMaxOccurrences      = 500                 // Per import
+	ICSUploadField         = "file"
+	MaxCalendarFeeds       = 10 // Per user
 )
 
 // =====================================================
@@ -112,10 +176,24 @@ const (
 	BotCommandChannel      = "bot:command:"    // bot:command:{container_id}
 	MeetingEventsChannel   = "meeting:events"  // Global events
 
+	// Job Queues (Redis Streams)
+	SpawnQueueStream       = "queue:spawn"     // Dead letters: queue:spawn:dead
+	SpawnQueueGroup        = "bot-manager"
+	FinalizeQueueStream    = "queue:finalize"  // Dead letters: queue:finalize:dead
+	FinalizeQueueGroup     = "bot-manager"
+
+	// Bot Commands
+	BotCommandStop         = "stop"
+	BotCommandStatus       = "status"
+
 	// Pub/Sub Timeouts
 	RedisPublishTimeout    = 5 * time.Second
 	RedisSubscribeTimeout  = 0 // No timeout for subscriptions
 
+	// Bot Status Dispatcher (single PSUBSCRIBE on bot:status:*)
+	StatusDispatchBuffer   = 64              // Queued updates per container
+	StatusDispatchTimeout  = 1 * time.Second // Wait for a full queue before dropping
+
 	// Key Expiration
 	BotStatusTTL           = 24 * time.Hour
 	RateLimitTTL           = 1 * time.Minute
@@ -140,6 +218,37 @@ const (
 	ContainerStartTimeout  = 60 * time.Second
 	ContainerStopTimeout   = 30 * time.Second
 	ContainerCleanupDelay  = 5 * time.Minute
+	ReconcileTimeout       = 10 * time.Minute // Startup reconciliation (may finalize recordings)
+
+	// Reaper
+	ReaperInterval         = 1 * time.Minute
+	ReaperExitGracePeriod  = 1 * time.Minute // Let the status listener settle exited bots first
+	ReaperLostGracePeriod  = 1 * time.Minute // Meetings updated this recently are not treated as lost
+)
+
+// =====================================================
+// WEBHOOK CONFIGURATION
+// =====================================================
+
+const (
+	// Headers
+	WebhookSignatureHeader = "X-Newar-Signature" // sha256=<hex HMAC of "{timestamp}.{body}">
+	WebhookTimestampHeader = "X-Newar-Timestamp" // Unix seconds
+	WebhookEventHeader     = "X-Newar-Event"
+	WebhookDeliveryHeader  = "X-Newar-Delivery"
+	WebhookSecretPrefix    = "whsec_"
+
+	// Delivery
+	WebhookRequestTimeout  = 10 * time.Second
+	WebhookPollInterval    = 5 * time.Second
+	WebhookBatchSize       = 20
+	WebhookMaxAttempts     = 8
+	WebhookInitialBackoff  = 30 * time.Second // Doubled per attempt
+	WebhookMaxBackoff      = 1 * time.Hour
+	WebhookDeliveryLease   = 1 * time.Minute  // Claimed deliveries are retried after this if the worker dies
+
+	// Limits
+	MaxWebhooksPerUser     = 10
 )
 
 // =====================================================
@@ -191,6 +300,11 @@ const (
 	ErrMaxBotsReached      = "Maximum concurrent bots limit reached"
 	ErrDuplicateRecording  = "Recording already exists for this meeting"
 	ErrRecordingNotFound   = "Recording not found"
+	ErrWebhookNotFound     = "Webhook not found"
+	ErrMaxWebhooksReached  = "Maximum number of webhooks reached"
+	ErrCalendarNotFound    = "Calendar feed not found"
+	ErrMaxCalendarFeeds    = "Maximum number of calendar feeds reached"
+	ErrCannotFinalize      = "Recording cannot be finalized in its current status"
 )
 
 // =====================================================
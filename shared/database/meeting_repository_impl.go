@@ -70,16 +70,16 @@ func (r *MeetingRepositoryImpl) Save(ctx context.Context, meeting *entities.Meet
 			return fmt.Errorf("failed to insert meeting: %w", err)
 		}
 	} else {
-		// Meeting exists, update
+		// Meeting exists, update (a status change must respect the state machine)
+		sources := append(types.TransitionSources(dto.Status), dto.Status)
+		statusClause, statusArgs := statusInClause(sources, 8)
 		updateQuery := `
 			UPDATE meetings
 			SET status = $1, bot_container_id = $2, recording_path = $3,
 			    recording_duration = $4, error_message = $5, updated_at = $6
-			WHERE id = $7
-		`
-		_, err = r.db.Exec(
-			ctx,
-			updateQuery,
+			WHERE id = $7 AND ` + statusClause
+
+		args := []interface{}{
 			dto.Status,
 			dto.BotContainerID,
 			dto.RecordingPath,
@@ -87,11 +87,16 @@ func (r *MeetingRepositoryImpl) Save(ctx context.Context, meeting *entities.Meet
 			dto.ErrorMessage,
 			dto.UpdatedAt,
 			dto.ID,
-		)
+		}
+		result, err := r.db.Exec(ctx, updateQuery, append(args, statusArgs...)...)
 
 		if err != nil {
 			return fmt.Errorf("failed to update meeting: %w", err)
 		}
+
+		if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+			return transitionError(ctx, r.db, dto.ID, dto.Status)
+		}
 	}
 
 	return nil
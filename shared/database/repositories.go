@@ -5,9 +5,14 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+
 	"github.com/newar/insights/shared/types"
 )
 
@@ -75,7 +80,7 @@ func (r *UserRepository) CreateOld(ctx context.Context, req types.CreateUserRequ
 
 // GetByID retrieves a user by ID
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*types.User, error) {
-	query := `SELECT id, email, name, max_concurrent_bots, created_at, updated_at FROM users WHERE id = $1`
+	query := `SELECT id, email, name, max_concurrent_bots, data, created_at, updated_at FROM users WHERE id = $1`
 
 	var user types.User
 	err := r.db.QueryRow(ctx, query, id).Scan(
@@ -83,6 +88,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*types.User, er
 		&user.Email,
 		&user.Name,
 		&user.MaxConcurrentBots,
+		&user.Data,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -99,7 +105,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*types.User, er
 
 // GetByEmail retrieves a user by email
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*types.User, error) {
-	query := `SELECT id, email, name, max_concurrent_bots, created_at, updated_at FROM users WHERE email = $1`
+	query := `SELECT id, email, name, max_concurrent_bots, data, created_at, updated_at FROM users WHERE email = $1`
 
 	var user types.User
 	err := r.db.QueryRow(ctx, query, email).Scan(
@@ -107,6 +113,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*types.U
 		&user.Email,
 		&user.Name,
 		&user.MaxConcurrentBots,
+		&user.Data,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -273,37 +280,53 @@ func NewMeetingRepository(db Database) *MeetingRepository {
 }
 
 // Create creates a new meeting
+// Meetings with a ScheduledAt start as "scheduled", all others as "requested".
 func (r *MeetingRepository) Create(ctx context.Context, userID int64, req types.CreateRecordingRequest, meetingURL string) (*types.Meeting, error) {
 	now := time.Now()
 	query := `
-		INSERT INTO meetings (user_id, platform, meeting_id, meeting_url, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO meetings (user_id, platform, meeting_id, meeting_url, bot_name, status, scheduled_at, max_duration, output_formats, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id
 	`
 
+	status := types.MeetingStatusRequested
+	if req.ScheduledAt != nil {
+		status = types.MeetingStatusScheduled
+	}
+
+	var botName *string
+	if req.BotName != "" {
+		botName = &req.BotName
+	}
+
 	var id int64
-	err := r.db.QueryRow(ctx, query, userID, req.Platform, req.MeetingID, meetingURL, types.StatusRequested, now, now).Scan(&id)
+	err := r.db.QueryRow(ctx, query, userID, req.Platform, req.MeetingID, meetingURL, botName, status, req.ScheduledAt, req.MaxDuration, pq.Array(req.OutputFormats), now, now).Scan(&id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create meeting: %w", err)
 	}
 
 	return &types.Meeting{
-		ID:         int64(id),
-		UserID:     userID,
-		Platform:   req.Platform,
-		MeetingID:  req.MeetingID,
-		MeetingURL: meetingURL,
-		Status:     types.StatusRequested,
-		CreatedAt:  now,
-		UpdatedAt:  now,
+		ID:            int64(id),
+		UserID:        userID,
+		Platform:      req.Platform,
+		MeetingID:     req.MeetingID,
+		MeetingURL:    meetingURL,
+		BotName:       botName,
+		Status:        status,
+		ScheduledAt:   req.ScheduledAt,
+		MaxDuration:   req.MaxDuration,
+		OutputFormats: req.OutputFormats,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}, nil
 }
 
 // GetByID retrieves a meeting by ID
 func (r *MeetingRepository) GetByID(ctx context.Context, id int64) (*types.Meeting, error) {
 	query := `
-		SELECT id, user_id, platform, meeting_id, meeting_url, bot_name, bot_container_id,
-		       recording_session_id, status, recording_path, recording_duration, error_message,
+		SELECT id, user_id, platform, meeting_id, meeting_url, bot_name, bot_container_id, bot_host,
+		       recording_session_id, status, recording_path, recording_duration, error_message, stop_reason,
+		       stop_requested_at, spawn_attempts, spawn_last_error, scheduled_at, max_duration, output_formats,
 		       started_at, completed_at, created_at, updated_at
 		FROM meetings WHERE id = $1
 	`
@@ -317,11 +340,19 @@ func (r *MeetingRepository) GetByID(ctx context.Context, id int64) (*types.Meeti
 		&meeting.MeetingURL,
 		&meeting.BotName,
 		&meeting.BotContainerID,
+		&meeting.BotHost,
 		&meeting.RecordingSessionID,
 		&meeting.Status,
 		&meeting.RecordingPath,
 		&meeting.RecordingDuration,
 		&meeting.ErrorMessage,
+		&meeting.StopReason,
+		&meeting.StopRequestedAt,
+		&meeting.SpawnAttempts,
+		&meeting.SpawnLastError,
+		&meeting.ScheduledAt,
+		&meeting.MaxDuration,
+		pq.Array(&meeting.OutputFormats),
 		&meeting.StartedAt,
 		&meeting.CompletedAt,
 		&meeting.CreatedAt,
@@ -348,6 +379,11 @@ func (r *MeetingRepository) Get(ctx context.Context, filter types.MeetingFilter)
 	args := []interface{}{}
 	paramIndex := 1
 
+	if filter.ID != nil {
+		query += fmt.Sprintf(" AND id = $%d", paramIndex)
+		args = append(args, *filter.ID)
+		paramIndex++
+	}
 	if filter.UserID != nil {
 		query += fmt.Sprintf(" AND user_id = $%d", paramIndex)
 		args = append(args, *filter.UserID)
@@ -409,27 +445,50 @@ func (r *MeetingRepository) Get(ctx context.Context, filter types.MeetingFilter)
 	return &meeting, nil
 }
 
-// GetByPlatformAndMeetingID retrieves a meeting by platform and meeting ID
+// GetByPlatformAndMeetingID retrieves a meeting by platform and meeting ID.
+// A meeting may be recorded more than once (e.g. every occurrence of a recurring
+// calendar event shares one meeting ID). The one in progress is returned, else the
+// latest that started (or was due to), else the next scheduled occurrence.
 func (r *MeetingRepository) GetByPlatformAndMeetingID(ctx context.Context, userID int64, platform types.Platform, meetingID string) (*types.Meeting, error) {
 	query := `
-		SELECT id, user_id, platform, meeting_id, bot_container_id, status, meeting_url,
-		       recording_path, started_at, completed_at, error_message, created_at, updated_at
+		SELECT id, user_id, platform, meeting_id, bot_container_id, recording_session_id, status, meeting_url,
+		       recording_path, started_at, completed_at, error_message, stop_reason, stop_requested_at, spawn_attempts,
+		       spawn_last_error, scheduled_at, max_duration, output_formats, created_at, updated_at
 		FROM meetings WHERE user_id = $1 AND platform = $2 AND meeting_id = $3
+		ORDER BY
+			CASE
+				WHEN status NOT IN ($4, $5, $6) THEN 0
+				WHEN COALESCE(started_at, scheduled_at, created_at) <= $7 THEN 1
+				ELSE 2
+			END,
+			CASE WHEN COALESCE(started_at, scheduled_at, created_at) <= $7 THEN COALESCE(started_at, scheduled_at, created_at) END DESC NULLS LAST,
+			COALESCE(started_at, scheduled_at, created_at) ASC,
+			id DESC
+		LIMIT 1
 	`
 
 	var meeting types.Meeting
-	err := r.db.QueryRow(ctx, query, userID, platform, meetingID).Scan(
+	err := r.db.QueryRow(ctx, query, userID, platform, meetingID,
+		types.StatusScheduled, types.StatusCompleted, types.StatusFailed, time.Now()).Scan(
 		&meeting.ID,
 		&meeting.UserID,
 		&meeting.Platform,
 		&meeting.MeetingID,
 		&meeting.BotContainerID,
+		&meeting.RecordingSessionID,
 		&meeting.Status,
 		&meeting.MeetingURL,
 		&meeting.RecordingPath,
 		&meeting.StartedAt,
 		&meeting.CompletedAt,
 		&meeting.ErrorMessage,
+		&meeting.StopReason,
+		&meeting.StopRequestedAt,
+		&meeting.SpawnAttempts,
+		&meeting.SpawnLastError,
+		&meeting.ScheduledAt,
+		&meeting.MaxDuration,
+		pq.Array(&meeting.OutputFormats),
 		&meeting.CreatedAt,
 		&meeting.UpdatedAt,
 	)
@@ -444,42 +503,212 @@ func (r *MeetingRepository) GetByPlatformAndMeetingID(ctx context.Context, userI
 	return &meeting, nil
 }
 
-// UpdateStatus updates a meeting status
+// InvalidTransitionError is returned when a status write is rejected by the meeting state machine
+type InvalidTransitionError struct {
+	MeetingID int64
+	From      types.MeetingStatus
+	To        types.MeetingStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("invalid status transition for meeting %d: %s -> %s", e.MeetingID, e.From, e.To)
+}
+
+// UpdateStatus updates a meeting status.
+// The write is a compare-and-set against the state machine: it only applies if the
+// current status may move to the new one, otherwise *InvalidTransitionError is returned.
 func (r *MeetingRepository) UpdateStatus(ctx context.Context, id int64, status types.MeetingStatus, recordingPath *string, errorMsg *string, recordingDuration *int) error {
-	query := `
-		UPDATE meetings
-		SET status = $1, recording_path = COALESCE($2, recording_path),
-		    error_message = $3, recording_duration = COALESCE($4, recording_duration), updated_at = $5
-		WHERE id = $6
-	`
+	now := time.Now()
+
+	setClause := `status = $1, recording_path = COALESCE($2, recording_path),
+		    error_message = $3, recording_duration = COALESCE($4, recording_duration), updated_at = $5`
+	args := []interface{}{status, recordingPath, errorMsg, recordingDuration, now}
 
 	// Set started_at when status changes to active
 	if status == types.StatusActive {
-		now := time.Now()
-		query = `
-			UPDATE meetings
-			SET status = $1, started_at = $2, updated_at = $3
-			WHERE id = $4
-		`
-		_, err := r.db.Exec(ctx, query, status, now, now, id)
-		return err
+		setClause = `status = $1, started_at = COALESCE(started_at, $2), updated_at = $3`
+		args = []interface{}{status, now, now}
 	}
 
 	// Set completed_at when status changes to completed or failed
 	if status == types.StatusCompleted || status == types.StatusFailed {
-		now := time.Now()
-		query = `
-			UPDATE meetings
-			SET status = $1, error_message = $2, recording_path = COALESCE($3, recording_path),
-			    recording_duration = COALESCE($4, recording_duration), completed_at = $5, updated_at = $6
-			WHERE id = $7
-		`
-		_, err := r.db.Exec(ctx, query, status, errorMsg, recordingPath, recordingDuration, now, now, id)
-		return err
+		setClause = `status = $1, error_message = $2, recording_path = COALESCE($3, recording_path),
+		    recording_duration = COALESCE($4, recording_duration), completed_at = $5, updated_at = $6`
+		args = []interface{}{status, errorMsg, recordingPath, recordingDuration, now, now}
+	}
+
+	args = append(args, id)
+	statusClause, statusArgs := statusInClause(types.TransitionSources(status), len(args)+1)
+	args = append(args, statusArgs...)
+
+	query := fmt.Sprintf(`
+		UPDATE meetings
+		SET %s
+		WHERE id = $%d AND %s
+	`, setClause, len(args)-len(statusArgs), statusClause)
+
+	result, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update meeting status: %w", err)
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows > 0 {
+		return nil
+	}
+
+	return transitionError(ctx, r.db, id, status)
+}
+
+// ReopenForFinalization moves a failed recording back to finalizing so its chunks
+// can be finalized again. Deliberately outside the status state machine (failed is
+// terminal); only meetings that had a bot session are reopened.
+func (r *MeetingRepository) ReopenForFinalization(ctx context.Context, id int64) error {
+	query := `
+		UPDATE meetings
+		SET status = $1, error_message = NULL, completed_at = NULL, updated_at = $2
+		WHERE id = $3 AND status = $4 AND recording_session_id IS NOT NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, types.StatusFinalizing, time.Now(), id, types.StatusFailed)
+	if err != nil {
+		return fmt.Errorf("failed to reopen meeting: %w", err)
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows > 0 {
+		return nil
+	}
+
+	return transitionError(ctx, r.db, id, types.MeetingStatusFinalizing)
+}
+
+// FailStale marks meetings that have sat in a status without any update for longer
+// than olderThan as failed (every spawn attempt and status write touches updated_at).
+// Returns the meetings updated (ID and UserID only)
+func (r *MeetingRepository) FailStale(ctx context.Context, status types.MeetingStatus, olderThan time.Duration, errorMsg string) ([]*types.Meeting, error) {
+	if !types.CanTransition(status, types.MeetingStatusFailed) {
+		return nil, &InvalidTransitionError{From: status, To: types.MeetingStatusFailed}
+	}
+
+	query := `
+		UPDATE meetings
+		SET status = $1, error_message = $2, completed_at = $3, updated_at = $3
+		WHERE status = $4 AND updated_at < $5
+		RETURNING id, user_id
+	`
+
+	now := time.Now()
+	rows, err := r.db.Query(ctx, query, types.StatusFailed, errorMsg, now, status, now.Add(-olderThan))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fail stale meetings: %w", err)
+	}
+	defer rows.Close()
+
+	meetings := []*types.Meeting{}
+	for rows.Next() {
+		meeting := &types.Meeting{Status: types.MeetingStatusFailed}
+		if err := rows.Scan(&meeting.ID, &meeting.UserID); err != nil {
+			return nil, fmt.Errorf("failed to scan meeting: %w", err)
+		}
+		meetings = append(meetings, meeting)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return meetings, nil
+}
+
+// transitionError explains why a compare-and-set status write matched no rows
+func transitionError(ctx context.Context, db Database, id int64, status types.MeetingStatus) error {
+	var current types.MeetingStatus
+	err := db.QueryRow(ctx, "SELECT status FROM meetings WHERE id = $1", id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("meeting not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get meeting status: %w", err)
+	}
+
+	log.Warn().
+		Int64("meeting_id", id).
+		Str("from", string(current)).
+		Str("to", string(status)).
+		Msg("Rejected invalid meeting status transition")
+
+	return &InvalidTransitionError{MeetingID: id, From: current, To: status}
+}
+
+// statusInClause builds a "status IN (...)" condition with placeholders starting at firstParam
+func statusInClause(statuses []types.MeetingStatus, firstParam int) (string, []interface{}) {
+	placeholders := make([]string, len(statuses))
+	args := make([]interface{}, len(statuses))
+	for i, status := range statuses {
+		placeholders[i] = fmt.Sprintf("$%d", firstParam+i)
+		args[i] = status
+	}
+	return "status IN (" + strings.Join(placeholders, ", ") + ")", args
+}
+
+// RecordSpawnAttempt increments the spawn attempt counter and stores the last spawn error
+// Returns the updated attempt count
+func (r *MeetingRepository) RecordSpawnAttempt(ctx context.Context, id int64, lastError *string) (int, error) {
+	query := `
+		UPDATE meetings
+		SET spawn_attempts = spawn_attempts + 1, spawn_last_error = COALESCE($1, spawn_last_error), updated_at = $2
+		WHERE id = $3
+		RETURNING spawn_attempts
+	`
+
+	var attempts int
+	err := r.db.QueryRow(ctx, query, lastError, time.Now(), id).Scan(&attempts)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("meeting not found")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to record spawn attempt: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// AttachBot stores a spawned bot's container and session IDs and the Docker host it
+// runs on (so each bot-manager only reconciles and reaps its own bots). It is a compare-and-set:
+// the IDs are only stored while the meeting is still "requested" without a bot, so of
+// two concurrent spawns for one meeting only the first is attached. Returns false
+// if the meeting already has a bot or stopped waiting for one.
+func (r *MeetingRepository) AttachBot(ctx context.Context, id int64, containerID, sessionID, botHost string) (bool, error) {
+	query := `
+		UPDATE meetings
+		SET bot_container_id = $1, recording_session_id = $2, bot_host = $3, updated_at = $4
+		WHERE id = $5 AND (bot_container_id IS NULL OR bot_container_id = '') AND status = $6
+	`
+
+	result, err := r.db.Exec(ctx, query, containerID, sessionID, botHost, time.Now(), id, types.StatusRequested)
+	if err != nil {
+		return false, fmt.Errorf("failed to attach bot: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to attach bot: %w", err)
 	}
+	return rows > 0, nil
+}
+
+// RequestStop records that the bot was told to stop and why. The first request
+// wins, so the grace period the reaper enforces runs from the first stop command.
+func (r *MeetingRepository) RequestStop(ctx context.Context, id int64, reason string) error {
+	query := `
+		UPDATE meetings
+		SET stop_reason = COALESCE(stop_reason, $1), stop_requested_at = COALESCE(stop_requested_at, $2), updated_at = $2
+		WHERE id = $3
+	`
 
-	_, err := r.db.Exec(ctx, query, status, recordingPath, errorMsg, recordingDuration, time.Now(), id)
-	return err
+	if _, err := r.db.Exec(ctx, query, reason, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to request stop: %w", err)
+	}
+	return nil
 }
 
 // List retrieves paginated meetings for a user
@@ -494,7 +723,8 @@ func (r *MeetingRepository) List(ctx context.Context, userID int64, limit, offse
 	// Get paginated meetings
 	query := `
 		SELECT id, user_id, platform, meeting_id, bot_container_id, status, meeting_url,
-		       recording_path, started_at, completed_at, error_message, created_at, updated_at
+		       recording_path, scheduled_at, max_duration, started_at, completed_at, error_message,
+		       stop_reason, output_formats, created_at, updated_at
 		FROM meetings
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -519,9 +749,13 @@ func (r *MeetingRepository) List(ctx context.Context, userID int64, limit, offse
 			&meeting.Status,
 			&meeting.MeetingURL,
 			&meeting.RecordingPath,
+			&meeting.ScheduledAt,
+			&meeting.MaxDuration,
 			&meeting.StartedAt,
 			&meeting.CompletedAt,
 			&meeting.ErrorMessage,
+			&meeting.StopReason,
+			pq.Array(&meeting.OutputFormats),
 			&meeting.CreatedAt,
 			&meeting.UpdatedAt,
 		)
@@ -534,121 +768,193 @@ func (r *MeetingRepository) List(ctx context.Context, userID int64, limit, offse
 	return meetings, total, nil
 }
 
-// CountActiveByUser counts active recordings for a user
-func (r *MeetingRepository) CountActiveByUser(ctx context.Context, userID int64) (int, error) {
-	var count int
-	err := r.db.QueryRow(ctx, `
-		SELECT COUNT(*) FROM meetings
-		WHERE user_id = $1 AND status IN ($2, $3, $4)
-	`, userID, types.StatusRequested, types.StatusJoining, types.StatusActive).Scan(&count)
-
+// ListScheduled retrieves a user's scheduled recordings, soonest first
+func (r *MeetingRepository) ListScheduled(ctx context.Context, userID int64, limit, offset int) ([]types.Meeting, int64, error) {
+	var total int64
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM meetings WHERE user_id = $1 AND status = $2", userID, types.StatusScheduled).Scan(&total)
 	if err != nil {
-		return 0, fmt.Errorf("failed to count active meetings: %w", err)
+		return nil, 0, fmt.Errorf("failed to count scheduled meetings: %w", err)
 	}
 
-	return count, nil
-}
-
-// Update updates a meeting with flexible filter and update params
-func (r *MeetingRepository) Update(ctx context.Context, filter types.MeetingFilter, update types.MeetingUpdate) error {
-	query := "UPDATE meetings SET updated_at = $1"
-	args := []interface{}{time.Now()}
-	paramIndex := 2
+	query := `
+		SELECT id, user_id, platform, meeting_id, meeting_url, bot_name, status,
+		       scheduled_at, max_duration, created_at, updated_at
+		FROM meetings
+		WHERE user_id = $1 AND status = $2
+		ORDER BY scheduled_at ASC, id ASC
+		LIMIT $3 OFFSET $4
+	`
 
-	// Build SET clause
-	if update.Status != nil {
-		query += fmt.Sprintf(", status = $%d", paramIndex)
-		args = append(args, *update.Status)
-		paramIndex++
-	}
-	if update.BotContainerID != nil {
-		query += fmt.Sprintf(", bot_container_id = $%d", paramIndex)
-		args = append(args, *update.BotContainerID)
-		paramIndex++
-	}
-	if update.RecordingPath != nil {
-		query += fmt.Sprintf(", recording_path = $%d", paramIndex)
-		args = append(args, *update.RecordingPath)
-		paramIndex++
-	}
-	if update.RecordingDuration != nil {
-		query += fmt.Sprintf(", recording_duration = $%d", paramIndex)
-		args = append(args, *update.RecordingDuration)
-		paramIndex++
+	rows, err := r.db.Query(ctx, query, userID, types.StatusScheduled, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list scheduled meetings: %w", err)
 	}
-	if update.ErrorMessage != nil {
-		query += fmt.Sprintf(", error_message = $%d", paramIndex)
-		args = append(args, *update.ErrorMessage)
-		paramIndex++
+	defer rows.Close()
+
+	meetings := []types.Meeting{}
+	for rows.Next() {
+		var meeting types.Meeting
+		err := rows.Scan(
+			&meeting.ID,
+			&meeting.UserID,
+			&meeting.Platform,
+			&meeting.MeetingID,
+			&meeting.MeetingURL,
+			&meeting.BotName,
+			&meeting.Status,
+			&meeting.ScheduledAt,
+			&meeting.MaxDuration,
+			&meeting.CreatedAt,
+			&meeting.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan meeting: %w", err)
+		}
+		meetings = append(meetings, meeting)
 	}
-	if update.StartedAt != nil {
-		query += fmt.Sprintf(", started_at = $%d", paramIndex)
-		args = append(args, *update.StartedAt)
-		paramIndex++
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating rows: %w", err)
 	}
-	if update.CompletedAt != nil {
-		query += fmt.Sprintf(", completed_at = $%d", paramIndex)
-		args = append(args, *update.CompletedAt)
-		paramIndex++
+
+	return meetings, total, nil
+}
+
+// Reschedule moves a scheduled recording to a new start time.
+// Fails with *InvalidTransitionError once the meeting has left the "scheduled" status.
+func (r *MeetingRepository) Reschedule(ctx context.Context, id int64, scheduledAt time.Time, maxDuration *int) error {
+	query := `
+		UPDATE meetings
+		SET scheduled_at = $1, max_duration = COALESCE($2, max_duration), updated_at = $3
+		WHERE id = $4 AND status = $5
+	`
+
+	result, err := r.db.Exec(ctx, query, scheduledAt, maxDuration, time.Now(), id, types.StatusScheduled)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule meeting: %w", err)
 	}
 
-	// Build WHERE clause
-	query += " WHERE 1=1"
-	if filter.UserID != nil {
-		query += fmt.Sprintf(" AND user_id = $%d", paramIndex)
-		args = append(args, *filter.UserID)
-		paramIndex++
+	if rows, err := result.RowsAffected(); err == nil && rows > 0 {
+		return nil
 	}
-	if filter.Platform != nil {
-		query += fmt.Sprintf(" AND platform = $%d", paramIndex)
-		args = append(args, *filter.Platform)
-		paramIndex++
+
+	return transitionError(ctx, r.db, id, types.MeetingStatusScheduled)
+}
+
+// CancelScheduled fails a meeting only while it is still scheduled, so a recording
+// released to a bot concurrently is not cancelled
+func (r *MeetingRepository) CancelScheduled(ctx context.Context, id int64, errorMsg string) error {
+	query := `
+		UPDATE meetings
+		SET status = $1, error_message = $2, completed_at = $3, updated_at = $3
+		WHERE id = $4 AND status = $5
+	`
+
+	result, err := r.db.Exec(ctx, query, types.StatusFailed, errorMsg, time.Now(), id, types.StatusScheduled)
+	if err != nil {
+		return fmt.Errorf("failed to cancel meeting: %w", err)
 	}
-	if filter.MeetingID != nil {
-		query += fmt.Sprintf(" AND meeting_id = $%d", paramIndex)
-		args = append(args, *filter.MeetingID)
-		paramIndex++
+
+	if rows, err := result.RowsAffected(); err == nil && rows > 0 {
+		return nil
 	}
-	if filter.Status != nil {
-		query += fmt.Sprintf(" AND status = $%d", paramIndex)
-		args = append(args, *filter.Status)
-		paramIndex++
+
+	return transitionError(ctx, r.db, id, types.MeetingStatusFailed)
+}
+
+// releaseDueLockKey is the advisory lock ReleaseDue holds while releasing meetings
+const releaseDueLockKey = 7_130_001
+
+// ReleaseDue moves scheduled meetings starting before the given time to "requested"
+// and returns them. Each meeting is released once, even with several schedulers running.
+// A meeting is only released while its owner has fewer active bots than their
+// max_concurrent_bots; the rest stay scheduled until a bot finishes.
+func (r *MeetingRepository) ReleaseDue(ctx context.Context, before time.Time, limit int) ([]*types.Meeting, error) {
+	query := `
+		WITH due AS (
+			SELECT m.id, m.user_id, m.scheduled_at
+			FROM meetings m
+			JOIN users u ON u.id = m.user_id
+			WHERE m.status = $3 AND m.scheduled_at <= $4
+			  AND (SELECT COUNT(*) FROM meetings a WHERE a.user_id = m.user_id AND a.status IN ($6, $7, $8, $9)) < u.max_concurrent_bots
+			ORDER BY m.scheduled_at ASC
+			LIMIT $5
+			FOR UPDATE OF m SKIP LOCKED
+		), ranked AS (
+			SELECT due.id,
+			       ROW_NUMBER() OVER (PARTITION BY due.user_id ORDER BY due.scheduled_at, due.id) AS position,
+			       (SELECT COUNT(*) FROM meetings a WHERE a.user_id = due.user_id AND a.status IN ($6, $7, $8, $9)) AS active,
+			       u.max_concurrent_bots
+			FROM due
+			JOIN users u ON u.id = due.user_id
+		)
+		UPDATE meetings
+		SET status = $1, updated_at = $2
+		WHERE id IN (SELECT id FROM ranked WHERE active + position <= max_concurrent_bots)
+		RETURNING id, user_id, platform, meeting_id, meeting_url, bot_name, status,
+		          scheduled_at, max_duration, created_at, updated_at
+	`
+
+	// Releases are serialized so concurrent schedulers can't both fill a user's last slot
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin release: %w", err)
 	}
-	if filter.BotContainerID != nil {
-		query += fmt.Sprintf(" AND bot_container_id = $%d", paramIndex)
-		args = append(args, *filter.BotContainerID)
-		paramIndex++
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, releaseDueLockKey); err != nil {
+		return nil, fmt.Errorf("failed to lock scheduled meetings: %w", err)
 	}
-	if filter.RecordingSessionID != nil {
-		query += fmt.Sprintf(" AND recording_session_id = $%d", paramIndex)
-		args = append(args, *filter.RecordingSessionID)
-		paramIndex++
+
+	rows, err := tx.QueryContext(ctx, query, types.StatusRequested, time.Now(), types.StatusScheduled, before, limit,
+		types.StatusRequested, types.StatusJoining, types.StatusActive, types.StatusRecording)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release scheduled meetings: %w", err)
 	}
+	defer rows.Close()
 
-	_, err := r.db.Exec(ctx, query, args...)
+	meetings, err := scanQueuedMeetings(rows)
 	if err != nil {
-		return fmt.Errorf("failed to update meeting: %w", err)
+		return nil, err
 	}
+	rows.Close()
 
-	return nil
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit release: %w", err)
+	}
+
+	return meetings, nil
 }
 
-// GetActiveRecordings retrieves all recordings in active states
-func (r *MeetingRepository) GetActiveRecordings(ctx context.Context) ([]*types.Meeting, error) {
+// ReclaimStalledRequests returns "requested" meetings that still have no bot and
+// haven't been updated since the given time, i.e. whose spawn job was lost (every
+// spawn attempt updates the meeting). They are touched so each is reclaimed once.
+func (r *MeetingRepository) ReclaimStalledRequests(ctx context.Context, before time.Time, limit int) ([]*types.Meeting, error) {
 	query := `
-		SELECT id, user_id, platform, meeting_id, bot_container_id, recording_session_id, status, meeting_url,
-		       recording_path, started_at, completed_at, error_message, created_at, updated_at
-		FROM meetings
-		WHERE status IN ($1, $2, $3, $4)
-		ORDER BY created_at ASC
+		UPDATE meetings
+		SET updated_at = $1
+		WHERE id IN (
+			SELECT id FROM meetings
+			WHERE status = $2 AND (bot_container_id IS NULL OR bot_container_id = '') AND updated_at < $3
+			ORDER BY updated_at ASC
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, platform, meeting_id, meeting_url, bot_name, status,
+		          scheduled_at, max_duration, created_at, updated_at
 	`
 
-	rows, err := r.db.Query(ctx, query, types.StatusRequested, types.StatusJoining, types.StatusActive, types.StatusRecording)
+	rows, err := r.db.Query(ctx, query, time.Now(), types.StatusRequested, before, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get active recordings: %w", err)
+		return nil, fmt.Errorf("failed to reclaim stalled meetings: %w", err)
 	}
 	defer rows.Close()
 
+	return scanQueuedMeetings(rows)
+}
+
+// scanQueuedMeetings scans the meetings returned by ReleaseDue and ReclaimStalledRequests
+func scanQueuedMeetings(rows *sql.Rows) ([]*types.Meeting, error) {
 	meetings := []*types.Meeting{}
 	for rows.Next() {
 		var meeting types.Meeting
@@ -657,8 +963,291 @@ func (r *MeetingRepository) GetActiveRecordings(ctx context.Context) ([]*types.M
 			&meeting.UserID,
 			&meeting.Platform,
 			&meeting.MeetingID,
-			&meeting.BotContainerID,
-			&meeting.RecordingSessionID,
+			&meeting.MeetingURL,
+			&meeting.BotName,
+			&meeting.Status,
+			&meeting.ScheduledAt,
+			&meeting.MaxDuration,
+			&meeting.CreatedAt,
+			&meeting.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan meeting: %w", err)
+		}
+		meetings = append(meetings, &meeting)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return meetings, nil
+}
+
+// CalendarMeeting identifies a recording imported from a calendar occurrence
+type CalendarMeeting struct {
+	ID     int64
+	UserID int64
+	UID    string
+	Start  time.Time // Original occurrence start
+}
+
+// CreateFromCalendar creates a scheduled recording for a calendar occurrence.
+// Occurrences are imported once per user (by UID and original start); returns
+// false without creating anything if the occurrence was imported before.
+func (r *MeetingRepository) CreateFromCalendar(ctx context.Context, userID int64, req types.CreateRecordingRequest, meetingURL string, feedID *int64, uid string, start time.Time) (*types.Meeting, bool, error) {
+	now := time.Now()
+	query := `
+		INSERT INTO meetings (user_id, platform, meeting_id, meeting_url, bot_name, status, scheduled_at, max_duration,
+		                      calendar_feed_id, ical_uid, ical_start, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (user_id, ical_uid, ical_start) WHERE ical_uid IS NOT NULL DO NOTHING
+		RETURNING id
+	`
+
+	var botName *string
+	if req.BotName != "" {
+		botName = &req.BotName
+	}
+
+	var id int64
+	err := r.db.QueryRow(ctx, query, userID, req.Platform, req.MeetingID, meetingURL, botName, types.StatusScheduled,
+		req.ScheduledAt, req.MaxDuration, feedID, uid, start, now, now).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create meeting: %w", err)
+	}
+
+	return &types.Meeting{
+		ID:             id,
+		UserID:         userID,
+		Platform:       req.Platform,
+		MeetingID:      req.MeetingID,
+		MeetingURL:     meetingURL,
+		BotName:        botName,
+		Status:         types.MeetingStatusScheduled,
+		ScheduledAt:    req.ScheduledAt,
+		MaxDuration:    req.MaxDuration,
+		CalendarFeedID: feedID,
+		ICalUID:        &uid,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, true, nil
+}
+
+// ListScheduledByFeed retrieves the still-scheduled recordings imported from a calendar feed
+func (r *MeetingRepository) ListScheduledByFeed(ctx context.Context, feedID int64) ([]CalendarMeeting, error) {
+	query := `
+		SELECT id, user_id, ical_uid, ical_start
+		FROM meetings
+		WHERE calendar_feed_id = $1 AND status = $2 AND ical_uid IS NOT NULL
+	`
+
+	rows, err := r.db.Query(ctx, query, feedID, types.StatusScheduled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed meetings: %w", err)
+	}
+	defer rows.Close()
+
+	meetings := []CalendarMeeting{}
+	for rows.Next() {
+		var meeting CalendarMeeting
+		if err := rows.Scan(&meeting.ID, &meeting.UserID, &meeting.UID, &meeting.Start); err != nil {
+			return nil, fmt.Errorf("failed to scan meeting: %w", err)
+		}
+		meetings = append(meetings, meeting)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return meetings, nil
+}
+
+// FailMissedSchedules marks scheduled meetings whose start time passed before the
+// given time as failed (e.g. bot-manager was down). Returns the IDs updated.
+func (r *MeetingRepository) FailMissedSchedules(ctx context.Context, before time.Time, errorMsg string) ([]int64, error) {
+	query := `
+		UPDATE meetings
+		SET status = $1, error_message = $2, completed_at = $3, updated_at = $3
+		WHERE status = $4 AND scheduled_at < $5
+		RETURNING id
+	`
+
+	rows, err := r.db.Query(ctx, query, types.StatusFailed, errorMsg, time.Now(), types.StatusScheduled, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fail missed schedules: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan meeting id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+// CountActiveByUser counts the recordings of a user that have (or are about to get) a bot
+func (r *MeetingRepository) CountActiveByUser(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM meetings
+		WHERE user_id = $1 AND status IN ($2, $3, $4, $5)
+	`, userID, types.StatusRequested, types.StatusJoining, types.StatusActive, types.StatusRecording).Scan(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count active meetings: %w", err)
+	}
+
+	return count, nil
+}
+
+// Update updates a meeting with flexible filter and update params
+func (r *MeetingRepository) Update(ctx context.Context, filter types.MeetingFilter, update types.MeetingUpdate) error {
+	query := "UPDATE meetings SET updated_at = $1"
+	args := []interface{}{time.Now()}
+	paramIndex := 2
+
+	// Build SET clause
+	if update.Status != nil {
+		query += fmt.Sprintf(", status = $%d", paramIndex)
+		args = append(args, *update.Status)
+		paramIndex++
+	}
+	if update.BotContainerID != nil {
+		query += fmt.Sprintf(", bot_container_id = $%d", paramIndex)
+		args = append(args, *update.BotContainerID)
+		paramIndex++
+	}
+	if update.RecordingSessionID != nil {
+		query += fmt.Sprintf(", recording_session_id = $%d", paramIndex)
+		args = append(args, *update.RecordingSessionID)
+		paramIndex++
+	}
+	if update.RecordingPath != nil {
+		query += fmt.Sprintf(", recording_path = $%d", paramIndex)
+		args = append(args, *update.RecordingPath)
+		paramIndex++
+	}
+	if update.RecordingDuration != nil {
+		query += fmt.Sprintf(", recording_duration = $%d", paramIndex)
+		args = append(args, *update.RecordingDuration)
+		paramIndex++
+	}
+	if update.ErrorMessage != nil {
+		query += fmt.Sprintf(", error_message = $%d", paramIndex)
+		args = append(args, *update.ErrorMessage)
+		paramIndex++
+	}
+	if update.StartedAt != nil {
+		query += fmt.Sprintf(", started_at = $%d", paramIndex)
+		args = append(args, *update.StartedAt)
+		paramIndex++
+	}
+	if update.CompletedAt != nil {
+		query += fmt.Sprintf(", completed_at = $%d", paramIndex)
+		args = append(args, *update.CompletedAt)
+		paramIndex++
+	}
+
+	// Build WHERE clause
+	query += " WHERE 1=1"
+	if filter.ID != nil {
+		query += fmt.Sprintf(" AND id = $%d", paramIndex)
+		args = append(args, *filter.ID)
+		paramIndex++
+	}
+	if filter.UserID != nil {
+		query += fmt.Sprintf(" AND user_id = $%d", paramIndex)
+		args = append(args, *filter.UserID)
+		paramIndex++
+	}
+	if filter.Platform != nil {
+		query += fmt.Sprintf(" AND platform = $%d", paramIndex)
+		args = append(args, *filter.Platform)
+		paramIndex++
+	}
+	if filter.MeetingID != nil {
+		query += fmt.Sprintf(" AND meeting_id = $%d", paramIndex)
+		args = append(args, *filter.MeetingID)
+		paramIndex++
+	}
+	if filter.Status != nil {
+		query += fmt.Sprintf(" AND status = $%d", paramIndex)
+		args = append(args, *filter.Status)
+		paramIndex++
+	}
+	if filter.BotContainerID != nil {
+		query += fmt.Sprintf(" AND bot_container_id = $%d", paramIndex)
+		args = append(args, *filter.BotContainerID)
+		paramIndex++
+	}
+	if filter.RecordingSessionID != nil {
+		query += fmt.Sprintf(" AND recording_session_id = $%d", paramIndex)
+		args = append(args, *filter.RecordingSessionID)
+		paramIndex++
+	}
+
+	// Status writes must respect the state machine
+	if update.Status != nil {
+		statusClause, statusArgs := statusInClause(types.TransitionSources(types.MeetingStatus(*update.Status)), paramIndex)
+		query += " AND " + statusClause
+		args = append(args, statusArgs...)
+	}
+
+	result, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update meeting: %w", err)
+	}
+
+	if update.Status != nil && filter.ID != nil {
+		if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+			return transitionError(ctx, r.db, *filter.ID, types.MeetingStatus(*update.Status))
+		}
+	}
+
+	return nil
+}
+
+// GetActiveRecordings retrieves all recordings in active states (including finalizing)
+func (r *MeetingRepository) GetActiveRecordings(ctx context.Context) ([]*types.Meeting, error) {
+	query := `
+		SELECT id, user_id, platform, meeting_id, bot_container_id, bot_host, recording_session_id, status, meeting_url,
+		       recording_path, started_at, completed_at, error_message, created_at, updated_at
+		FROM meetings
+		WHERE status IN ($1, $2, $3, $4, $5)
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, types.StatusRequested, types.StatusJoining, types.StatusActive, types.StatusRecording, types.StatusFinalizing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active recordings: %w", err)
+	}
+	defer rows.Close()
+
+	meetings := []*types.Meeting{}
+	for rows.Next() {
+		var meeting types.Meeting
+		err := rows.Scan(
+			&meeting.ID,
+			&meeting.UserID,
+			&meeting.Platform,
+			&meeting.MeetingID,
+			&meeting.BotContainerID,
+			&meeting.BotHost,
+			&meeting.RecordingSessionID,
 			&meeting.Status,
 			&meeting.MeetingURL,
 			&meeting.RecordingPath,
@@ -680,3 +1269,895 @@ func (r *MeetingRepository) GetActiveRecordings(ctx context.Context) ([]*types.M
 
 	return meetings, nil
 }
+
+// =====================================================
+// MEETING EVENT REPOSITORY
+// =====================================================
+
+type MeetingEventRepository struct {
+	db Database
+}
+
+func NewMeetingEventRepository(db Database) *MeetingEventRepository {
+	return &MeetingEventRepository{db: db}
+}
+
+// Append records a bot status update in the meeting's event log
+func (r *MeetingEventRepository) Append(ctx context.Context, update types.BotStatusUpdate) error {
+	query := `
+		INSERT INTO meeting_events (meeting_id, status, container_id, chunk_count, error_message, occurred_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	now := time.Now()
+	occurredAt := update.Timestamp
+	if occurredAt.IsZero() {
+		occurredAt = now
+	}
+
+	var containerID *string
+	if update.ContainerID != "" {
+		containerID = &update.ContainerID
+	}
+
+	var chunkCount *int
+	if update.ChunkCount > 0 {
+		chunkCount = &update.ChunkCount
+	}
+
+	_, err := r.db.Exec(ctx, query, update.MeetingID, update.Status, containerID, chunkCount, update.ErrorMessage, occurredAt, now)
+	if err != nil {
+		return fmt.Errorf("failed to append meeting event: %w", err)
+	}
+
+	return nil
+}
+
+// ListByMeeting retrieves a meeting's event log, oldest first
+func (r *MeetingEventRepository) ListByMeeting(ctx context.Context, meetingID int64) ([]types.MeetingEvent, error) {
+	query := `
+		SELECT id, meeting_id, status, container_id, chunk_count, error_message, occurred_at, created_at
+		FROM meeting_events
+		WHERE meeting_id = $1
+		ORDER BY occurred_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list meeting events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []types.MeetingEvent{}
+	for rows.Next() {
+		var event types.MeetingEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.MeetingID,
+			&event.Status,
+			&event.ContainerID,
+			&event.ChunkCount,
+			&event.ErrorMessage,
+			&event.OccurredAt,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan meeting event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// =====================================================
+// RECORDING FILE REPOSITORY
+// =====================================================
+
+type RecordingFileRepository struct {
+	db Database
+}
+
+func NewRecordingFileRepository(db Database) *RecordingFileRepository {
+	return &RecordingFileRepository{db: db}
+}
+
+const recordingFileColumns = `id, meeting_id, path, format, codec, sample_rate, channels, bitrate, size_bytes, duration_seconds, dropped_chunks, processing, created_at`
+
+// Create stores a finalized recording file (file.ID and file.CreatedAt are set).
+// A file stored again at the same path (a retried finalization) replaces its row.
+func (r *RecordingFileRepository) Create(ctx context.Context, file *types.RecordingFile) error {
+	query := `
+		INSERT INTO recording_files (meeting_id, path, format, codec, sample_rate, channels, bitrate, size_bytes, duration_seconds, dropped_chunks, processing, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (meeting_id, path) DO UPDATE
+		SET format = EXCLUDED.format, codec = EXCLUDED.codec, sample_rate = EXCLUDED.sample_rate,
+		    channels = EXCLUDED.channels, bitrate = EXCLUDED.bitrate, size_bytes = EXCLUDED.size_bytes,
+		    duration_seconds = EXCLUDED.duration_seconds, dropped_chunks = EXCLUDED.dropped_chunks,
+		    processing = EXCLUDED.processing, created_at = EXCLUDED.created_at
+		RETURNING id
+	`
+
+	var processing []byte
+	if len(file.Processing) > 0 {
+		encoded, err := json.Marshal(file.Processing)
+		if err != nil {
+			return fmt.Errorf("failed to encode processing steps: %w", err)
+		}
+		processing = encoded
+	}
+
+	file.CreatedAt = time.Now()
+	err := r.db.QueryRow(ctx, query, file.MeetingID, file.Path, file.Format, file.Codec, file.SampleRate,
+		file.Channels, file.Bitrate, file.SizeBytes, file.DurationSeconds, pq.Array(file.DroppedChunks), processing, file.CreatedAt).Scan(&file.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create recording file: %w", err)
+	}
+
+	return nil
+}
+
+// ListByMeeting retrieves a meeting's recording files
+func (r *RecordingFileRepository) ListByMeeting(ctx context.Context, meetingID int64) ([]types.RecordingFile, error) {
+	files, err := r.ListByMeetings(ctx, []int64{meetingID})
+	if err != nil {
+		return nil, err
+	}
+	return files[meetingID], nil
+}
+
+// ListByMeetings retrieves the recording files of several meetings, keyed by meeting ID
+func (r *RecordingFileRepository) ListByMeetings(ctx context.Context, meetingIDs []int64) (map[int64][]types.RecordingFile, error) {
+	files := map[int64][]types.RecordingFile{}
+	if len(meetingIDs) == 0 {
+		return files, nil
+	}
+
+	query := `SELECT ` + recordingFileColumns + ` FROM recording_files WHERE meeting_id = ANY($1) ORDER BY id ASC`
+
+	rows, err := r.db.Query(ctx, query, pq.Array(meetingIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recording files: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var file types.RecordingFile
+		var processing []byte
+		err := rows.Scan(
+			&file.ID,
+			&file.MeetingID,
+			&file.Path,
+			&file.Format,
+			&file.Codec,
+			&file.SampleRate,
+			&file.Channels,
+			&file.Bitrate,
+			&file.SizeBytes,
+			&file.DurationSeconds,
+			pq.Array(&file.DroppedChunks),
+			&processing,
+			&file.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recording file: %w", err)
+		}
+		if len(processing) > 0 {
+			if err := json.Unmarshal(processing, &file.Processing); err != nil {
+				return nil, fmt.Errorf("failed to decode processing steps: %w", err)
+			}
+		}
+		files[file.MeetingID] = append(files[file.MeetingID], file)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return files, nil
+}
+
+// =====================================================
+// FINALIZATION JOB REPOSITORY
+// =====================================================
+
+type FinalizationJobRepository struct {
+	db Database
+}
+
+func NewFinalizationJobRepository(db Database) *FinalizationJobRepository {
+	return &FinalizationJobRepository{db: db}
+}
+
+// Queue records a queued finalization for a meeting, resetting a finished job.
+// Returns false if a finalization is already queued or running for the meeting.
+func (r *FinalizationJobRepository) Queue(ctx context.Context, meetingID int64, containerID string, chunkCount int) (bool, error) {
+	query := `
+		INSERT INTO finalization_jobs (meeting_id, container_id, status, chunk_count, attempts, queued_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, 0, $5, $5)
+		ON CONFLICT (meeting_id) DO UPDATE
+		SET container_id = COALESCE(EXCLUDED.container_id, finalization_jobs.container_id),
+		    status = EXCLUDED.status, chunk_count = EXCLUDED.chunk_count, attempts = 0,
+		    last_error = NULL, queued_at = EXCLUDED.queued_at, started_at = NULL,
+		    finished_at = NULL, updated_at = EXCLUDED.updated_at
+		WHERE finalization_jobs.status IN ($6, $7)
+		RETURNING meeting_id
+	`
+
+	var id int64
+	err := r.db.QueryRow(ctx, query, meetingID, containerID, types.FinalizationQueued, chunkCount, time.Now(),
+		types.FinalizationCompleted, types.FinalizationFailed).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to queue finalization job: %w", err)
+	}
+
+	return true, nil
+}
+
+// Start marks a finalization attempt as running
+func (r *FinalizationJobRepository) Start(ctx context.Context, meetingID int64) error {
+	query := `
+		UPDATE finalization_jobs
+		SET status = $1, attempts = attempts + 1, started_at = $2, updated_at = $2
+		WHERE meeting_id = $3
+	`
+
+	if _, err := r.db.Exec(ctx, query, types.FinalizationRunning, time.Now(), meetingID); err != nil {
+		return fmt.Errorf("failed to start finalization job: %w", err)
+	}
+
+	return nil
+}
+
+// Retry records a failed attempt that will be retried
+func (r *FinalizationJobRepository) Retry(ctx context.Context, meetingID int64, errorMsg string) error {
+	return r.finish(ctx, meetingID, types.FinalizationRetrying, &errorMsg, false)
+}
+
+// Complete marks a finalization as completed
+func (r *FinalizationJobRepository) Complete(ctx context.Context, meetingID int64) error {
+	return r.finish(ctx, meetingID, types.FinalizationCompleted, nil, true)
+}
+
+// Fail marks a finalization as failed for good
+func (r *FinalizationJobRepository) Fail(ctx context.Context, meetingID int64, errorMsg string) error {
+	return r.finish(ctx, meetingID, types.FinalizationFailed, &errorMsg, true)
+}
+
+// finish records the outcome of an attempt
+func (r *FinalizationJobRepository) finish(ctx context.Context, meetingID int64, status types.FinalizationJobStatus, errorMsg *string, final bool) error {
+	query := `
+		UPDATE finalization_jobs
+		SET status = $1, last_error = $2, finished_at = CASE WHEN $3 THEN $4 ELSE finished_at END, updated_at = $4
+		WHERE meeting_id = $5
+	`
+
+	if _, err := r.db.Exec(ctx, query, status, errorMsg, final, time.Now(), meetingID); err != nil {
+		return fmt.Errorf("failed to update finalization job: %w", err)
+	}
+
+	return nil
+}
+
+// GetByMeeting retrieves a meeting's finalization job
+func (r *FinalizationJobRepository) GetByMeeting(ctx context.Context, meetingID int64) (*types.FinalizationJob, error) {
+	query := `
+		SELECT meeting_id, container_id, status, chunk_count, attempts, last_error,
+		       queued_at, started_at, finished_at, updated_at
+		FROM finalization_jobs WHERE meeting_id = $1
+	`
+
+	var job types.FinalizationJob
+	err := r.db.QueryRow(ctx, query, meetingID).Scan(
+		&job.MeetingID,
+		&job.ContainerID,
+		&job.Status,
+		&job.ChunkCount,
+		&job.Attempts,
+		&job.LastError,
+		&job.QueuedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+		&job.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("finalization job not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get finalization job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// =====================================================
+// WEBHOOK REPOSITORY
+// =====================================================
+
+type WebhookRepository struct {
+	db Database
+}
+
+func NewWebhookRepository(db Database) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// DueWebhookDelivery is a claimed delivery together with its endpoint details
+type DueWebhookDelivery struct {
+	types.WebhookDelivery
+	URL    string
+	Secret string
+}
+
+const webhookEndpointColumns = `id, user_id, url, secret, events, description, is_active, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, endpoint_id, meeting_id, event_type, payload, status, attempts,
+		       last_status_code, last_error, next_attempt_at, delivered_at, created_at, updated_at`
+
+// CreateEndpoint registers a webhook endpoint for a user
+func (r *WebhookRepository) CreateEndpoint(ctx context.Context, userID int64, url, secret string, events []types.WebhookEventType, description *string) (*types.WebhookEndpoint, error) {
+	query := `
+		INSERT INTO webhook_endpoints (user_id, url, secret, events, description, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, true, $6, $7)
+		RETURNING ` + webhookEndpointColumns
+
+	now := time.Now()
+	endpoint, err := scanWebhookEndpoint(r.db.QueryRow(ctx, query, userID, url, secret, pq.Array(eventStrings(events)), description, now, now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+// GetEndpoint retrieves a webhook endpoint by ID
+func (r *WebhookRepository) GetEndpoint(ctx context.Context, id int64) (*types.WebhookEndpoint, error) {
+	query := `SELECT ` + webhookEndpointColumns + ` FROM webhook_endpoints WHERE id = $1`
+
+	endpoint, err := scanWebhookEndpoint(r.db.QueryRow(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook endpoint not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+// ListEndpoints retrieves webhook endpoints for a user
+// Use userID=0 to list all endpoints (admin privilege)
+func (r *WebhookRepository) ListEndpoints(ctx context.Context, userID int64) ([]*types.WebhookEndpoint, error) {
+	query := `SELECT ` + webhookEndpointColumns + ` FROM webhook_endpoints WHERE ($1 = 0 OR user_id = $1) ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	endpoints := []*types.WebhookEndpoint{}
+	for rows.Next() {
+		endpoint, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook endpoint: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return endpoints, nil
+}
+
+// ListActiveEndpointsForEvent retrieves a user's active endpoints subscribed to an event
+func (r *WebhookRepository) ListActiveEndpointsForEvent(ctx context.Context, userID int64, event types.WebhookEventType) ([]*types.WebhookEndpoint, error) {
+	query := `
+		SELECT ` + webhookEndpointColumns + `
+		FROM webhook_endpoints
+		WHERE user_id = $1 AND is_active = true AND $2 = ANY(events)
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, string(event))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	endpoints := []*types.WebhookEndpoint{}
+	for rows.Next() {
+		endpoint, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook endpoint: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return endpoints, nil
+}
+
+// CountEndpoints counts a user's webhook endpoints
+func (r *WebhookRepository) CountEndpoints(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM webhook_endpoints WHERE user_id = $1`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count webhook endpoints: %w", err)
+	}
+	return count, nil
+}
+
+// UpdateEndpoint updates a webhook endpoint (nil fields are left unchanged)
+func (r *WebhookRepository) UpdateEndpoint(ctx context.Context, id int64, req types.UpdateWebhookRequest) error {
+	query := "UPDATE webhook_endpoints SET updated_at = $1"
+	args := []interface{}{time.Now()}
+	paramIndex := 2
+
+	if req.URL != nil {
+		query += fmt.Sprintf(", url = $%d", paramIndex)
+		args = append(args, *req.URL)
+		paramIndex++
+	}
+	if req.Events != nil {
+		query += fmt.Sprintf(", events = $%d", paramIndex)
+		args = append(args, pq.Array(eventStrings(req.Events)))
+		paramIndex++
+	}
+	if req.Description != nil {
+		query += fmt.Sprintf(", description = $%d", paramIndex)
+		args = append(args, *req.Description)
+		paramIndex++
+	}
+	if req.IsActive != nil {
+		query += fmt.Sprintf(", is_active = $%d", paramIndex)
+		args = append(args, *req.IsActive)
+		paramIndex++
+	}
+
+	query += fmt.Sprintf(" WHERE id = $%d", paramIndex)
+	args = append(args, id)
+
+	result, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook endpoint: %w", err)
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("webhook endpoint not found")
+	}
+
+	return nil
+}
+
+// DeleteEndpoint deletes a webhook endpoint and its delivery log
+func (r *WebhookRepository) DeleteEndpoint(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("webhook endpoint not found")
+	}
+
+	return nil
+}
+
+// CreateDelivery queues a delivery of an event to an endpoint.
+// Each event is delivered once per endpoint and meeting; returns false if it was already queued.
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, endpointID, meetingID int64, event types.WebhookEventType, payload []byte) (bool, error) {
+	query := `
+		INSERT INTO webhook_deliveries (endpoint_id, meeting_id, event_type, payload, status, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6, $6)
+		ON CONFLICT (endpoint_id, meeting_id, event_type) DO NOTHING
+	`
+
+	result, err := r.db.Exec(ctx, query, endpointID, meetingID, string(event), payload, string(types.WebhookDeliveryPending), time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// ClaimDueDeliveries claims pending deliveries that are due and counts the attempt.
+// Claimed deliveries are pushed back by lease, so another worker only retries them
+// if this one dies before recording the result.
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*DueWebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1, next_attempt_at = $1, updated_at = $2
+		FROM webhook_endpoints e
+		WHERE d.endpoint_id = e.id AND d.id IN (
+			SELECT wd.id FROM webhook_deliveries wd
+			JOIN webhook_endpoints we ON we.id = wd.endpoint_id
+			WHERE wd.status = $3 AND wd.next_attempt_at <= $2 AND we.is_active = true
+			ORDER BY wd.next_attempt_at
+			LIMIT $4
+			FOR UPDATE OF wd SKIP LOCKED
+		)
+		RETURNING d.id, d.endpoint_id, d.meeting_id, d.event_type, d.payload, d.status, d.attempts,
+		          d.last_status_code, d.last_error, d.next_attempt_at, d.delivered_at, d.created_at, d.updated_at,
+		          e.url, e.secret
+	`
+
+	now := time.Now()
+	rows, err := r.db.Query(ctx, query, now.Add(lease), now, string(types.WebhookDeliveryPending), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*DueWebhookDelivery{}
+	for rows.Next() {
+		var due DueWebhookDelivery
+		var payload []byte
+		err := rows.Scan(
+			&due.ID,
+			&due.EndpointID,
+			&due.MeetingID,
+			&due.EventType,
+			&payload,
+			&due.Status,
+			&due.Attempts,
+			&due.LastStatusCode,
+			&due.LastError,
+			&due.NextAttemptAt,
+			&due.DeliveredAt,
+			&due.CreatedAt,
+			&due.UpdatedAt,
+			&due.URL,
+			&due.Secret,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		due.Payload = payload
+		deliveries = append(deliveries, &due)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// MarkDeliverySucceeded records a successful delivery
+func (r *WebhookRepository) MarkDeliverySucceeded(ctx context.Context, id int64, statusCode int) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, last_status_code = $2, last_error = NULL, delivered_at = $3, updated_at = $3
+		WHERE id = $4
+	`
+
+	_, err := r.db.Exec(ctx, query, string(types.WebhookDeliverySucceeded), statusCode, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook delivery as succeeded: %w", err)
+	}
+	return nil
+}
+
+// MarkDeliveryFailed records a failed attempt. With a nil retryAt the delivery is given up.
+func (r *WebhookRepository) MarkDeliveryFailed(ctx context.Context, id int64, statusCode *int, errMsg string, retryAt *time.Time) error {
+	status := types.WebhookDeliveryPending
+	nextAttemptAt := time.Now()
+	if retryAt != nil {
+		nextAttemptAt = *retryAt
+	} else {
+		status = types.WebhookDeliveryFailed
+	}
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, last_status_code = $2, last_error = $3, next_attempt_at = $4, updated_at = $5
+		WHERE id = $6
+	`
+
+	_, err := r.db.Exec(ctx, query, string(status), statusCode, errMsg, nextAttemptAt, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook delivery as failed: %w", err)
+	}
+	return nil
+}
+
+// GetDelivery retrieves a webhook delivery by ID
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id int64) (*types.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	delivery, err := scanWebhookDelivery(r.db.QueryRow(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook delivery not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// ListDeliveries retrieves an endpoint's delivery log with pagination, newest first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, endpointID int64, limit, offset int) ([]*types.WebhookDelivery, int64, error) {
+	var total int64
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM webhook_deliveries WHERE endpoint_id = $1`, endpointID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE endpoint_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, endpointID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*types.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return deliveries, total, nil
+}
+
+// ResetDelivery queues a delivery to be sent again immediately with a fresh attempt count
+func (r *WebhookRepository) ResetDelivery(ctx context.Context, id int64) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = 0, next_attempt_at = $2, updated_at = $2
+		WHERE id = $3
+	`
+
+	result, err := r.db.Exec(ctx, query, string(types.WebhookDeliveryPending), time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to reset webhook delivery: %w", err)
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("webhook delivery not found")
+	}
+
+	return nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanWebhookEndpoint scans a row selected with webhookEndpointColumns
+func scanWebhookEndpoint(row rowScanner) (*types.WebhookEndpoint, error) {
+	var endpoint types.WebhookEndpoint
+	var events []string
+	err := row.Scan(
+		&endpoint.ID,
+		&endpoint.UserID,
+		&endpoint.URL,
+		&endpoint.Secret,
+		pq.Array(&events),
+		&endpoint.Description,
+		&endpoint.IsActive,
+		&endpoint.CreatedAt,
+		&endpoint.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.Events = make([]types.WebhookEventType, len(events))
+	for i, event := range events {
+		endpoint.Events[i] = types.WebhookEventType(event)
+	}
+
+	return &endpoint, nil
+}
+
+// scanWebhookDelivery scans a row selected with webhookDeliveryColumns
+func scanWebhookDelivery(row rowScanner) (*types.WebhookDelivery, error) {
+	var delivery types.WebhookDelivery
+	var payload []byte
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.EndpointID,
+		&delivery.MeetingID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.NextAttemptAt,
+		&delivery.DeliveredAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = payload
+	return &delivery, nil
+}
+
+// eventStrings converts event types for storage in a TEXT[] column
+func eventStrings(events []types.WebhookEventType) []string {
+	strs := make([]string, len(events))
+	for i, event := range events {
+		strs[i] = string(event)
+	}
+	return strs
+}
+
+// =====================================================
+// CALENDAR FEED REPOSITORY
+// =====================================================
+
+type CalendarFeedRepository struct {
+	db Database
+}
+
+func NewCalendarFeedRepository(db Database) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+const calendarFeedColumns = `id, user_id, url, bot_name, max_duration, is_active, last_synced_at, last_sync_error, created_at, updated_at`
+
+// Create registers a calendar feed for a user
+func (r *CalendarFeedRepository) Create(ctx context.Context, userID int64, url string, botName *string, maxDuration *int) (*types.CalendarFeed, error) {
+	query := `
+		INSERT INTO calendar_feeds (user_id, url, bot_name, max_duration, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, true, $5, $6)
+		RETURNING ` + calendarFeedColumns
+
+	now := time.Now()
+	feed, err := scanCalendarFeed(r.db.QueryRow(ctx, query, userID, url, botName, maxDuration, now, now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar feed: %w", err)
+	}
+
+	return feed, nil
+}
+
+// GetByID retrieves a calendar feed by ID
+func (r *CalendarFeedRepository) GetByID(ctx context.Context, id int64) (*types.CalendarFeed, error) {
+	query := `SELECT ` + calendarFeedColumns + ` FROM calendar_feeds WHERE id = $1`
+
+	feed, err := scanCalendarFeed(r.db.QueryRow(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("calendar feed not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+
+	return feed, nil
+}
+
+// List retrieves a user's calendar feeds
+func (r *CalendarFeedRepository) List(ctx context.Context, userID int64) ([]*types.CalendarFeed, error) {
+	query := `SELECT ` + calendarFeedColumns + ` FROM calendar_feeds WHERE user_id = $1 ORDER BY created_at DESC`
+	return r.list(ctx, query, userID)
+}
+
+// ListDue retrieves active feeds that were not synced since the given time
+func (r *CalendarFeedRepository) ListDue(ctx context.Context, syncedBefore time.Time) ([]*types.CalendarFeed, error) {
+	query := `
+		SELECT ` + calendarFeedColumns + `
+		FROM calendar_feeds
+		WHERE is_active = true AND (last_synced_at IS NULL OR last_synced_at < $1)
+		ORDER BY last_synced_at ASC NULLS FIRST
+	`
+	return r.list(ctx, query, syncedBefore)
+}
+
+// list runs a query selecting calendarFeedColumns
+func (r *CalendarFeedRepository) list(ctx context.Context, query string, args ...interface{}) ([]*types.CalendarFeed, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendar feeds: %w", err)
+	}
+	defer rows.Close()
+
+	feeds := []*types.CalendarFeed{}
+	for rows.Next() {
+		feed, err := scanCalendarFeed(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan calendar feed: %w", err)
+		}
+		feeds = append(feeds, feed)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return feeds, nil
+}
+
+// Count counts a user's calendar feeds
+func (r *CalendarFeedRepository) Count(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM calendar_feeds WHERE user_id = $1`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count calendar feeds: %w", err)
+	}
+	return count, nil
+}
+
+// RecordSync stores the outcome of a feed sync (syncErr is nil on success)
+func (r *CalendarFeedRepository) RecordSync(ctx context.Context, id int64, syncErr *string) error {
+	query := `
+		UPDATE calendar_feeds
+		SET last_synced_at = $1, last_sync_error = $2, updated_at = $1
+		WHERE id = $3
+	`
+
+	_, err := r.db.Exec(ctx, query, time.Now(), syncErr, id)
+	if err != nil {
+		return fmt.Errorf("failed to record calendar feed sync: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a calendar feed (its recordings are kept)
+func (r *CalendarFeedRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM calendar_feeds WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("calendar feed not found")
+	}
+
+	return nil
+}
+
+// scanCalendarFeed scans a row selected with calendarFeedColumns
+func scanCalendarFeed(row rowScanner) (*types.CalendarFeed, error) {
+	var feed types.CalendarFeed
+	err := row.Scan(
+		&feed.ID,
+		&feed.UserID,
+		&feed.URL,
+		&feed.BotName,
+		&feed.MaxDuration,
+		&feed.IsActive,
+		&feed.LastSyncedAt,
+		&feed.LastSyncError,
+		&feed.CreatedAt,
+		&feed.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
@@ -0,0 +1,214 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/newar/insights/shared/types"
+)
+
+var (
+	wherePattern    = regexp.MustCompile(`(?s)WHERE (.*)$`)
+	idPattern       = regexp.MustCompile(`\bid = \$(\d+)`)
+	statusInPattern = regexp.MustCompile(`\bstatus IN \(([^)]*)\)`)
+	statusEqPattern = regexp.MustCompile(`\bstatus = \$(\d+)`)
+)
+
+// fakeMeetings is a database/sql driver holding only the status of each meeting.
+// It evaluates the id and status conditions of "UPDATE meetings SET status = $1 ...
+// WHERE ..." the way Postgres would, so compare-and-set writes can be tested.
+type fakeMeetings struct {
+	mu       sync.Mutex
+	statuses map[int64]string
+}
+
+func newTestMeetingRepository(statuses map[int64]string) (*MeetingRepository, *fakeMeetings) {
+	f := &fakeMeetings{statuses: statuses}
+	return NewMeetingRepository(&PostgresDB{db: sql.OpenDB(f)}), f
+}
+
+func (f *fakeMeetings) Connect(context.Context) (driver.Conn, error) { return fakeConn{f}, nil }
+func (f *fakeMeetings) Driver() driver.Driver                        { return nil }
+
+func (f *fakeMeetings) status(id int64) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.statuses[id]
+}
+
+type fakeConn struct{ f *fakeMeetings }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+// arg returns the value bound to placeholder $n
+func arg(args []driver.NamedValue, n string) driver.Value {
+	i, _ := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(n), "$"))
+	return args[i-1].Value
+}
+
+func (c fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if !strings.Contains(query, "UPDATE meetings") || !strings.Contains(query, "SET status = $1") {
+		return nil, errors.New("unexpected query: " + query)
+	}
+	where := wherePattern.FindStringSubmatch(query)[1]
+
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+
+	id := arg(args, idPattern.FindStringSubmatch(where)[1]).(int64)
+	current, ok := c.f.statuses[id]
+	if !ok {
+		return driver.RowsAffected(0), nil
+	}
+
+	allowed := []string{}
+	if m := statusInPattern.FindStringSubmatch(where); m != nil {
+		for _, n := range strings.Split(m[1], ",") {
+			allowed = append(allowed, arg(args, n).(string))
+		}
+	} else if m := statusEqPattern.FindStringSubmatch(where); m != nil {
+		allowed = append(allowed, arg(args, m[1]).(string))
+	}
+
+	for _, status := range allowed {
+		if status == current {
+			c.f.statuses[id] = args[0].Value.(string)
+			return driver.RowsAffected(1), nil
+		}
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.HasPrefix(query, "SELECT status FROM meetings WHERE id = $1") {
+		return nil, errors.New("unexpected query: " + query)
+	}
+
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+
+	status, ok := c.f.statuses[args[0].Value.(int64)]
+	if !ok {
+		return &fakeRows{}, nil
+	}
+	return &fakeRows{values: []string{status}}, nil
+}
+
+type fakeRows struct{ values []string }
+
+func (r *fakeRows) Columns() []string { return []string{"status"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+func TestUpdateStatusCompareAndSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    types.MeetingStatus
+		to      types.MeetingStatus
+		wantErr bool
+	}{
+		{"requested to joining", types.MeetingStatusRequested, types.MeetingStatusJoining, false},
+		{"recording to finalizing", types.MeetingStatusRecording, types.MeetingStatusFinalizing, false},
+		{"finalizing to completed", types.MeetingStatusFinalizing, types.MeetingStatusCompleted, false},
+		{"joining to failed", types.MeetingStatusJoining, types.MeetingStatusFailed, false},
+		{"active back to requested", types.MeetingStatusActive, types.MeetingStatusRequested, true},
+		{"completed to failed", types.MeetingStatusCompleted, types.MeetingStatusFailed, true},
+		{"failed to active", types.MeetingStatusFailed, types.MeetingStatusActive, true},
+		{"joining to finalizing", types.MeetingStatusJoining, types.MeetingStatusFinalizing, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, db := newTestMeetingRepository(map[int64]string{42: string(tt.from)})
+
+			err := repo.UpdateStatus(context.Background(), 42, tt.to, nil, nil, nil)
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("UpdateStatus() error = %v", err)
+				}
+				if got := db.status(42); got != string(tt.to) {
+					t.Errorf("status = %s, want %s", got, tt.to)
+				}
+				return
+			}
+
+			var transitionErr *InvalidTransitionError
+			if !errors.As(err, &transitionErr) {
+				t.Fatalf("UpdateStatus() error = %v, want InvalidTransitionError", err)
+			}
+			want := InvalidTransitionError{MeetingID: 42, From: tt.from, To: tt.to}
+			if *transitionErr != want {
+				t.Errorf("error = %+v, want %+v", *transitionErr, want)
+			}
+			if got := db.status(42); got != string(tt.from) {
+				t.Errorf("rejected write changed status to %s", got)
+			}
+		})
+	}
+}
+
+func TestUpdateStatusMissingMeeting(t *testing.T) {
+	repo, _ := newTestMeetingRepository(map[int64]string{})
+
+	err := repo.UpdateStatus(context.Background(), 42, types.StatusJoining, nil, nil, nil)
+
+	var transitionErr *InvalidTransitionError
+	if err == nil || errors.As(err, &transitionErr) {
+		t.Errorf("UpdateStatus() of a missing meeting error = %v, want not found", err)
+	}
+}
+
+func TestCancelScheduled(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    types.MeetingStatus
+		wantErr bool
+	}{
+		{"scheduled", types.MeetingStatusScheduled, false},
+		{"released by the scheduler", types.MeetingStatusRequested, true},
+		{"bot joining", types.MeetingStatusJoining, true},
+		{"already failed", types.MeetingStatusFailed, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, db := newTestMeetingRepository(map[int64]string{42: string(tt.from)})
+
+			err := repo.CancelScheduled(context.Background(), 42, "Scheduled recording cancelled")
+
+			var transitionErr *InvalidTransitionError
+			if tt.wantErr != errors.As(err, &transitionErr) {
+				t.Fatalf("CancelScheduled() error = %v, want InvalidTransitionError %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("CancelScheduled() error = %v", err)
+			}
+
+			want := string(tt.from)
+			if !tt.wantErr {
+				want = types.StatusFailed
+			}
+			if got := db.status(42); got != want {
+				t.Errorf("status = %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -150,6 +150,11 @@ func (m *Meeting) IsActive() bool {
 		m.status == types.MeetingStatusRecording
 }
 
+// IsScheduled checks if the meeting is waiting for its scheduled start
+func (m *Meeting) IsScheduled() bool {
+	return m.status == types.MeetingStatusScheduled
+}
+
 // IsFinished checks if the meeting is in a finished state
 func (m *Meeting) IsFinished() bool {
 	return m.status == types.MeetingStatusCompleted ||
@@ -157,41 +162,9 @@ func (m *Meeting) IsFinished() bool {
 }
 
 // CanTransitionTo validates state machine transitions
+// The transition table lives in types so the database layer enforces the same rules.
 func (m *Meeting) CanTransitionTo(newStatus types.MeetingStatus) bool {
-	validTransitions := map[types.MeetingStatus][]types.MeetingStatus{
-		types.MeetingStatusRequested: {
-			types.MeetingStatusJoining,
-			types.MeetingStatusFailed,
-		},
-		types.MeetingStatusJoining: {
-			types.MeetingStatusActive,
-			types.MeetingStatusFailed,
-		},
-		types.MeetingStatusActive: {
-			types.MeetingStatusRecording,
-			types.MeetingStatusFailed,
-		},
-		types.MeetingStatusRecording: {
-			types.MeetingStatusFinalizing,
-			types.MeetingStatusFailed,
-		},
-		types.MeetingStatusFinalizing: {
-			types.MeetingStatusCompleted,
-			types.MeetingStatusFailed,
-		},
-	}
-
-	allowed, exists := validTransitions[m.status]
-	if !exists {
-		return false
-	}
-
-	for _, s := range allowed {
-		if s == newStatus {
-			return true
-		}
-	}
-	return false
+	return types.CanTransition(m.status, newStatus)
 }
 
 // TransitionTo transitions the meeting to a new status
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/newar/insights/shared/constants"
 	"github.com/newar/insights/shared/domain/valueobjects"
+	"github.com/newar/insights/shared/types"
 )
 
 // User represents a user entity with rich domain logic
@@ -112,6 +114,68 @@ func (u *User) UpdateMaxConcurrentBots(max int) error {
 	return nil
 }
 
+// DefaultMaxDuration returns the default max recording duration in seconds (0 if unset)
+func (u *User) DefaultMaxDuration() int {
+	switch seconds := u.data[constants.UserMaxDurationKey].(type) {
+	case int:
+		return seconds
+	case float64: // Decoded from JSON
+		return int(seconds)
+	}
+	return 0
+}
+
+// UpdateDefaultMaxDuration sets the default max recording duration (0 clears it)
+func (u *User) UpdateDefaultMaxDuration(seconds int) error {
+	if seconds == 0 {
+		delete(u.data, constants.UserMaxDurationKey)
+		u.updatedAt = time.Now()
+		return nil
+	}
+
+	maxSeconds := int(constants.MaxRecordingDuration.Seconds())
+	if seconds < constants.MinRecordingDuration || seconds > maxSeconds {
+		return fmt.Errorf("default max duration must be between %d and %d seconds", constants.MinRecordingDuration, maxSeconds)
+	}
+
+	u.SetData(constants.UserMaxDurationKey, seconds)
+	return nil
+}
+
+// DefaultOutputFormats returns the default extra output formats for recordings
+func (u *User) DefaultOutputFormats() []string {
+	switch formats := u.data[constants.UserOutputFormatsKey].(type) {
+	case []string:
+		return formats
+	case []interface{}: // Decoded from JSON
+		names := make([]string, 0, len(formats))
+		for _, format := range formats {
+			if name, ok := format.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// UpdateDefaultOutputFormats sets the default extra output formats (empty clears them)
+func (u *User) UpdateDefaultOutputFormats(formats []string) error {
+	if len(formats) == 0 {
+		delete(u.data, constants.UserOutputFormatsKey)
+		u.updatedAt = time.Now()
+		return nil
+	}
+
+	formats, err := types.ParseOutputFormats(formats)
+	if err != nil {
+		return err
+	}
+
+	u.SetData(constants.UserOutputFormatsKey, formats)
+	return nil
+}
+
 // SetData sets custom data for the user
 func (u *User) SetData(key string, value interface{}) {
 	u.data[key] = value
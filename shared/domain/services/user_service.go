@@ -89,6 +89,46 @@ func (s *UserService) UpdateMaxConcurrentBots(ctx context.Context, userID int64,
 	return s.userRepo.Save(ctx, user)
 }
 
+// UpdateUser applies the given changes to a user (nil fields are left unchanged)
+// and returns the updated user. A default max duration of 0 or an empty (non-nil)
+// list of default output formats clears the setting.
+func (s *UserService) UpdateUser(ctx context.Context, userID int64, name *string, maxConcurrentBots *int, defaultMaxDuration *int, defaultOutputFormats []string) (*entities.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if name != nil {
+		if err := user.UpdateName(*name); err != nil {
+			return nil, err
+		}
+	}
+
+	if maxConcurrentBots != nil {
+		if err := user.UpdateMaxConcurrentBots(*maxConcurrentBots); err != nil {
+			return nil, err
+		}
+	}
+
+	if defaultMaxDuration != nil {
+		if err := user.UpdateDefaultMaxDuration(*defaultMaxDuration); err != nil {
+			return nil, err
+		}
+	}
+
+	if defaultOutputFormats != nil {
+		if err := user.UpdateDefaultOutputFormats(defaultOutputFormats); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.userRepo.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
 // CanUserSpawnBot checks if a user can spawn a new bot
 func (s *UserService) CanUserSpawnBot(ctx context.Context, userID int64) (bool, error) {
 	user, err := s.userRepo.FindByID(ctx, userID)
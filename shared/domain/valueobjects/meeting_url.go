@@ -3,9 +3,13 @@ package valueobjects
 import (
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
 )
 
+// urlPattern matches http(s) URLs in free text (e.g. calendar invites)
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'\\]+`)
+
 // MeetingURL represents an immutable meeting URL value object
 type MeetingURL struct {
 	value string
@@ -62,6 +66,36 @@ func (m MeetingURL) IsTeams() bool {
 	return strings.Contains(strings.ToLower(m.value), "teams.microsoft.com")
 }
 
+// FindMeetingURL returns the first Google Meet or Teams link in free text
+func FindMeetingURL(text string) (MeetingURL, bool) {
+	for _, match := range urlPattern.FindAllString(text, -1) {
+		// Links in prose often end with punctuation that is not part of the URL
+		match = strings.TrimRight(match, ".,;:!?)]}>")
+
+		meetingURL, err := NewMeetingURL(match)
+		if err != nil {
+			continue
+		}
+		if meetingURL.IsGoogleMeet() || meetingURL.IsTeams() {
+			return meetingURL, true
+		}
+	}
+	return MeetingURL{}, false
+}
+
+// NativeMeetingID returns the meeting ID used for recordings on the URL's platform:
+// the meeting code for Google Meet, and the full URL for Teams (as it has no short code)
+func (m MeetingURL) NativeMeetingID() string {
+	if m.IsGoogleMeet() {
+		parsedURL, err := url.Parse(m.value)
+		if err != nil {
+			return ""
+		}
+		return strings.Split(strings.Trim(parsedURL.Path, "/"), "/")[0]
+	}
+	return m.value
+}
+
 // String implements Stringer interface
 func (m MeetingURL) String() string {
 	return m.value
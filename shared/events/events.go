@@ -0,0 +1,151 @@
+// Package events publishes and consumes structured meeting events on the
+// global meeting:events Redis channel. Every message is a
+// types.MeetingEventEnvelope; integrations subscribe with a Consumer and
+// register handlers per event type.
+//
+// The channel is Redis Pub/Sub, so delivery is best-effort: consumers only see
+// events published while they are subscribed. Use webhooks or the meeting
+// event log where every event must be seen.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/redis"
+	"github.com/newar/insights/shared/types"
+)
+
+// ErrUnsupportedVersion is returned when decoding an envelope newer than this build understands
+var ErrUnsupportedVersion = errors.New("unsupported meeting event version")
+
+// =====================================================
+// PUBLISHER
+// =====================================================
+
+// Publisher publishes meeting events
+type Publisher struct {
+	client *redis.Client
+}
+
+// NewPublisher creates a new meeting event publisher
+func NewPublisher(client *redis.Client) *Publisher {
+	return &Publisher{client: client}
+}
+
+// Publish wraps payload in an envelope and publishes it. Publishing is best-effort:
+// failures are logged, never returned, so they cannot affect the caller's operation.
+func (p *Publisher) Publish(ctx context.Context, eventType types.MeetingEventType, meetingID, userID int64, payload interface{}) {
+	if p == nil {
+		return
+	}
+
+	logger := log.With().
+		Str("event_type", string(eventType)).
+		Int64("meeting_id", meetingID).
+		Logger()
+
+	event, err := types.NewMeetingEventEnvelope(eventType, meetingID, userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to encode meeting event")
+		return
+	}
+
+	if err := p.client.PublishMeetingEvent(ctx, event); err != nil {
+		logger.Error().Err(err).Msg("Failed to publish meeting event")
+	}
+}
+
+// PublishStatusChanged publishes a meeting.status_changed event
+func (p *Publisher) PublishStatusChanged(ctx context.Context, meetingID, userID int64, status types.MeetingStatus, errorMsg *string, chunkCount int) {
+	p.Publish(ctx, types.EventStatusChanged, meetingID, userID, types.StatusChangedPayload{
+		Status:       status,
+		ErrorMessage: errorMsg,
+		ChunkCount:   chunkCount,
+	})
+}
+
+// =====================================================
+// CONSUMER
+// =====================================================
+
+// Handler handles a decoded meeting event
+type Handler func(event *types.MeetingEventEnvelope)
+
+// Consumer subscribes to meeting events and routes them to handlers by type.
+// Handlers run one at a time, in the order events arrive.
+type Consumer struct {
+	client *redis.Client
+
+	mu       sync.RWMutex
+	handlers map[types.MeetingEventType][]Handler
+	all      []Handler
+}
+
+// NewConsumer creates a new meeting event consumer. Register handlers, then call Run.
+func NewConsumer(client *redis.Client) *Consumer {
+	return &Consumer{
+		client:   client,
+		handlers: map[types.MeetingEventType][]Handler{},
+	}
+}
+
+// On registers a handler for one event type
+func (c *Consumer) On(eventType types.MeetingEventType, handler Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[eventType] = append(c.handlers[eventType], handler)
+}
+
+// OnAll registers a handler for every event type
+func (c *Consumer) OnAll(handler Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.all = append(c.all, handler)
+}
+
+// Run subscribes and dispatches events until ctx is cancelled
+func (c *Consumer) Run(ctx context.Context) error {
+	return c.client.SubscribeMeetingEvents(ctx, func(data []byte) {
+		event, err := Decode(data)
+		if err != nil {
+			log.Warn().Err(err).Msg("Skipping undecodable meeting event")
+			return
+		}
+		c.dispatch(event)
+	})
+}
+
+// dispatch runs the handlers registered for the event
+func (c *Consumer) dispatch(event *types.MeetingEventEnvelope) {
+	c.mu.RLock()
+	handlers := append(append([]Handler{}, c.handlers[event.Type]...), c.all...)
+	c.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// Decode parses and validates a meeting event envelope
+func Decode(data []byte) (*types.MeetingEventEnvelope, error) {
+	var event types.MeetingEventEnvelope
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode meeting event: %w", err)
+	}
+
+	if event.Type == "" {
+		return nil, fmt.Errorf("meeting event has no type")
+	}
+
+	if event.Version < 1 || event.Version > types.MeetingEventVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, event.Version)
+	}
+
+	return &event, nil
+}
@@ -0,0 +1,128 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/newar/insights/shared/types"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	errMsg := "bot crashed"
+	event, err := types.NewMeetingEventEnvelope(types.EventStatusChanged, 42, 7, types.StatusChangedPayload{
+		Status:       types.MeetingStatusFailed,
+		ErrorMessage: &errMsg,
+		ChunkCount:   12,
+	})
+	if err != nil {
+		t.Fatalf("NewMeetingEventEnvelope() error = %v", err)
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if decoded.Type != types.EventStatusChanged || decoded.Version != types.MeetingEventVersion ||
+		decoded.MeetingID != 42 || decoded.UserID != 7 || !decoded.OccurredAt.Equal(event.OccurredAt) {
+		t.Errorf("Decode() = %+v, want %+v", decoded, event)
+	}
+
+	var payload types.StatusChangedPayload
+	if err := decoded.DecodePayload(&payload); err != nil {
+		t.Fatalf("DecodePayload() error = %v", err)
+	}
+	if payload.Status != types.MeetingStatusFailed || payload.ErrorMessage == nil || *payload.ErrorMessage != errMsg || payload.ChunkCount != 12 {
+		t.Errorf("DecodePayload() = %+v", payload)
+	}
+}
+
+func TestNewMeetingEventEnvelopeUnencodablePayload(t *testing.T) {
+	if _, err := types.NewMeetingEventEnvelope(types.EventStatusChanged, 1, 1, make(chan int)); err == nil {
+		t.Error("NewMeetingEventEnvelope() error = nil, want an encoding error")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error // nil with wantAny means any error
+		wantAny bool
+	}{
+		{
+			name: "current version",
+			data: `{"type":"meeting.created","version":1,"meeting_id":1,"user_id":2,"payload":{}}`,
+		},
+		{
+			name: "unknown type is passed on",
+			data: `{"type":"meeting.something_new","version":1,"meeting_id":1,"user_id":2,"payload":{}}`,
+		},
+		{
+			name:    "newer version",
+			data:    `{"type":"meeting.created","version":2,"meeting_id":1,"user_id":2,"payload":{}}`,
+			wantErr: ErrUnsupportedVersion,
+		},
+		{
+			name:    "missing version",
+			data:    `{"type":"meeting.created","meeting_id":1,"user_id":2,"payload":{}}`,
+			wantErr: ErrUnsupportedVersion,
+		},
+		{
+			name:    "missing type",
+			data:    `{"version":1,"meeting_id":1,"user_id":2,"payload":{}}`,
+			wantAny: true,
+		},
+		{
+			name:    "not JSON",
+			data:    `meeting.created`,
+			wantAny: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode([]byte(tt.data))
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Decode() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantAny:
+				if err == nil {
+					t.Error("Decode() error = nil, want an error")
+				}
+			case err != nil:
+				t.Errorf("Decode() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestConsumerDispatch(t *testing.T) {
+	consumer := NewConsumer(nil)
+
+	var created, all []types.MeetingEventType
+	consumer.On(types.EventMeetingCreated, func(event *types.MeetingEventEnvelope) {
+		created = append(created, event.Type)
+	})
+	consumer.OnAll(func(event *types.MeetingEventEnvelope) {
+		all = append(all, event.Type)
+	})
+
+	consumer.dispatch(&types.MeetingEventEnvelope{Type: types.EventMeetingCreated})
+	consumer.dispatch(&types.MeetingEventEnvelope{Type: types.EventStatusChanged})
+
+	if len(created) != 1 || created[0] != types.EventMeetingCreated {
+		t.Errorf("typed handler got %v, want [%s]", created, types.EventMeetingCreated)
+	}
+	if len(all) != 2 {
+		t.Errorf("catch-all handler got %v, want both events", all)
+	}
+}
@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/metrics"
+	"github.com/newar/insights/shared/types"
+)
+
+// ErrAlreadyListening is returned when a container already has a registered handler
+var ErrAlreadyListening = errors.New("container already has a status listener")
+
+// StatusDispatcher receives status updates for all bots over a single
+// PSUBSCRIBE on bot:status:* and routes them to per-container listeners.
+// Each listener has a bounded queue: when it is full the dispatcher waits
+// briefly, then drops the update so one slow listener cannot stall the rest.
+type StatusDispatcher struct {
+	client  *Client
+	metrics *metrics.Collector
+
+	bufferSize  int
+	sendTimeout time.Duration
+
+	mu     sync.RWMutex
+	routes map[string]chan types.BotStatusUpdate
+
+	delivered atomic.Int64
+	dropped   atomic.Int64
+	unknown   atomic.Int64
+	invalid   atomic.Int64
+}
+
+// DispatcherStats holds status dispatcher counters
+type DispatcherStats struct {
+	Listeners int   `json:"listeners"`
+	Delivered int64 `json:"delivered"`
+	Dropped   int64 `json:"dropped"`
+	Unknown   int64 `json:"unknown"` // Updates for containers without a listener
+	Invalid   int64 `json:"invalid"` // Updates that could not be decoded
+}
+
+// NewStatusDispatcher creates a status dispatcher. Call Start to subscribe.
+func (c *Client) NewStatusDispatcher(collector *metrics.Collector) *StatusDispatcher {
+	return &StatusDispatcher{
+		client:      c,
+		metrics:     collector,
+		bufferSize:  constants.StatusDispatchBuffer,
+		sendTimeout: constants.StatusDispatchTimeout,
+		routes:      map[string]chan types.BotStatusUpdate{},
+	}
+}
+
+// Start subscribes to all bot status channels and dispatches updates in the
+// background until ctx is cancelled. It returns once the subscription is confirmed.
+func (d *StatusDispatcher) Start(ctx context.Context) error {
+	pattern := constants.BotStatusChannel + "*"
+
+	pubsub := d.client.rdb.PSubscribe(ctx, pattern)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("failed to subscribe to bot status updates: %w", err)
+	}
+
+	log.Info().Str("pattern", pattern).Msg("Status dispatcher subscribed")
+
+	go func() {
+		defer pubsub.Close()
+
+		ch := pubsub.Channel()
+		for {
+			select {
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				d.dispatch(strings.TrimPrefix(msg.Channel, constants.BotStatusChannel), msg.Payload)
+
+			case <-ctx.Done():
+				log.Info().Str("pattern", pattern).Msg("Status dispatcher stopped")
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Listen registers handler for a container's status updates and runs it for each
+// update until ctx is cancelled. Updates for one container are handled in order.
+func (d *StatusDispatcher) Listen(ctx context.Context, containerID string, handler func(types.BotStatusUpdate)) error {
+	route := make(chan types.BotStatusUpdate, d.bufferSize)
+
+	d.mu.Lock()
+	if _, exists := d.routes[containerID]; exists {
+		d.mu.Unlock()
+		return ErrAlreadyListening
+	}
+	d.routes[containerID] = route
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		if d.routes[containerID] == route {
+			delete(d.routes, containerID)
+		}
+		d.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case status := <-route:
+			handler(status)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Stats returns the current dispatcher counters
+func (d *StatusDispatcher) Stats() DispatcherStats {
+	d.mu.RLock()
+	listeners := len(d.routes)
+	d.mu.RUnlock()
+
+	return DispatcherStats{
+		Listeners: listeners,
+		Delivered: d.delivered.Load(),
+		Dropped:   d.dropped.Load(),
+		Unknown:   d.unknown.Load(),
+		Invalid:   d.invalid.Load(),
+	}
+}
+
+// dispatch decodes an update and queues it for the container's listener
+func (d *StatusDispatcher) dispatch(containerID, payload string) {
+	var status types.BotStatusUpdate
+	if err := json.Unmarshal([]byte(payload), &status); err != nil {
+		d.invalid.Add(1)
+		d.count("invalid")
+		log.Error().Err(err).Str("container_id", containerID).Msg("Failed to unmarshal bot status update")
+		return
+	}
+
+	d.mu.RLock()
+	route, exists := d.routes[containerID]
+	d.mu.RUnlock()
+
+	if !exists {
+		d.unknown.Add(1)
+		d.count("unknown_container")
+		log.Debug().
+			Str("container_id", containerID).
+			Str("status", string(status.Status)).
+			Msg("Status update for unknown container")
+		return
+	}
+
+	select {
+	case route <- status:
+		d.delivered.Add(1)
+		return
+	default:
+	}
+
+	// Queue is full - apply backpressure before giving up
+	timer := time.NewTimer(d.sendTimeout)
+	defer timer.Stop()
+
+	select {
+	case route <- status:
+		d.delivered.Add(1)
+	case <-timer.C:
+		d.dropped.Add(1)
+		d.count("dropped")
+		log.Warn().
+			Str("container_id", containerID).
+			Str("status", string(status.Status)).
+			Msg("Status listener queue full - dropping update")
+	}
+}
+
+// count increments the dispatcher metric for the given outcome
+func (d *StatusDispatcher) count(outcome string) {
+	if d.metrics != nil {
+		d.metrics.IncrementCounter("bot_status_dispatch_total", map[string]string{"outcome": outcome})
+	}
+}
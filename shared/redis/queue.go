@@ -0,0 +1,398 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+)
+
+// JobQueue is a durable job queue backed by a Redis Stream and a consumer group.
+// Jobs stay pending until a consumer acknowledges them, so they survive consumer
+// restarts and can be processed by several replicas of the same service.
+type JobQueue struct {
+	rdb              *redis.Client
+	stream           string
+	group            string
+	deadLetterStream string
+}
+
+// Job is a queued job delivered to a consumer
+type Job struct {
+	ID         string
+	Payload    []byte
+	Deliveries int64 // Number of times this job has been delivered, including the current one
+}
+
+// Decode unmarshals the job payload into v
+func (j Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// JobHandler processes a job. Returning nil acknowledges the job, returning an error
+// leaves it pending so it is retried with backoff, unless it is a PermanentJobError.
+type JobHandler func(ctx context.Context, job Job) error
+
+// PermanentJobError marks a job failure that retrying cannot fix.
+// The job is moved to the dead-letter stream immediately.
+type PermanentJobError struct {
+	Err error
+}
+
+func (e *PermanentJobError) Error() string { return e.Err.Error() }
+func (e *PermanentJobError) Unwrap() error { return e.Err }
+
+// ConsumerConfig holds job consumer configuration
+type ConsumerConfig struct {
+	Name          string        // Consumer name, unique per replica (e.g. hostname)
+	Concurrency   int           // Max jobs processed in parallel
+	MaxDeliveries int64         // Deliveries before a job is dead-lettered
+	JobTimeout    time.Duration // Per-job handler timeout
+	RetryBackoff  time.Duration // Delay before the first retry, doubled per delivery
+	MaxBackoff    time.Duration // Upper bound for retry delay
+
+	// Timeout optionally overrides JobTimeout per job (e.g. scaled to the job's size).
+	// Running jobs are kept claimed by a heartbeat, so it may exceed RetryBackoff.
+	Timeout func(job Job) time.Duration
+
+	// OnDeadLetter is called after a job has been moved to the dead-letter stream
+	OnDeadLetter func(job Job, err error)
+}
+
+// NewJobQueue creates a job queue on the given stream.
+// Dead-lettered jobs are written to "{stream}:dead".
+func (c *Client) NewJobQueue(stream, group string) *JobQueue {
+	return &JobQueue{
+		rdb:              c.rdb,
+		stream:           stream,
+		group:            group,
+		deadLetterStream: stream + ":dead",
+	}
+}
+
+// Enqueue adds a job to the queue, returning the job ID
+func (q *JobQueue) Enqueue(ctx context.Context, payload interface{}) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	id, err := q.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: q.stream,
+		Values: map[string]interface{}{"payload": data},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	log.Debug().Str("stream", q.stream).Str("job_id", id).Msg("Job enqueued")
+
+	return id, nil
+}
+
+// Consume processes jobs until ctx is cancelled. New jobs are read with XREADGROUP,
+// failed or abandoned jobs are reclaimed from the pending entries list once their
+// backoff has elapsed. Blocks until all in-flight jobs have finished.
+func (q *JobQueue) Consume(ctx context.Context, cfg ConsumerConfig, handler JobHandler) error {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+
+	if err := q.ensureGroup(ctx); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("stream", q.stream).
+		Str("group", q.group).
+		Str("consumer", cfg.Name).
+		Int("concurrency", cfg.Concurrency).
+		Msg("Job consumer started")
+
+	slots := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	run := func(job Job) {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			q.process(cfg, handler, job)
+		}()
+	}
+
+	lastReclaim := time.Time{}
+	for {
+		if ctx.Err() != nil {
+			log.Info().Str("stream", q.stream).Msg("Job consumer stopped")
+			return ctx.Err()
+		}
+
+		// Retry failed jobs and take over jobs abandoned by dead consumers. Only as
+		// many as there are free slots are claimed: a claimed job waiting for a slot
+		// has no heartbeat, so another consumer would claim and run it again.
+		free := cfg.Concurrency - len(slots)
+		if free > 0 && time.Since(lastReclaim) >= cfg.RetryBackoff/2 {
+			lastReclaim = time.Now()
+			jobs, err := q.reclaim(ctx, cfg, free)
+			if err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Str("stream", q.stream).Msg("Failed to reclaim pending jobs")
+			}
+			for _, job := range jobs {
+				run(job)
+			}
+			free -= len(jobs)
+		}
+
+		if free == 0 {
+			select {
+			case <-time.After(100 * time.Millisecond):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		streams, err := q.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    q.group,
+			Consumer: cfg.Name,
+			Streams:  []string{q.stream, ">"},
+			Count:    int64(free),
+			Block:    5 * time.Second,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			log.Error().Err(err).Str("stream", q.stream).Msg("Failed to read jobs")
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				run(newJob(msg, 1))
+			}
+		}
+	}
+}
+
+// ensureGroup creates the consumer group (and stream) if it does not exist yet
+func (q *JobQueue) ensureGroup(ctx context.Context) error {
+	err := q.rdb.XGroupCreateMkStream(ctx, q.stream, q.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+	return nil
+}
+
+// reclaim claims up to limit pending jobs whose retry backoff has elapsed
+func (q *JobQueue) reclaim(ctx context.Context, cfg ConsumerConfig, limit int) ([]Job, error) {
+	pending, err := q.rdb.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: q.stream,
+		Group:  q.group,
+		Idle:   cfg.RetryBackoff,
+		Start:  "-",
+		End:    "+",
+		Count:  int64(cfg.Concurrency) * 10,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending jobs: %w", err)
+	}
+
+	deliveries := map[string]int64{}
+	ids := []string{}
+	for _, p := range pending {
+		if len(ids) == limit {
+			break
+		}
+		if p.Idle < q.backoff(cfg, p.RetryCount) {
+			continue
+		}
+		deliveries[p.ID] = p.RetryCount + 1
+		ids = append(ids, p.ID)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	msgs, err := q.rdb.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   q.stream,
+		Group:    q.group,
+		Consumer: cfg.Name,
+		MinIdle:  cfg.RetryBackoff,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending jobs: %w", err)
+	}
+
+	jobs := make([]Job, 0, len(msgs))
+	for _, msg := range msgs {
+		jobs = append(jobs, newJob(msg, deliveries[msg.ID]))
+	}
+
+	log.Info().Str("stream", q.stream).Int("count", len(jobs)).Msg("Reclaimed pending jobs")
+
+	return jobs, nil
+}
+
+// backoff returns the delay before a job delivered n times may be retried
+func (q *JobQueue) backoff(cfg ConsumerConfig, deliveries int64) time.Duration {
+	delay := cfg.RetryBackoff
+	for i := int64(1); i < deliveries; i++ {
+		delay *= 2
+		if delay >= cfg.MaxBackoff {
+			return cfg.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// process runs the handler for a single job and acks, retries or dead-letters it
+func (q *JobQueue) process(cfg ConsumerConfig, handler JobHandler, job Job) {
+	if job.Deliveries > cfg.MaxDeliveries {
+		q.deadLetter(cfg, job, fmt.Errorf("exceeded %d deliveries", cfg.MaxDeliveries))
+		return
+	}
+
+	timeout := cfg.JobTimeout
+	if cfg.Timeout != nil {
+		timeout = cfg.Timeout(job)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	stopHeartbeat := q.heartbeat(cfg, job)
+	err := handler(ctx, job)
+	stopHeartbeat()
+	if err == nil {
+		q.ack(job)
+		return
+	}
+
+	var permErr *PermanentJobError
+	if errors.As(err, &permErr) || job.Deliveries >= cfg.MaxDeliveries {
+		q.deadLetter(cfg, job, err)
+		return
+	}
+
+	log.Warn().
+		Err(err).
+		Str("stream", q.stream).
+		Str("job_id", job.ID).
+		Int64("deliveries", job.Deliveries).
+		Dur("retry_in", q.backoff(cfg, job.Deliveries)).
+		Msg("Job failed - will retry")
+}
+
+// heartbeat re-claims a job for this consumer while its handler runs, resetting its
+// idle time so it is not reclaimed as abandoned. Returns a function that stops it.
+func (q *JobQueue) heartbeat(cfg ConsumerConfig, job Job) func() {
+	interval := cfg.RetryBackoff / 2
+	if interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), constants.RedisPublishTimeout)
+				// JUSTID leaves the delivery counter untouched
+				err := q.rdb.XClaimJustID(ctx, &redis.XClaimArgs{
+					Stream:   q.stream,
+					Group:    q.group,
+					Consumer: cfg.Name,
+					Messages: []string{job.ID},
+				}).Err()
+				cancel()
+				if err != nil {
+					log.Warn().Err(err).Str("stream", q.stream).Str("job_id", job.ID).Msg("Failed to extend job claim")
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// deadLetter moves a job to the dead-letter stream and acknowledges it
+func (q *JobQueue) deadLetter(cfg ConsumerConfig, job Job, jobErr error) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RedisPublishTimeout)
+	defer cancel()
+
+	log.Error().
+		Err(jobErr).
+		Str("stream", q.stream).
+		Str("job_id", job.ID).
+		Int64("deliveries", job.Deliveries).
+		Msg("Job moved to dead-letter stream")
+
+	err := q.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: q.deadLetterStream,
+		Values: map[string]interface{}{
+			"payload":    job.Payload,
+			"job_id":     job.ID,
+			"deliveries": job.Deliveries,
+			"error":      jobErr.Error(),
+			"failed_at":  time.Now().UTC().Format(time.RFC3339),
+		},
+	}).Err()
+	if err != nil {
+		// Leave the job pending so it is not lost
+		log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to write dead-letter entry")
+		return
+	}
+
+	q.ack(job)
+
+	if cfg.OnDeadLetter != nil {
+		cfg.OnDeadLetter(job, jobErr)
+	}
+}
+
+// ack acknowledges a job and removes it from the stream
+func (q *JobQueue) ack(job Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RedisPublishTimeout)
+	defer cancel()
+
+	pipe := q.rdb.TxPipeline()
+	pipe.XAck(ctx, q.stream, q.group, job.ID)
+	pipe.XDel(ctx, q.stream, job.ID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Error().Err(err).Str("stream", q.stream).Str("job_id", job.ID).Msg("Failed to acknowledge job")
+	}
+}
+
+// newJob builds a Job from a stream message
+func newJob(msg redis.XMessage, deliveries int64) Job {
+	payload, _ := msg.Values["payload"].(string)
+	return Job{
+		ID:         msg.ID,
+		Payload:    []byte(payload),
+		Deliveries: deliveries,
+	}
+}
@@ -0,0 +1,241 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeStream answers the stream commands of a JobQueue from memory, through a
+// client hook, so queue tests run without a Redis server
+type fakeStream struct {
+	mu       sync.Mutex
+	pending  []redis.XPendingExt
+	messages map[string]redis.XMessage
+	commands [][]interface{}
+}
+
+func (f *fakeStream) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (f *fakeStream) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		f.process(cmd)
+		return nil
+	}
+}
+
+func (f *fakeStream) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			f.process(cmd)
+		}
+		return nil
+	}
+}
+
+func (f *fakeStream) process(cmd redis.Cmder) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.commands = append(f.commands, cmd.Args())
+
+	switch cmd := cmd.(type) {
+	case *redis.XPendingExtCmd:
+		cmd.SetVal(f.pending)
+	case *redis.XMessageSliceCmd:
+		// XCLAIM returns the claimed messages, from the 6th argument on
+		msgs := []redis.XMessage{}
+		for _, arg := range cmd.Args()[5:] {
+			if msg, ok := f.messages[arg.(string)]; ok {
+				msgs = append(msgs, msg)
+			}
+		}
+		cmd.SetVal(msgs)
+	case *redis.StringCmd:
+		cmd.SetVal("1-0")
+	}
+}
+
+// calls returns the commands named name that were sent, in order
+func (f *fakeStream) calls(name string) [][]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	calls := [][]interface{}{}
+	for _, args := range f.commands {
+		if strings.EqualFold(args[0].(string), name) {
+			calls = append(calls, args)
+		}
+	}
+	return calls
+}
+
+func newTestJobQueue(f *fakeStream) *JobQueue {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	rdb.AddHook(f)
+	return (&Client{rdb: rdb}).NewJobQueue("jobs", "workers")
+}
+
+func testConsumerConfig() ConsumerConfig {
+	return ConsumerConfig{
+		Name:          "consumer-1",
+		Concurrency:   2,
+		MaxDeliveries: 3,
+		JobTimeout:    time.Second,
+		RetryBackoff:  time.Second,
+		MaxBackoff:    5 * time.Second,
+	}
+}
+
+func TestJobQueueBackoff(t *testing.T) {
+	q := newTestJobQueue(&fakeStream{})
+	cfg := testConsumerConfig()
+
+	tests := []struct {
+		deliveries int64
+		want       time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second}, // Capped at MaxBackoff
+		{50, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := q.backoff(cfg, tt.deliveries); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.deliveries, got, tt.want)
+		}
+	}
+}
+
+func TestJobQueueReclaim(t *testing.T) {
+	f := &fakeStream{
+		pending: []redis.XPendingExt{
+			{ID: "1-0", Idle: 1500 * time.Millisecond, RetryCount: 1}, // Backoff 1s elapsed
+			{ID: "2-0", Idle: 1500 * time.Millisecond, RetryCount: 2}, // Backoff 2s not elapsed yet
+			{ID: "3-0", Idle: 10 * time.Second, RetryCount: 3},
+			{ID: "4-0", Idle: 10 * time.Second, RetryCount: 1}, // Over the limit of free slots
+		},
+		messages: map[string]redis.XMessage{
+			"1-0": {ID: "1-0", Values: map[string]interface{}{"payload": `{"n":1}`}},
+			"3-0": {ID: "3-0", Values: map[string]interface{}{"payload": `{"n":3}`}},
+			"4-0": {ID: "4-0", Values: map[string]interface{}{"payload": `{"n":4}`}},
+		},
+	}
+	q := newTestJobQueue(f)
+
+	jobs, err := q.reclaim(context.Background(), testConsumerConfig(), 2)
+	if err != nil {
+		t.Fatalf("reclaim() error = %v", err)
+	}
+
+	if len(jobs) != 2 || jobs[0].ID != "1-0" || jobs[1].ID != "3-0" {
+		t.Fatalf("reclaim() = %+v, want jobs 1-0 and 3-0", jobs)
+	}
+	if jobs[0].Deliveries != 2 || jobs[1].Deliveries != 4 {
+		t.Errorf("deliveries = %d, %d; want 2, 4", jobs[0].Deliveries, jobs[1].Deliveries)
+	}
+	if string(jobs[1].Payload) != `{"n":3}` {
+		t.Errorf("payload = %s, want {\"n\":3}", jobs[1].Payload)
+	}
+
+	claims := f.calls("xclaim")
+	if len(claims) != 1 {
+		t.Fatalf("XCLAIM sent %d times, want 1", len(claims))
+	}
+	if ids := claims[0][5:]; len(ids) != 2 || ids[0] != "1-0" || ids[1] != "3-0" {
+		t.Errorf("XCLAIM ids = %v, want [1-0 3-0]", ids)
+	}
+}
+
+func TestJobQueueProcess(t *testing.T) {
+	errTransient := errors.New("bot-manager busy")
+
+	tests := []struct {
+		name           string
+		deliveries     int64
+		handlerErr     error
+		wantRun        bool
+		wantDeadLetter bool
+		wantAck        bool
+	}{
+		{"success", 1, nil, true, false, true},
+		{"failure is retried", 1, errTransient, true, false, false},
+		{"failure on last delivery", 3, errTransient, true, true, true},
+		{"permanent failure", 1, &PermanentJobError{Err: errTransient}, true, true, true},
+		{"over max deliveries", 4, nil, false, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeStream{}
+			q := newTestJobQueue(f)
+
+			var deadLettered []Job
+			cfg := testConsumerConfig()
+			cfg.OnDeadLetter = func(job Job, err error) { deadLettered = append(deadLettered, job) }
+
+			ran := false
+			job := Job{ID: "7-0", Payload: []byte(`{"meeting_id":7}`), Deliveries: tt.deliveries}
+			q.process(cfg, func(ctx context.Context, job Job) error {
+				ran = true
+				return tt.handlerErr
+			}, job)
+
+			if ran != tt.wantRun {
+				t.Errorf("handler ran = %v, want %v", ran, tt.wantRun)
+			}
+
+			// Dead-lettered jobs are copied to the dead-letter stream before the ack
+			adds := f.calls("xadd")
+			if (len(adds) == 1) != tt.wantDeadLetter || (len(deadLettered) == 1) != tt.wantDeadLetter {
+				t.Errorf("dead-letter writes = %d, callbacks = %d, want dead-lettered %v", len(adds), len(deadLettered), tt.wantDeadLetter)
+			}
+			if tt.wantDeadLetter && len(adds) == 1 && adds[0][1] != "jobs:dead" {
+				t.Errorf("dead-letter stream = %v, want jobs:dead", adds[0][1])
+			}
+
+			acks := f.calls("xack")
+			if (len(acks) == 1) != tt.wantAck {
+				t.Errorf("acks = %d, want acked %v", len(acks), tt.wantAck)
+			}
+			if tt.wantAck && len(f.calls("xdel")) != 1 {
+				t.Error("acked job was not removed from the stream")
+			}
+		})
+	}
+}
+
+func TestJobQueueHeartbeat(t *testing.T) {
+	f := &fakeStream{}
+	q := newTestJobQueue(f)
+
+	cfg := testConsumerConfig()
+	cfg.RetryBackoff = 20 * time.Millisecond // Heartbeat every 10ms
+
+	q.process(cfg, func(ctx context.Context, job Job) error {
+		time.Sleep(55 * time.Millisecond)
+		return nil
+	}, Job{ID: "7-0", Deliveries: 1})
+
+	claims := f.calls("xclaim")
+	if len(claims) < 2 {
+		t.Fatalf("heartbeat claimed the job %d times while it ran, want at least 2", len(claims))
+	}
+	last := claims[len(claims)-1]
+	if last[5] != "7-0" || !strings.EqualFold(last[len(last)-1].(string), "justid") {
+		t.Errorf("heartbeat sent %v, want XCLAIM of 7-0 with JUSTID", last)
+	}
+
+	// The heartbeat stops with the handler
+	time.Sleep(30 * time.Millisecond)
+	if after := f.calls("xclaim"); len(after) != len(claims) {
+		t.Errorf("heartbeat claimed the job %d more times after it finished", len(after)-len(claims))
+	}
+}
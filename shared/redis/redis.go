@@ -156,6 +156,47 @@ func (c *Client) PublishBotCommand(ctx context.Context, containerID string, comm
 	return nil
 }
 
+// PublishBotCommandAndWait sends a command to a bot and waits until the bot reports
+// one of the given statuses. The status subscription is established before the
+// command is published so a fast acknowledgement cannot be missed.
+func (c *Client) PublishBotCommandAndWait(ctx context.Context, containerID string, command types.BotCommand, statuses ...types.MeetingStatus) (*types.BotStatusUpdate, error) {
+	channel := constants.BotStatusChannel + containerID
+
+	pubsub := c.rdb.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	// Wait for confirmation
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return nil, fmt.Errorf("failed to subscribe to bot status: %w", err)
+	}
+
+	if err := c.PublishBotCommand(ctx, containerID, command); err != nil {
+		return nil, err
+	}
+
+	// Listen for matching status
+	ch := pubsub.Channel()
+	for {
+		select {
+		case msg := <-ch:
+			var status types.BotStatusUpdate
+			if err := json.Unmarshal([]byte(msg.Payload), &status); err != nil {
+				log.Error().Err(err).Msg("Failed to unmarshal bot status update")
+				continue
+			}
+
+			for _, s := range statuses {
+				if status.Status == s {
+					return &status, nil
+				}
+			}
+
+		case <-ctx.Done():
+			return nil, fmt.Errorf("bot %s did not acknowledge %q command: %w", containerID, command.Command, ctx.Err())
+		}
+	}
+}
+
 // SubscribeBotCommands subscribes to bot commands for a specific container
 func (c *Client) SubscribeBotCommands(ctx context.Context, containerID string, handler func(types.BotCommand)) error {
 	channel := constants.BotCommandChannel + containerID
@@ -0,0 +1,83 @@
+package safehttp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/newar/insights/shared/utils"
+)
+
+// ErrBlockedAddress is returned when a request would connect to an address on a
+// private network, e.g. another service next to us or a cloud metadata endpoint
+var ErrBlockedAddress = errors.New("destination address is not allowed")
+
+// blockedPrefixes are networks user-supplied URLs may not reach, besides the
+// loopback, private, link-local and unspecified ranges netip classifies itself
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),   // Reserved
+}
+
+// NewClient returns an HTTP client for URLs supplied by users (e.g. webhooks).
+// The address is checked when each connection is dialed, after DNS resolution
+// and for every redirect, so hostnames resolving to private addresses are
+// rejected too. ALLOW_PRIVATE_NETWORK_TARGETS=true disables the check for
+// local development.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if !utils.GetEnvOrDefaultBool("ALLOW_PRIVATE_NETWORK_TARGETS", false) {
+		dialer.Control = checkAddress
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// No proxy: it would dial on our behalf, past the address check
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+	}
+}
+
+// IsBlocked reports whether user-supplied URLs may not connect to an address
+func IsBlocked(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAddress is a net.Dialer Control function rejecting blocked addresses.
+// It runs with the resolved address, right before the connection is made.
+func checkAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	if IsBlocked(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, addrPort.Addr())
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package safehttp
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestIsBlocked(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"127.0.0.1", true},        // Loopback
+		{"10.1.2.3", true},         // Private
+		{"172.16.0.1", true},       // Private
+		{"172.20.0.5", true},       // Private (compose network)
+		{"192.168.1.1", true},      // Private
+		{"169.254.169.254", true},  // Link-local (cloud metadata)
+		{"0.0.0.0", true},          // Unspecified
+		{"100.64.0.1", true},       // Carrier-grade NAT
+		{"192.0.0.8", true},        // IETF protocol assignments
+		{"198.18.0.1", true},       // Benchmarking
+		{"240.0.0.1", true},        // Reserved
+		{"224.0.0.1", true},        // Multicast
+		{"::1", true},              // IPv6 loopback
+		{"::", true},               // IPv6 unspecified
+		{"fd00::1", true},          // IPv6 unique local
+		{"fe80::1", true},          // IPv6 link-local
+		{"ff02::1", true},          // IPv6 multicast
+		{"::ffff:127.0.0.1", true}, // IPv4-mapped loopback
+		{"::ffff:10.0.0.1", true},  // IPv4-mapped private
+		{"8.8.8.8", false},
+		{"1.1.1.1", false},
+		{"100.63.255.255", false}, // Just below carrier-grade NAT
+		{"172.32.0.1", false},     // Just above 172.16.0.0/12
+		{"2606:4700:4700::1111", false},
+		{"::ffff:8.8.8.8", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := IsBlocked(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("IsBlocked(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		blocked bool
+	}{
+		{"127.0.0.1:80", true},
+		{"[::1]:443", true},
+		{"not-an-address", true},
+		{"8.8.8.8:443", false},
+		{"[2606:4700:4700::1111]:443", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := checkAddress("tcp", tt.address, nil)
+			if blocked := errors.Is(err, ErrBlockedAddress); blocked != tt.blocked {
+				t.Errorf("checkAddress(%s) error = %v, want blocked %v", tt.address, err, tt.blocked)
+			}
+		})
+	}
+}
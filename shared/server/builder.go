@@ -93,8 +93,9 @@ func NewServerBuilder(serviceName string) (*ServerBuilder, error) {
 	if cfg.Features.EnableCORS {
 		app.Use(cors.New(cors.Config{
 			AllowOrigins:     cfg.Server.CORSAllowedOrigins,
-			AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS,PATCH",
-			AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-API-Key,X-Admin-API-Key",
+			AllowMethods:     "GET,HEAD,POST,PUT,DELETE,OPTIONS,PATCH",
+			AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-API-Key,X-Admin-API-Key,Range,If-Range,If-None-Match,If-Modified-Since",
+			ExposeHeaders:    "Content-Length,Content-Range,Content-Disposition,Accept-Ranges,ETag,Last-Modified", // Ranged downloads
 			AllowCredentials: true,
 			MaxAge:           86400,
 		}))
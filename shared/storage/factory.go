@@ -3,14 +3,39 @@ package storage
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/utils"
 )
 
 // NewStorage creates a new storage instance based on configuration
 func NewStorage(provider string) (Storage, error) {
-	log.Info().Str("provider", provider).Msg("Creating storage instance")
+	cfg, err := ConfigFromEnv(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(cfg)
+}
+
+// New creates a storage instance from a configuration
+func New(cfg Config) (Storage, error) {
+	log.Info().Str("provider", cfg.Provider).Msg("Creating storage instance")
 
+	switch cfg.Provider {
+	case "supabase", "s3":
+		return NewSupabaseStorage(cfg)
+	case "local":
+		return NewLocalStorage(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage provider: %s", cfg.Provider)
+	}
+}
+
+// ConfigFromEnv loads the configuration of a storage provider from environment variables
+func ConfigFromEnv(provider string) (Config, error) {
 	switch provider {
 	case "supabase":
 		cfg := Config{
@@ -21,32 +46,98 @@ func NewStorage(provider string) (Storage, error) {
 			AccessKey:   os.Getenv("SUPABASE_STORAGE_ACCESS_KEY"),
 			SecretKey:   os.Getenv("SUPABASE_STORAGE_SECRET_KEY"),
 			Endpoint:    os.Getenv("SUPABASE_STORAGE_ENDPOINT"),
+			PublicRead:  utils.GetEnvOrDefaultBool("STORAGE_PUBLIC_READ", false),
+			Multipart:   MultipartConfigFromEnv(),
 		}
 
 		// Validate configuration
 		if cfg.SupabaseURL == "" {
-			return nil, fmt.Errorf("SUPABASE_URL is required")
+			return cfg, fmt.Errorf("SUPABASE_URL is required")
 		}
 		if cfg.Bucket == "" {
-			return nil, fmt.Errorf("SUPABASE_STORAGE_BUCKET is required")
+			return cfg, fmt.Errorf("SUPABASE_STORAGE_BUCKET is required")
 		}
 		if cfg.Endpoint == "" {
-			return nil, fmt.Errorf("SUPABASE_STORAGE_ENDPOINT is required")
+			return cfg, fmt.Errorf("SUPABASE_STORAGE_ENDPOINT is required")
 		}
 		if cfg.AccessKey == "" {
-			return nil, fmt.Errorf("SUPABASE_STORAGE_ACCESS_KEY is required")
+			return cfg, fmt.Errorf("SUPABASE_STORAGE_ACCESS_KEY is required")
 		}
 		if cfg.SecretKey == "" {
-			return nil, fmt.Errorf("SUPABASE_STORAGE_SECRET_KEY is required")
+			return cfg, fmt.Errorf("SUPABASE_STORAGE_SECRET_KEY is required")
 		}
 
-		return NewSupabaseStorage(cfg)
+		// Supabase Storage public URL format:
+		// https://<project-ref>.supabase.co/storage/v1/object/public/<bucket>/<path>
+		cfg.PublicURL = fmt.Sprintf("%s/storage/v1/object/public/%s", cfg.SupabaseURL, cfg.Bucket)
+
+		return cfg, nil
+
+	case "s3":
+		// Any S3-compatible service, e.g. MinIO for local development
+		cfg := Config{
+			Provider:     "s3",
+			Bucket:       os.Getenv("S3_BUCKET"),
+			Region:       utils.GetEnvOrDefault("S3_REGION", "us-east-1"),
+			AccessKey:    os.Getenv("S3_ACCESS_KEY"),
+			SecretKey:    os.Getenv("S3_SECRET_KEY"),
+			Endpoint:     os.Getenv("S3_ENDPOINT"),
+			PublicURL:    os.Getenv("S3_PUBLIC_URL"),
+			PublicRead:   utils.GetEnvOrDefaultBool("STORAGE_PUBLIC_READ", false),
+			SignEndpoint: os.Getenv("S3_SIGN_ENDPOINT"),
+			Multipart:    MultipartConfigFromEnv(),
+		}
+
+		if cfg.Endpoint == "" {
+			return cfg, fmt.Errorf("S3_ENDPOINT is required")
+		}
+		if cfg.Bucket == "" {
+			return cfg, fmt.Errorf("S3_BUCKET is required")
+		}
+		if cfg.AccessKey == "" {
+			return cfg, fmt.Errorf("S3_ACCESS_KEY is required")
+		}
+		if cfg.SecretKey == "" {
+			return cfg, fmt.Errorf("S3_SECRET_KEY is required")
+		}
+
+		// Path-style URL of the bucket
+		if cfg.PublicURL == "" {
+			cfg.PublicURL = strings.TrimSuffix(cfg.Endpoint, "/") + "/" + cfg.Bucket
+		}
+
+		return cfg, nil
 
 	case "local":
-		// Local storage implementation (fallback)
-		return nil, fmt.Errorf("local storage not implemented yet")
+		return Config{
+			Provider:      "local",
+			LocalBasePath: utils.GetEnvOrDefault("STORAGE_PATH", "./storage/recordings"),
+			LocalBaseURL:  os.Getenv("STORAGE_PUBLIC_URL"),
+			SigningKey:    os.Getenv("STORAGE_SIGNING_KEY"),
+		}, nil
 
 	default:
-		return nil, fmt.Errorf("unknown storage provider: %s", provider)
+		return Config{}, fmt.Errorf("unknown storage provider: %s", provider)
+	}
+}
+
+// BotEnv returns the environment variables recording bots need to upload their
+// chunks to this storage, under the layout's chunk directory
+func (c Config) BotEnv(layout Layout) []string {
+	env := []string{
+		fmt.Sprintf("STORAGE_TYPE=%s", c.Provider),
+		fmt.Sprintf("STORAGE_CHUNKS_DIR=%s", layout.ChunksDir),
+	}
+
+	if c.Provider == "local" {
+		return append(env, fmt.Sprintf("STORAGE_PATH=%s", c.LocalBasePath))
 	}
+
+	return append(env,
+		fmt.Sprintf("STORAGE_ENDPOINT=%s", c.Endpoint),
+		fmt.Sprintf("STORAGE_BUCKET=%s", c.Bucket),
+		fmt.Sprintf("STORAGE_REGION=%s", c.Region),
+		fmt.Sprintf("STORAGE_ACCESS_KEY=%s", c.AccessKey),
+		fmt.Sprintf("STORAGE_SECRET_KEY=%s", c.SecretKey),
+	)
 }
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/utils"
+)
+
+// Layout is the directory layout of recordings within a storage backend.
+// Paths are storage keys (slash-separated, relative to the storage root).
+type Layout struct {
+	RecordingsDir string // Finalized recordings and their renditions
+	ChunksDir     string // Chunks uploaded by bots, one folder per meeting
+}
+
+// DefaultLayout returns the default layout: final/ and temp/meeting_<id>/
+func DefaultLayout() Layout {
+	return Layout{
+		RecordingsDir: constants.FinalFolderPrefix,
+		ChunksDir:     constants.TempFolderPrefix,
+	}
+}
+
+// LayoutFromEnv loads the layout from STORAGE_RECORDINGS_DIR and STORAGE_CHUNKS_DIR,
+// falling back to the defaults
+func LayoutFromEnv() Layout {
+	def := DefaultLayout()
+
+	return Layout{
+		RecordingsDir: utils.GetEnvOrDefault("STORAGE_RECORDINGS_DIR", def.RecordingsDir),
+		ChunksDir:     utils.GetEnvOrDefault("STORAGE_CHUNKS_DIR", def.ChunksDir),
+	}
+}
+
+// RecordingPath returns the storage path of a finalized recording file
+func (l Layout) RecordingPath(fileName string) string {
+	return path.Join(l.RecordingsDir, fileName)
+}
+
+// ChunkDir returns the storage path of the folder a meeting's chunks are uploaded to
+func (l Layout) ChunkDir(meetingID int64) string {
+	return path.Join(l.ChunksDir, fmt.Sprintf("meeting_%d", meetingID))
+}
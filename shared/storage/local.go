@@ -0,0 +1,340 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrInvalidPath is returned for storage paths that would resolve outside the storage root
+	ErrInvalidPath = errors.New("invalid storage path")
+
+	// ErrSigningUnavailable is returned by SignedURL when local storage has no base
+	// URL or signing key to build signed URLs with
+	ErrSigningUnavailable = errors.New("signed URLs are not configured")
+
+	// ErrInvalidSignature is returned for signed URLs that were not issued by this storage
+	ErrInvalidSignature = errors.New("invalid URL signature")
+
+	// ErrURLExpired is returned for signed URLs past their expiry
+	ErrURLExpired = errors.New("signed URL has expired")
+)
+
+// LocalStorage implements Storage interface on the local filesystem
+type LocalStorage struct {
+	basePath   string
+	baseURL    string
+	signingKey []byte
+}
+
+// NewLocalStorage creates a new local filesystem storage rooted at cfg.LocalBasePath
+func NewLocalStorage(cfg Config) (*LocalStorage, error) {
+	basePath, err := filepath.Abs(cfg.LocalBasePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage path: %w", err)
+	}
+
+	if err := os.MkdirAll(basePath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	log.Info().
+		Str("base_path", basePath).
+		Str("base_url", cfg.LocalBaseURL).
+		Bool("signed_urls", cfg.LocalBaseURL != "" && cfg.SigningKey != "").
+		Msg("Local Storage initialized successfully")
+
+	return &LocalStorage{
+		basePath:   basePath,
+		baseURL:    strings.TrimSuffix(cfg.LocalBaseURL, "/"),
+		signingKey: []byte(cfg.SigningKey),
+	}, nil
+}
+
+// Upload writes a file to local storage. The file is written to a temporary file
+// in the target directory and renamed into place, so readers never see a partial file.
+func (s *LocalStorage) Upload(ctx context.Context, path string, reader io.Reader, contentType string) (string, error) {
+	fullPath, err := s.resolve(path)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Dir(fullPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(fullPath)+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	size, err := io.Copy(tmp, &contextReader{ctx: ctx, reader: reader})
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, fullPath)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	log.Info().
+		Str("path", path).
+		Str("content_type", contentType).
+		Int64("size_bytes", size).
+		Msg("File uploaded successfully to local storage")
+
+	return s.GetPublicURL(path), nil
+}
+
+// Download opens a file from local storage
+func (s *LocalStorage) Download(ctx context.Context, path string) (io.ReadCloser, error) {
+	fullPath, err := s.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return file, nil
+}
+
+// DownloadRange opens a file from local storage, reading length bytes from offset
+func (s *LocalStorage) DownloadRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	fullPath, err := s.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to seek file: %w", err)
+	}
+
+	return &limitedReadCloser{Reader: io.LimitReader(file, length), Closer: file}, nil
+}
+
+// Stat returns the size and version of a file in local storage. The ETag is
+// derived from the size and modification time, which change on every upload.
+func (s *LocalStorage) Stat(ctx context.Context, path string) (ObjectInfo, error) {
+	fullPath, err := s.resolve(path)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	info, err := os.Stat(fullPath)
+	if os.IsNotExist(err) || (err == nil && info.IsDir()) {
+		return ObjectInfo{}, fmt.Errorf("%w: %s", ErrNotFound, path)
+	}
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	return ObjectInfo{
+		Size:         info.Size(),
+		ETag:         fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+// Delete deletes a file from local storage, along with directories it leaves empty.
+// Deleting a missing file is not an error.
+func (s *LocalStorage) Delete(ctx context.Context, path string) error {
+	fullPath, err := s.resolve(path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	// Object stores have no directories - don't leave empty ones behind either
+	for dir := filepath.Dir(fullPath); dir != s.basePath; dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+
+	log.Info().Str("path", path).Msg("File deleted successfully")
+	return nil
+}
+
+// GetPublicURL returns the URL a file is served under, or its filesystem path if
+// no base URL is configured
+func (s *LocalStorage) GetPublicURL(path string) string {
+	if s.baseURL == "" {
+		fullPath, err := s.resolve(path)
+		if err != nil {
+			return ""
+		}
+		return fullPath
+	}
+	return s.baseURL + "/" + strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "/")
+}
+
+// SignedURL returns the URL a file is served under, with an expiry and an HMAC of
+// the path and expiry that VerifySignedURL checks when the URL is requested
+func (s *LocalStorage) SignedURL(ctx context.Context, path string, ttl time.Duration) (string, error) {
+	if s.baseURL == "" || len(s.signingKey) == 0 {
+		return "", ErrSigningUnavailable
+	}
+	if _, err := s.resolve(path); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	query := url.Values{
+		"expires":   {expires},
+		"signature": {s.sign(path, expires)},
+	}
+
+	return s.baseURL + "/" + (&url.URL{Path: path}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// VerifySignedURL checks that a signed URL for path was issued by SignedURL and
+// has not expired
+func (s *LocalStorage) VerifySignedURL(path, expires, signature string) error {
+	if len(s.signingKey) == 0 {
+		return ErrSigningUnavailable
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(path, expires))) {
+		return ErrInvalidSignature
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > expiresAt {
+		return ErrURLExpired
+	}
+
+	return nil
+}
+
+// sign returns the hex HMAC-SHA256 of a path and expiry
+func (s *LocalStorage) sign(path, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(path + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Exists checks if a file exists in local storage
+func (s *LocalStorage) Exists(ctx context.Context, path string) (bool, error) {
+	fullPath, err := s.resolve(path)
+	if err != nil {
+		return false, err
+	}
+
+	info, err := os.Stat(fullPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// List returns the paths of the files below a directory in local storage.
+// Files still being written by Upload are left out.
+func (s *LocalStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	root, err := s.resolve(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	err = filepath.WalkDir(root, func(fullPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".tmp") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.basePath, fullPath)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// LocalPath returns the filesystem path of a file in local storage
+func (s *LocalStorage) LocalPath(path string) (string, error) {
+	return s.resolve(path)
+}
+
+// resolve maps a storage path onto the filesystem, rejecting absolute paths and
+// paths that climb out of the storage root
+func (s *LocalStorage) resolve(path string) (string, error) {
+	rel := filepath.FromSlash(path)
+	if path == "" || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPath, path)
+	}
+	return filepath.Join(s.basePath, rel), nil
+}
+
+// contextReader stops a copy once its context is cancelled
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
+
+// limitedReadCloser reads part of a file and closes the whole file
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLocalStorage(t *testing.T, baseURL, signingKey string) *LocalStorage {
+	t.Helper()
+	store, err := NewLocalStorage(Config{LocalBasePath: t.TempDir(), LocalBaseURL: baseURL, SigningKey: signingKey})
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+	return store
+}
+
+func TestLocalStorageRejectsPathTraversal(t *testing.T) {
+	store := newTestLocalStorage(t, "", "")
+	ctx := context.Background()
+
+	// A file next to the storage root that no path may reach
+	outside := filepath.Join(filepath.Dir(store.basePath), "outside.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(outside)
+
+	paths := []string{
+		"",
+		"../outside.txt",
+		"final/../../outside.txt",
+		"/etc/passwd",
+		"..",
+		"final/../..",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			if _, err := store.Upload(ctx, path, strings.NewReader("x"), "text/plain"); !errors.Is(err, ErrInvalidPath) {
+				t.Errorf("Upload() error = %v, want ErrInvalidPath", err)
+			}
+			if _, err := store.Download(ctx, path); !errors.Is(err, ErrInvalidPath) {
+				t.Errorf("Download() error = %v, want ErrInvalidPath", err)
+			}
+			if _, err := store.DownloadRange(ctx, path, 0, 1); !errors.Is(err, ErrInvalidPath) {
+				t.Errorf("DownloadRange() error = %v, want ErrInvalidPath", err)
+			}
+			if _, err := store.Stat(ctx, path); !errors.Is(err, ErrInvalidPath) {
+				t.Errorf("Stat() error = %v, want ErrInvalidPath", err)
+			}
+			if err := store.Delete(ctx, path); !errors.Is(err, ErrInvalidPath) {
+				t.Errorf("Delete() error = %v, want ErrInvalidPath", err)
+			}
+			if _, err := store.List(ctx, path); !errors.Is(err, ErrInvalidPath) {
+				t.Errorf("List() error = %v, want ErrInvalidPath", err)
+			}
+		})
+	}
+
+	if data, err := os.ReadFile(outside); err != nil || string(data) != "secret" {
+		t.Errorf("file outside the storage root was modified: %q, %v", data, err)
+	}
+}
+
+func TestLocalStorageRoundTrip(t *testing.T) {
+	store := newTestLocalStorage(t, "", "")
+	ctx := context.Background()
+
+	if _, err := store.Upload(ctx, "final/1/recording.webm", strings.NewReader("0123456789"), "audio/webm"); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	reader, err := store.DownloadRange(ctx, "final/1/recording.webm", 2, 3)
+	if err != nil {
+		t.Fatalf("DownloadRange() error = %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(data) != "234" {
+		t.Errorf("DownloadRange() = %q, want %q", data, "234")
+	}
+
+	info, err := store.Stat(ctx, "final/1/recording.webm")
+	if err != nil || info.Size != 10 || info.ETag == "" {
+		t.Errorf("Stat() = %+v, %v", info, err)
+	}
+
+	if err := store.Delete(ctx, "final/1/recording.webm"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(store.basePath, "final")); !os.IsNotExist(err) {
+		t.Errorf("empty directories left behind after Delete(): %v", err)
+	}
+	if _, err := store.Stat(ctx, "final/1/recording.webm"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat() after Delete() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalStorageSignedURL(t *testing.T) {
+	store := newTestLocalStorage(t, "https://api.example.com/files/", "signing-key")
+	ctx := context.Background()
+	path := "final/1/recording.webm"
+
+	signed, err := store.SignedURL(ctx, path, time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL() error = %v", err)
+	}
+
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("SignedURL() = %q, not a URL: %v", signed, err)
+	}
+	if u.Path != "/files/"+path {
+		t.Errorf("SignedURL() path = %q, want %q", u.Path, "/files/"+path)
+	}
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+
+	if err := store.VerifySignedURL(path, expires, signature); err != nil {
+		t.Errorf("VerifySignedURL() error = %v", err)
+	}
+
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	other := newTestLocalStorage(t, "https://api.example.com/files", "other-key")
+
+	tests := []struct {
+		name      string
+		store     *LocalStorage
+		path      string
+		expires   string
+		signature string
+		want      error
+	}{
+		{"other path", store, "final/2/recording.webm", expires, signature, ErrInvalidSignature},
+		{"extended expiry", store, path, expires + "0", signature, ErrInvalidSignature},
+		{"tampered signature", store, path, expires, strings.Repeat("0", len(signature)), ErrInvalidSignature},
+		{"missing signature", store, path, expires, "", ErrInvalidSignature},
+		{"non-numeric expiry", store, path, "soon", store.sign(path, "soon"), ErrInvalidSignature},
+		{"expired", store, path, past, store.sign(path, past), ErrURLExpired},
+		{"other signing key", other, path, expires, signature, ErrInvalidSignature},
+		{"signing not configured", newTestLocalStorage(t, "", ""), path, expires, signature, ErrSigningUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.store.VerifySignedURL(tt.path, tt.expires, tt.signature); !errors.Is(err, tt.want) {
+				t.Errorf("VerifySignedURL() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalStorageSignedURLUnavailable(t *testing.T) {
+	ctx := context.Background()
+
+	for name, store := range map[string]*LocalStorage{
+		"no base URL":    newTestLocalStorage(t, "", "signing-key"),
+		"no signing key": newTestLocalStorage(t, "https://api.example.com/files", ""),
+	} {
+		if _, err := store.SignedURL(ctx, "final/1/recording.webm", time.Minute); !errors.Is(err, ErrSigningUnavailable) {
+			t.Errorf("%s: SignedURL() error = %v, want ErrSigningUnavailable", name, err)
+		}
+	}
+
+	store := newTestLocalStorage(t, "https://api.example.com/files", "signing-key")
+	if _, err := store.SignedURL(ctx, "../outside.txt", time.Minute); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("SignedURL() error = %v, want ErrInvalidPath", err)
+	}
+}
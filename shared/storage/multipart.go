@@ -0,0 +1,342 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/utils"
+)
+
+const (
+	minPartSize    = 5 << 20 // S3 minimum for all but the last part
+	maxUploadParts = 10000   // S3 maximum parts per upload
+)
+
+// MultipartConfig controls how files are uploaded to S3-compatible storage.
+// Files larger than one part are uploaded in parts, so memory use is bounded by
+// PartSize * Concurrency regardless of the file size.
+type MultipartConfig struct {
+	PartSize    int64 // Bytes per part (at least 5 MB)
+	Concurrency int   // Parts uploaded in parallel
+	PartRetries int   // Attempts per part before the upload is aborted
+}
+
+// DefaultMultipartConfig returns the default upload settings: 16 MB parts, 4 at a time
+func DefaultMultipartConfig() MultipartConfig {
+	return MultipartConfig{
+		PartSize:    16 << 20,
+		Concurrency: 4,
+		PartRetries: 3,
+	}
+}
+
+// MultipartConfigFromEnv loads the upload settings from STORAGE_UPLOAD_* environment
+// variables, falling back to the defaults
+func MultipartConfigFromEnv() MultipartConfig {
+	def := DefaultMultipartConfig()
+
+	return MultipartConfig{
+		PartSize:    int64(utils.GetEnvOrDefaultInt("STORAGE_UPLOAD_PART_SIZE_MB", int(def.PartSize>>20))) << 20,
+		Concurrency: utils.GetEnvOrDefaultInt("STORAGE_UPLOAD_CONCURRENCY", def.Concurrency),
+		PartRetries: utils.GetEnvOrDefaultInt("STORAGE_UPLOAD_PART_RETRIES", def.PartRetries),
+	}
+}
+
+// normalized returns the configuration with out-of-range values replaced
+func (c MultipartConfig) normalized() MultipartConfig {
+	def := DefaultMultipartConfig()
+	if c.PartSize == 0 {
+		c.PartSize = def.PartSize
+	}
+	if c.PartSize < minPartSize {
+		c.PartSize = minPartSize
+	}
+	if c.Concurrency < 1 {
+		c.Concurrency = def.Concurrency
+	}
+	if c.PartRetries < 1 {
+		c.PartRetries = 1
+	}
+	return c
+}
+
+// part is one chunk of a multipart upload
+type part struct {
+	number int64
+	data   []byte
+}
+
+// upload writes reader to path, as a single PUT if it fits in one part and as a
+// multipart upload otherwise. Every part carries its MD5, which the server checks.
+func (s *SupabaseStorage) upload(ctx context.Context, path string, reader io.Reader, contentType string) (int64, error) {
+	cfg := s.multipart
+
+	// Buffers are allocated as parts need them and reused; at most Concurrency exist,
+	// so files that fit in one part only ever allocate the first
+	buffers := make(chan []byte, cfg.Concurrency)
+	allocated := 1
+	nextBuffer := func() []byte {
+		select {
+		case buf := <-buffers:
+			return buf
+		default:
+		}
+		if allocated < cfg.Concurrency {
+			allocated++
+			return make([]byte, cfg.PartSize)
+		}
+		return <-buffers
+	}
+
+	first := make([]byte, cfg.PartSize)
+	n, err := io.ReadFull(reader, first)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return int64(n), s.putObject(ctx, path, first[:n], contentType)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file data: %w", err)
+	}
+
+	created, err := s.client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(path),
+		ContentType: aws.String(contentType),
+		ACL:         s.acl(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to start multipart upload: %w", err)
+	}
+	uploadID := created.UploadId
+
+	uploadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		completed []*s3.CompletedPart
+		uploadErr error
+		wg        sync.WaitGroup
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if uploadErr == nil {
+			uploadErr = err
+		}
+		mu.Unlock()
+		cancel()
+	}
+
+	parts := make(chan part)
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range parts {
+				etag, err := s.uploadPart(uploadCtx, path, uploadID, p)
+				buffers <- p.data[:cap(p.data)]
+				if err != nil {
+					fail(err)
+					continue
+				}
+
+				mu.Lock()
+				completed = append(completed, &s3.CompletedPart{PartNumber: aws.Int64(p.number), ETag: etag})
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// Read parts while earlier ones upload; blocks while all buffers are in use
+	size := int64(n)
+	next := part{number: 1, data: first}
+	for {
+		select {
+		case parts <- next:
+		case <-uploadCtx.Done():
+		}
+		if uploadCtx.Err() != nil || len(next.data) < int(cfg.PartSize) {
+			break
+		}
+
+		buf := nextBuffer()
+		n, err := io.ReadFull(reader, buf)
+		if n == 0 && err == io.EOF {
+			buffers <- buf
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			buffers <- buf
+			fail(fmt.Errorf("failed to read file data: %w", err))
+			break
+		}
+		if next.number+1 > maxUploadParts {
+			buffers <- buf
+			fail(fmt.Errorf("file exceeds %d parts of %d bytes", maxUploadParts, cfg.PartSize))
+			break
+		}
+
+		size += int64(n)
+		next = part{number: next.number + 1, data: buf[:n]}
+	}
+	close(parts)
+	wg.Wait()
+
+	if uploadErr == nil {
+		uploadErr = ctx.Err()
+	}
+	if uploadErr == nil {
+		uploadErr = s.completeUpload(ctx, path, uploadID, completed, size)
+	}
+	if uploadErr != nil {
+		s.abortUpload(path, uploadID)
+		return 0, uploadErr
+	}
+
+	return size, nil
+}
+
+// putObject uploads a file that fits in one part
+func (s *SupabaseStorage) putObject(ctx context.Context, path string, data []byte, contentType string) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(path),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+		ContentMD5:  aws.String(contentMD5(data)),
+		ACL:         s.acl(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload to Supabase Storage: %w", err)
+	}
+	return nil
+}
+
+// uploadPart uploads one part, retrying it on failure. Returns its ETag.
+func (s *SupabaseStorage) uploadPart(ctx context.Context, path string, uploadID *string, p part) (*string, error) {
+	sum := md5.Sum(p.data)
+
+	var err error
+	for attempt := 1; attempt <= s.multipart.PartRetries; attempt++ {
+		var result *s3.UploadPartOutput
+		result, err = s.client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(s.bucket),
+			Key:        aws.String(path),
+			UploadId:   uploadID,
+			PartNumber: aws.Int64(p.number),
+			Body:       bytes.NewReader(p.data),
+			ContentMD5: aws.String(base64.StdEncoding.EncodeToString(sum[:])),
+		})
+		if err == nil {
+			err = verifyETag(result.ETag, sum[:])
+		}
+		if err == nil {
+			return result.ETag, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		log.Warn().
+			Err(err).
+			Str("path", path).
+			Int64("part", p.number).
+			Int("attempt", attempt).
+			Msg("Part upload failed")
+
+		if attempt == s.multipart.PartRetries {
+			break
+		}
+		select {
+		case <-time.After(time.Duration(attempt) * time.Second):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, fmt.Errorf("failed to upload part %d: %w", p.number, err)
+}
+
+// completeUpload assembles the uploaded parts and checks the object's size
+func (s *SupabaseStorage) completeUpload(ctx context.Context, path string, uploadID *string, completed []*s3.CompletedPart, size int64) error {
+	sort.Slice(completed, func(i, j int) bool {
+		return *completed[i].PartNumber < *completed[j].PartNumber
+	})
+
+	_, err := s.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(path),
+		UploadId:        uploadID,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	head, err := s.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(path),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify upload: %w", err)
+	}
+	if stored := aws.Int64Value(head.ContentLength); stored != size {
+		return fmt.Errorf("uploaded object has %d bytes, expected %d", stored, size)
+	}
+
+	return nil
+}
+
+// abortUpload discards the parts of a failed upload so they don't linger in the bucket
+func (s *SupabaseStorage) abortUpload(path string, uploadID *string) {
+	// The upload's context may be what failed
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := s.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(path),
+		UploadId: uploadID,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("path", path).Msg("Failed to abort multipart upload")
+		return
+	}
+
+	log.Info().Str("path", path).Msg("Multipart upload aborted")
+}
+
+// contentMD5 returns the Content-MD5 header value for data
+func contentMD5(data []byte) string {
+	sum := md5.Sum(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// verifyETag checks a part's ETag against its MD5. ETags that are not a plain MD5
+// (e.g. with server-side encryption) can't be compared and are accepted.
+func verifyETag(etag *string, sum []byte) error {
+	value := strings.Trim(aws.StringValue(etag), `"`)
+	if len(value) != 32 {
+		return nil
+	}
+	if _, err := hex.DecodeString(value); err != nil {
+		return nil
+	}
+	if !strings.EqualFold(value, hex.EncodeToString(sum)) {
+		return errors.New("part checksum mismatch")
+	}
+	return nil
+}
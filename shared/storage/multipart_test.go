@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// s3Stub is a minimal path-style S3 server recording the requests of an upload
+type s3Stub struct {
+	mu         sync.Mutex
+	objects    map[string]int64 // Object size by key
+	puts       int
+	parts      map[int]int // Part size by part number
+	completed  bool
+	aborted    bool
+	badETag    int   // Part answered with a wrong ETag
+	rejectPart int   // Part answered with an error
+	headSize   int64 // Size reported by HEAD, if not zero
+}
+
+func newS3Stub() *s3Stub {
+	return &s3Stub{objects: make(map[string]int64), parts: make(map[int]int)}
+}
+
+func (s *s3Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	query := r.URL.Query()
+	_, uploads := query["uploads"]
+
+	switch {
+	case r.Method == http.MethodHead && r.URL.Path == "/bucket":
+		w.WriteHeader(http.StatusOK)
+
+	case r.Method == http.MethodHead:
+		size, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if s.headSize != 0 {
+			size = s.headSize
+		}
+		w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+		w.WriteHeader(http.StatusOK)
+
+	case r.Method == http.MethodPost && uploads:
+		fmt.Fprint(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`)
+
+	case r.Method == http.MethodPut && query.Get("partNumber") != "":
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		if number == s.rejectPart {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`)
+			return
+		}
+
+		s.parts[number] = len(body)
+		sum := md5.Sum(body)
+		etag := hex.EncodeToString(sum[:])
+		if number == s.badETag {
+			etag = "00000000000000000000000000000000"
+		}
+		w.Header().Set("ETag", `"`+etag+`"`)
+		w.WriteHeader(http.StatusOK)
+
+	case r.Method == http.MethodPut:
+		s.puts++
+		s.objects[r.URL.Path] = int64(len(body))
+		w.WriteHeader(http.StatusOK)
+
+	case r.Method == http.MethodPost && query.Get("uploadId") != "":
+		s.completed = true
+		var size int64
+		for _, n := range s.parts {
+			size += int64(n)
+		}
+		s.objects[r.URL.Path] = size
+		fmt.Fprint(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><ETag>"done-1"</ETag></CompleteMultipartUploadResult>`)
+
+	case r.Method == http.MethodDelete && query.Get("uploadId") != "":
+		s.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func newTestSupabaseStorage(t *testing.T, stub *s3Stub) *SupabaseStorage {
+	t.Helper()
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	store, err := NewSupabaseStorage(Config{
+		Endpoint:  server.URL,
+		Bucket:    "bucket",
+		Region:    "us-east-1",
+		AccessKey: "test",
+		SecretKey: "test",
+		// One attempt per part, so failures don't wait on retry backoff
+		Multipart: MultipartConfig{PartSize: minPartSize, Concurrency: 2, PartRetries: 1},
+	})
+	if err != nil {
+		t.Fatalf("NewSupabaseStorage() error = %v", err)
+	}
+	return store
+}
+
+func TestMultipartConfigNormalized(t *testing.T) {
+	def := DefaultMultipartConfig()
+
+	tests := []struct {
+		name string
+		cfg  MultipartConfig
+		want MultipartConfig
+	}{
+		{"zero uses defaults", MultipartConfig{}, MultipartConfig{PartSize: def.PartSize, Concurrency: def.Concurrency, PartRetries: 1}},
+		{"part size below minimum", MultipartConfig{PartSize: 1 << 20, Concurrency: 2, PartRetries: 2}, MultipartConfig{PartSize: minPartSize, Concurrency: 2, PartRetries: 2}},
+		{"negative values", MultipartConfig{PartSize: -1, Concurrency: -1, PartRetries: -1}, MultipartConfig{PartSize: minPartSize, Concurrency: def.Concurrency, PartRetries: 1}},
+		{"valid values kept", MultipartConfig{PartSize: 32 << 20, Concurrency: 8, PartRetries: 5}, MultipartConfig{PartSize: 32 << 20, Concurrency: 8, PartRetries: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.normalized(); got != tt.want {
+				t.Errorf("normalized() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadPartSizing(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		wantPuts  int
+		wantParts []int
+	}{
+		{"empty file", 0, 1, nil},
+		{"smaller than a part", 1 << 20, 1, nil},
+		{"exactly one part", minPartSize, 0, []int{minPartSize}},
+		{"exactly two parts", 2 * minPartSize, 0, []int{minPartSize, minPartSize}},
+		{"last part shorter", 2*minPartSize + minPartSize/2, 0, []int{minPartSize, minPartSize, minPartSize / 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newS3Stub()
+			store := newTestSupabaseStorage(t, stub)
+
+			size, err := store.upload(context.Background(), "final/1/recording.webm", bytes.NewReader(bytes.Repeat([]byte("a"), tt.size)), "video/webm")
+			if err != nil {
+				t.Fatalf("upload() error = %v", err)
+			}
+			if size != int64(tt.size) {
+				t.Errorf("upload() size = %d, want %d", size, tt.size)
+			}
+
+			if stub.puts != tt.wantPuts {
+				t.Errorf("single PUTs = %d, want %d", stub.puts, tt.wantPuts)
+			}
+			if len(stub.parts) != len(tt.wantParts) {
+				t.Fatalf("parts = %v, want sizes %v", stub.parts, tt.wantParts)
+			}
+			for i, want := range tt.wantParts {
+				if got := stub.parts[i+1]; got != want {
+					t.Errorf("part %d size = %d, want %d", i+1, got, want)
+				}
+			}
+			if stub.completed != (len(tt.wantParts) > 0) {
+				t.Errorf("completed = %v, want %v", stub.completed, len(tt.wantParts) > 0)
+			}
+			if stub.aborted {
+				t.Error("upload was aborted")
+			}
+		})
+	}
+}
+
+func TestUploadAbortsOnError(t *testing.T) {
+	tests := []struct {
+		name          string
+		badETag       int
+		rejectPart    int
+		headSize      int64
+		wantCompleted bool
+	}{
+		{"part ETag mismatch", 2, 0, 0, false},
+		{"part rejected", 0, 3, 0, false},
+		{"stored size mismatch", 0, 0, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newS3Stub()
+			stub.badETag = tt.badETag
+			stub.rejectPart = tt.rejectPart
+			stub.headSize = tt.headSize
+			store := newTestSupabaseStorage(t, stub)
+
+			data := bytes.Repeat([]byte("a"), 3*minPartSize)
+			if _, err := store.upload(context.Background(), "final/1/recording.webm", bytes.NewReader(data), "video/webm"); err == nil {
+				t.Fatal("upload() error = nil, want error")
+			}
+
+			if !stub.aborted {
+				t.Error("failed upload was not aborted")
+			}
+			if stub.completed != tt.wantCompleted {
+				t.Errorf("completed = %v, want %v", stub.completed, tt.wantCompleted)
+			}
+		})
+	}
+}
+
+func TestVerifyETag(t *testing.T) {
+	sum := md5.Sum([]byte("part data"))
+	hexSum := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name    string
+		etag    *string
+		wantErr bool
+	}{
+		{"matching", aws.String(hexSum), false},
+		{"quoted", aws.String(`"` + hexSum + `"`), false},
+		{"upper case", aws.String(`"` + strings.ToUpper(hexSum) + `"`), false},
+		{"mismatch", aws.String(`"00000000000000000000000000000000"`), true},
+		{"not hex", aws.String(`"zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz"`), false},
+		{"multipart ETag", aws.String(`"` + hexSum + `-2"`), false},
+		{"missing", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyETag(tt.etag, sum[:])
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifyETag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,9 +2,14 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"io"
+	"time"
 )
 
+// ErrNotFound is returned by Stat for files that don't exist
+var ErrNotFound = errors.New("file not found")
+
 // Storage defines the interface for file storage operations
 type Storage interface {
 	// Upload uploads a file to storage
@@ -13,24 +18,63 @@ type Storage interface {
 	// Download downloads a file from storage
 	Download(ctx context.Context, path string) (io.ReadCloser, error)
 
+	// DownloadRange downloads length bytes of a file starting at offset
+	DownloadRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error)
+
+	// Stat returns the size and version of a file, or ErrNotFound
+	Stat(ctx context.Context, path string) (ObjectInfo, error)
+
 	// Delete deletes a file from storage
 	Delete(ctx context.Context, path string) error
 
 	// GetPublicURL returns the public URL for a file
 	GetPublicURL(path string) string
 
+	// SignedURL returns a URL that grants read access to a file until ttl elapses
+	SignedURL(ctx context.Context, path string, ttl time.Duration) (string, error)
+
 	// Exists checks if a file exists in storage
 	Exists(ctx context.Context, path string) (bool, error)
+
+	// List returns the paths of the files below a directory, sorted
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// ObjectInfo describes a file in storage
+type ObjectInfo struct {
+	Size         int64
+	ETag         string // Quoted entity tag, changes whenever the file does
+	LastModified time.Time
+}
+
+// LocalFiles is implemented by storage backends whose files live on the local
+// filesystem, so they can be read in place instead of downloaded
+type LocalFiles interface {
+	// LocalPath returns the filesystem path of a file in storage
+	LocalPath(path string) (string, error)
+}
+
+// SignedURLVerifier is implemented by storage backends whose signed URLs are
+// served by the application rather than by the storage service itself
+type SignedURLVerifier interface {
+	// VerifySignedURL checks the expiry and signature query parameters of a signed URL for path
+	VerifySignedURL(path, expires, signature string) error
 }
 
 // Config holds storage configuration
 type Config struct {
-	Provider      string // "supabase" or "local"
+	Provider      string // "supabase", "s3" or "local"
 	SupabaseURL   string
 	Bucket        string
 	Region        string
 	AccessKey     string
 	SecretKey     string
 	Endpoint      string
+	PublicURL     string // Base URL of public objects (defaults to the Supabase public URL)
+	PublicRead    bool   // Upload objects with a public-read ACL (private by default)
+	SignEndpoint  string // Endpoint signed URLs point at, if clients reach storage under another host
+	Multipart     MultipartConfig
 	LocalBasePath string
+	LocalBaseURL  string // Optional URL the local storage root is served under
+	SigningKey    string // Secret signed local URLs are signed with
 }
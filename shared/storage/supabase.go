@@ -1,23 +1,33 @@
 package storage
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/rs/zerolog/log"
 )
 
-// SupabaseStorage implements Storage interface using Supabase Storage (S3-compatible)
+// SupabaseStorage implements Storage interface using Supabase Storage (S3-compatible).
+// It works against any S3-compatible service.
 type SupabaseStorage struct {
 	client      *s3.S3
+	signer      *s3.S3 // Client signed URLs are built with
 	bucket      string
 	supabaseURL string
+	publicURL   string
+	publicRead  bool
+	multipart   MultipartConfig
 }
 
 // NewSupabaseStorage creates a new Supabase storage client
@@ -41,6 +51,12 @@ func NewSupabaseStorage(cfg Config) (*SupabaseStorage, error) {
 
 	client := s3.New(sess)
 
+	// Signed URLs are handed to clients, which may reach storage under another host
+	signer := client
+	if cfg.SignEndpoint != "" {
+		signer = s3.New(sess, &aws.Config{Endpoint: aws.String(cfg.SignEndpoint)})
+	}
+
 	// Verify bucket exists
 	_, err = client.HeadBucket(&s3.HeadBucketInput{
 		Bucket: aws.String(cfg.Bucket),
@@ -52,12 +68,16 @@ func NewSupabaseStorage(cfg Config) (*SupabaseStorage, error) {
 			Msg("Bucket may not exist or is not accessible - will continue anyway")
 	}
 
-	log.Info().Msg("Supabase Storage initialized successfully")
+	log.Info().Bool("public_read", cfg.PublicRead).Msg("Supabase Storage initialized successfully")
 
 	return &SupabaseStorage{
 		client:      client,
+		signer:      signer,
 		bucket:      cfg.Bucket,
 		supabaseURL: cfg.SupabaseURL,
+		publicURL:   strings.TrimSuffix(cfg.PublicURL, "/"),
+		publicRead:  cfg.PublicRead,
+		multipart:   cfg.Multipart.normalized(),
 	}, nil
 }
 
@@ -68,27 +88,10 @@ func (s *SupabaseStorage) Upload(ctx context.Context, path string, reader io.Rea
 		Str("content_type", contentType).
 		Msg("Uploading file to Supabase Storage")
 
-	// Read all data from reader
-	data, err := io.ReadAll(reader)
+	// Streamed in parts; large recordings are never held in memory as a whole
+	size, err := s.upload(ctx, path, reader, contentType)
 	if err != nil {
-		return "", fmt.Errorf("failed to read file data: %w", err)
-	}
-
-	log.Info().
-		Int("size_bytes", len(data)).
-		Str("path", path).
-		Msg("File data read, uploading to S3")
-
-	// Upload to S3
-	_, err = s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(s.bucket),
-		Key:         aws.String(path),
-		Body:        bytes.NewReader(data),
-		ContentType: aws.String(contentType),
-		ACL:         aws.String("public-read"), // Make file publicly accessible
-	})
-	if err != nil {
-		return "", fmt.Errorf("failed to upload to Supabase Storage: %w", err)
+		return "", err
 	}
 
 	publicURL := s.GetPublicURL(path)
@@ -96,7 +99,7 @@ func (s *SupabaseStorage) Upload(ctx context.Context, path string, reader io.Rea
 	log.Info().
 		Str("path", path).
 		Str("url", publicURL).
-		Int("size_bytes", len(data)).
+		Int64("size_bytes", size).
 		Msg("File uploaded successfully to Supabase Storage")
 
 	return publicURL, nil
@@ -119,6 +122,41 @@ func (s *SupabaseStorage) Download(ctx context.Context, path string) (io.ReadClo
 	return result.Body, nil
 }
 
+// DownloadRange downloads length bytes of a file from Supabase Storage, starting at offset
+func (s *SupabaseStorage) DownloadRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	result, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(path),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download range from Supabase Storage: %w", err)
+	}
+
+	return result.Body, nil
+}
+
+// Stat returns the size and version of a file in Supabase Storage
+func (s *SupabaseStorage) Stat(ctx context.Context, path string) (ObjectInfo, error) {
+	head, err := s.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(path),
+	})
+	if err != nil {
+		var reqErr awserr.RequestFailure
+		if errors.As(err, &reqErr) && reqErr.StatusCode() == http.StatusNotFound {
+			return ObjectInfo{}, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return ObjectInfo{}, fmt.Errorf("failed to stat Supabase Storage object: %w", err)
+	}
+
+	return ObjectInfo{
+		Size:         aws.Int64Value(head.ContentLength),
+		ETag:         aws.StringValue(head.ETag),
+		LastModified: aws.TimeValue(head.LastModified),
+	}, nil
+}
+
 // Delete deletes a file from Supabase Storage
 func (s *SupabaseStorage) Delete(ctx context.Context, path string) error {
 	log.Info().
@@ -137,13 +175,33 @@ func (s *SupabaseStorage) Delete(ctx context.Context, path string) error {
 	return nil
 }
 
-// GetPublicURL returns the public URL for a file in Supabase Storage
+// GetPublicURL returns the public URL for a file in Supabase Storage.
+// Objects are private unless uploaded with PublicRead; use SignedURL to share them.
 func (s *SupabaseStorage) GetPublicURL(path string) string {
+	if s.publicURL != "" {
+		return s.publicURL + "/" + path
+	}
+
 	// Supabase Storage public URL format:
 	// https://<project-ref>.supabase.co/storage/v1/object/public/<bucket>/<path>
 	return fmt.Sprintf("%s/storage/v1/object/public/%s/%s", s.supabaseURL, s.bucket, path)
 }
 
+// SignedURL returns a presigned GET URL for a file in Supabase Storage
+func (s *SupabaseStorage) SignedURL(ctx context.Context, path string, ttl time.Duration) (string, error) {
+	req, _ := s.signer.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(path),
+	})
+	req.SetContext(ctx)
+
+	signedURL, err := req.Presign(ttl)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign URL: %w", err)
+	}
+	return signedURL, nil
+}
+
 // Exists checks if a file exists in Supabase Storage
 func (s *SupabaseStorage) Exists(ctx context.Context, path string) (bool, error) {
 	_, err := s.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
@@ -159,3 +217,33 @@ func (s *SupabaseStorage) Exists(ctx context.Context, path string) (bool, error)
 	}
 	return true, nil
 }
+
+// List returns the paths of the files below a directory in Supabase Storage
+func (s *SupabaseStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	paths := []string{}
+
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(strings.TrimSuffix(prefix, "/") + "/"),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			paths = append(paths, aws.StringValue(object.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Supabase Storage objects: %w", err)
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// acl returns the canned ACL uploaded objects get: none (private) unless public
+// reads are enabled
+func (s *SupabaseStorage) acl() *string {
+	if s.publicRead {
+		return aws.String("public-read")
+	}
+	return nil
+}
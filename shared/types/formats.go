@@ -0,0 +1,64 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OutputFormat is a container/codec a finished recording can be rendered to
+type OutputFormat string
+
+const (
+	OutputFormatWebM OutputFormat = "webm" // Opus in WebM - what the bot records
+	OutputFormatMP3  OutputFormat = "mp3"
+	OutputFormatM4A  OutputFormat = "m4a" // AAC in MP4
+	OutputFormatWAV  OutputFormat = "wav" // 16-bit PCM
+	OutputFormatOGG  OutputFormat = "ogg" // Opus in Ogg
+)
+
+// outputFormatContentTypes maps each supported output format to its MIME type
+var outputFormatContentTypes = map[OutputFormat]string{
+	OutputFormatWebM: "audio/webm",
+	OutputFormatMP3:  "audio/mpeg",
+	OutputFormatM4A:  "audio/mp4",
+	OutputFormatWAV:  "audio/wav",
+	OutputFormatOGG:  "audio/ogg",
+}
+
+// IsValid reports whether the format is supported
+func (f OutputFormat) IsValid() bool {
+	_, ok := outputFormatContentTypes[f]
+	return ok
+}
+
+// ContentType returns the MIME type served for the format
+func (f OutputFormat) ContentType() string {
+	if contentType, ok := outputFormatContentTypes[f]; ok {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// Extension returns the file extension for the format, including the dot
+func (f OutputFormat) Extension() string {
+	return "." + string(f)
+}
+
+// ParseOutputFormats normalizes and validates a list of output format names,
+// dropping duplicates. Returns an error naming the first unsupported format.
+func ParseOutputFormats(names []string) ([]string, error) {
+	formats := []string{}
+	seen := map[OutputFormat]bool{}
+	for _, name := range names {
+		format := OutputFormat(strings.ToLower(strings.TrimSpace(name)))
+		if !format.IsValid() {
+			return nil, fmt.Errorf("unsupported output format %q (supported: webm, mp3, m4a, wav, ogg)", name)
+		}
+		if seen[format] {
+			continue
+		}
+		seen[format] = true
+		formats = append(formats, string(format))
+	}
+	return formats, nil
+}
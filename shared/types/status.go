@@ -0,0 +1,43 @@
+package types
+
+// meetingTransitions is the meeting status state machine: the statuses each
+// status may move to. Completed and failed are terminal.
+var meetingTransitions = map[MeetingStatus][]MeetingStatus{
+	MeetingStatusScheduled:  {MeetingStatusRequested, MeetingStatusFailed},
+	MeetingStatusRequested:  {MeetingStatusJoining, MeetingStatusFailed},
+	MeetingStatusJoining:    {MeetingStatusActive, MeetingStatusFailed},
+	MeetingStatusActive:     {MeetingStatusRecording, MeetingStatusFailed},
+	MeetingStatusRecording:  {MeetingStatusFinalizing, MeetingStatusFailed},
+	MeetingStatusFinalizing: {MeetingStatusCompleted, MeetingStatusFailed},
+}
+
+// CanTransition reports whether a meeting may move from one status to another
+func CanTransition(from, to MeetingStatus) bool {
+	for _, allowed := range meetingTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminalStatus reports whether a meeting status is final
+func IsTerminalStatus(status MeetingStatus) bool {
+	return status == MeetingStatusCompleted || status == MeetingStatusFailed
+}
+
+// TransitionSources returns the statuses a meeting must currently be in for a
+// write of status "to" to be accepted. Non-terminal statuses may also be written
+// again (e.g. repeated "recording" updates carrying chunk counts).
+func TransitionSources(to MeetingStatus) []MeetingStatus {
+	sources := []MeetingStatus{}
+	for from := range meetingTransitions {
+		if CanTransition(from, to) {
+			sources = append(sources, from)
+		}
+	}
+	if !IsTerminalStatus(to) {
+		sources = append(sources, to)
+	}
+	return sources
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from MeetingStatus
+		to   MeetingStatus
+		want bool
+	}{
+		{MeetingStatusScheduled, MeetingStatusRequested, true},
+		{MeetingStatusScheduled, MeetingStatusFailed, true},
+		{MeetingStatusScheduled, MeetingStatusJoining, false},
+		{MeetingStatusRequested, MeetingStatusJoining, true},
+		{MeetingStatusRequested, MeetingStatusRecording, false},
+		{MeetingStatusJoining, MeetingStatusActive, true},
+		{MeetingStatusActive, MeetingStatusRecording, true},
+		{MeetingStatusActive, MeetingStatusFinalizing, false},
+		{MeetingStatusRecording, MeetingStatusFinalizing, true},
+		{MeetingStatusRecording, MeetingStatusCompleted, false},
+		{MeetingStatusFinalizing, MeetingStatusCompleted, true},
+		{MeetingStatusFinalizing, MeetingStatusFailed, true},
+		{MeetingStatusFinalizing, MeetingStatusRecording, false},
+		{MeetingStatusCompleted, MeetingStatusFailed, false},
+		{MeetingStatusCompleted, MeetingStatusFinalizing, false},
+		{MeetingStatusFailed, MeetingStatusFinalizing, false},
+		{MeetingStatusFailed, MeetingStatusRequested, false},
+		{MeetingStatusRecording, MeetingStatusRecording, false}, // Repeats are a TransitionSources concern
+		{MeetingStatus("unknown"), MeetingStatusFailed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransitionSources(t *testing.T) {
+	tests := []struct {
+		to   MeetingStatus
+		want []MeetingStatus
+	}{
+		{MeetingStatusScheduled, []MeetingStatus{MeetingStatusScheduled}},
+		{MeetingStatusRequested, []MeetingStatus{MeetingStatusRequested, MeetingStatusScheduled}},
+		{MeetingStatusJoining, []MeetingStatus{MeetingStatusJoining, MeetingStatusRequested}},
+		{MeetingStatusRecording, []MeetingStatus{MeetingStatusActive, MeetingStatusRecording}},
+		{MeetingStatusFinalizing, []MeetingStatus{MeetingStatusFinalizing, MeetingStatusRecording}},
+		{MeetingStatusCompleted, []MeetingStatus{MeetingStatusFinalizing}},
+		{MeetingStatusFailed, []MeetingStatus{
+			MeetingStatusActive, MeetingStatusFinalizing, MeetingStatusJoining,
+			MeetingStatusRecording, MeetingStatusRequested, MeetingStatusScheduled,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.to), func(t *testing.T) {
+			got := TransitionSources(tt.to)
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("TransitionSources(%s) = %v, want %v", tt.to, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("TransitionSources(%s) = %v, want %v", tt.to, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestIsTerminalStatus(t *testing.T) {
+	for status, want := range map[MeetingStatus]bool{
+		MeetingStatusScheduled:  false,
+		MeetingStatusRecording:  false,
+		MeetingStatusFinalizing: false,
+		MeetingStatusCompleted:  true,
+		MeetingStatusFailed:     true,
+	} {
+		if got := IsTerminalStatus(status); got != want {
+			t.Errorf("IsTerminalStatus(%s) = %v, want %v", status, got, want)
+		}
+	}
+}
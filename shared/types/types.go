@@ -2,7 +2,10 @@ package types
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
+
+	"github.com/newar/insights/shared/constants"
 )
 
 // =====================================================
@@ -32,6 +35,54 @@ type CreateUserRequest struct {
 type UpdateUserRequest struct {
 	Name              *string `json:"name,omitempty" validate:"omitempty,min=2,max=255"`
 	MaxConcurrentBots *int    `json:"max_concurrent_bots,omitempty" validate:"omitempty,gte=1,lte=50"`
+
+	// Default max_duration_seconds for the user's recordings (stored in users.data, 0 clears it)
+	DefaultMaxDurationSeconds *int `json:"default_max_duration_seconds,omitempty"`
+
+	// Default output_formats for the user's recordings (stored in users.data, [] clears it)
+	DefaultOutputFormats []string `json:"default_output_formats,omitempty"`
+}
+
+// DefaultMaxDuration returns the user's default recording limit in seconds from
+// Data, or nil if none is set
+func (u *User) DefaultMaxDuration() *int {
+	if len(u.Data) == 0 {
+		return nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(u.Data, &data); err != nil {
+		return nil
+	}
+
+	seconds, ok := data[constants.UserMaxDurationKey].(float64)
+	if !ok || seconds <= 0 {
+		return nil
+	}
+
+	value := int(seconds)
+	return &value
+}
+
+// DefaultOutputFormats returns the user's default extra output formats from Data
+func (u *User) DefaultOutputFormats() []string {
+	if len(u.Data) == 0 {
+		return nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(u.Data, &data); err != nil {
+		return nil
+	}
+
+	values, _ := data[constants.UserOutputFormatsKey].([]interface{})
+	formats := make([]string, 0, len(values))
+	for _, value := range values {
+		if format, ok := value.(string); ok {
+			formats = append(formats, format)
+		}
+	}
+	return formats
 }
 
 // =====================================================
@@ -60,6 +111,7 @@ type GenerateTokenResponse struct {
 type MeetingStatus string
 
 const (
+	MeetingStatusScheduled  MeetingStatus = "scheduled" // Waiting for scheduled_at before the bot is spawned
 	MeetingStatusRequested  MeetingStatus = "requested"
 	MeetingStatusJoining    MeetingStatus = "joining"
 	MeetingStatusActive     MeetingStatus = "active"
@@ -79,23 +131,71 @@ const (
 
 // Meeting represents a recording session
 type Meeting struct {
-	ID                 int64         `json:"id" db:"id"`
-	UserID             int64         `json:"user_id" db:"user_id"`
-	Platform           Platform      `json:"platform" db:"platform"`
-	MeetingID          string        `json:"meeting_id" db:"meeting_id"`
-	MeetingURL         string        `json:"meeting_url" db:"meeting_url"`
-	BotName            *string       `json:"bot_name,omitempty" db:"bot_name"`
-	BotContainerID     *string       `json:"bot_container_id,omitempty" db:"bot_container_id"`
-	RecordingSessionID *string       `json:"recording_session_id,omitempty" db:"recording_session_id"`
-	Status             MeetingStatus `json:"status" db:"status"`
-	RecordingPath      *string       `json:"recording_path,omitempty" db:"recording_path"`
-	RecordingDuration  *int          `json:"recording_duration,omitempty" db:"recording_duration"` // seconds
-	RecordingURL       *string       `json:"recording_url,omitempty" db:"-"` // Computed
-	ErrorMessage       *string       `json:"error_message,omitempty" db:"error_message"`
-	StartedAt          *time.Time    `json:"started_at,omitempty" db:"started_at"`
-	CompletedAt        *time.Time    `json:"completed_at,omitempty" db:"completed_at"`
-	CreatedAt          time.Time     `json:"created_at" db:"created_at"`
-	UpdatedAt          time.Time     `json:"updated_at" db:"updated_at"`
+	ID                 int64           `json:"id" db:"id"`
+	UserID             int64           `json:"user_id" db:"user_id"`
+	Platform           Platform        `json:"platform" db:"platform"`
+	MeetingID          string          `json:"meeting_id" db:"meeting_id"`
+	MeetingURL         string          `json:"meeting_url" db:"meeting_url"`
+	BotName            *string         `json:"bot_name,omitempty" db:"bot_name"`
+	BotContainerID     *string         `json:"bot_container_id,omitempty" db:"bot_container_id"`
+	BotHost            *string         `json:"bot_host,omitempty" db:"bot_host"` // Docker host the bot runs on
+	RecordingSessionID *string         `json:"recording_session_id,omitempty" db:"recording_session_id"`
+	Status             MeetingStatus   `json:"status" db:"status"`
+	RecordingPath      *string         `json:"recording_path,omitempty" db:"recording_path"`
+	RecordingDuration  *int            `json:"recording_duration,omitempty" db:"recording_duration"` // seconds
+	RecordingURL       *string         `json:"recording_url,omitempty" db:"-"`                       // Computed
+	ErrorMessage       *string         `json:"error_message,omitempty" db:"error_message"`
+	StopReason         *string         `json:"stop_reason,omitempty" db:"stop_reason"` // Why the recording was stopped (by the user or bot-manager)
+	StopRequestedAt    *time.Time      `json:"stop_requested_at,omitempty" db:"stop_requested_at"` // When the bot was told to stop
+	SpawnAttempts      int             `json:"spawn_attempts,omitempty" db:"spawn_attempts"`
+	SpawnLastError     *string         `json:"spawn_last_error,omitempty" db:"spawn_last_error"`
+	ScheduledAt        *time.Time      `json:"scheduled_at,omitempty" db:"scheduled_at"`
+	MaxDuration        *int            `json:"max_duration_seconds,omitempty" db:"max_duration"` // seconds
+	OutputFormats      []string        `json:"output_formats,omitempty" db:"output_formats"`     // Renditions besides webm
+	CalendarFeedID     *int64          `json:"calendar_feed_id,omitempty" db:"calendar_feed_id"`
+	ICalUID            *string         `json:"ical_uid,omitempty" db:"ical_uid"` // Set for recordings imported from a calendar
+	Files              []RecordingFile `json:"files,omitempty" db:"-"`           // Finalized files with media metadata
+	StartedAt          *time.Time      `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt        *time.Time      `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt          time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// RecordingFile is a finalized recording file with its media metadata
+type RecordingFile struct {
+	ID              int64            `json:"id" db:"id"`
+	MeetingID       int64            `json:"meeting_id" db:"meeting_id"`
+	Path            string           `json:"path" db:"path"`     // Relative to the storage root
+	Format          string           `json:"format" db:"format"` // Container, e.g. "webm"
+	Codec           *string          `json:"codec,omitempty" db:"codec"`
+	SampleRate      *int             `json:"sample_rate,omitempty" db:"sample_rate"` // Hz
+	Channels        *int             `json:"channels,omitempty" db:"channels"`
+	Bitrate         *int64           `json:"bitrate,omitempty" db:"bitrate"` // bits per second
+	SizeBytes       int64            `json:"size_bytes" db:"size_bytes"`
+	DurationSeconds *float64         `json:"duration_seconds,omitempty" db:"duration_seconds"`
+	DroppedChunks   []int64          `json:"dropped_chunks,omitempty" db:"dropped_chunks"` // Chunk indices left out of the file
+	Processing      []ProcessingStep `json:"processing,omitempty" db:"processing"`         // Post-processing applied, in order
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+}
+
+// ProcessingStep is one post-processing filter applied to a recording.
+// Filter is the exact ffmpeg filter expression, so the result can be reproduced.
+type ProcessingStep struct {
+	Name   string                 `json:"name"` // trim_silence, noise_gate, mono, loudnorm
+	Filter string                 `json:"filter"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// MeetingEvent is an entry in a meeting's status history
+type MeetingEvent struct {
+	ID           int64         `json:"id" db:"id"`
+	MeetingID    int64         `json:"meeting_id" db:"meeting_id"`
+	Status       MeetingStatus `json:"status" db:"status"`
+	ContainerID  *string       `json:"container_id,omitempty" db:"container_id"`
+	ChunkCount   *int          `json:"chunk_count,omitempty" db:"chunk_count"`
+	ErrorMessage *string       `json:"error_message,omitempty" db:"error_message"`
+	OccurredAt   time.Time     `json:"occurred_at" db:"occurred_at"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
 }
 
 // CreateRecordingRequest is the request body for starting a recording
@@ -103,6 +203,21 @@ type CreateRecordingRequest struct {
 	Platform  Platform `json:"platform" validate:"required,oneof=google_meet teams"`
 	MeetingID string   `json:"meeting_id" validate:"required,min=3,max=255"`
 	BotName   string   `json:"bot_name,omitempty" validate:"omitempty,max=100"`
+
+	// Optional scheduling - the bot joins at ScheduledAt instead of now
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+
+	// Optional recording limit - defaults to the user's default_max_duration_seconds
+	MaxDuration *int `json:"max_duration_seconds,omitempty" validate:"omitempty,gte=60"` // seconds
+
+	// Optional extra renditions (mp3, m4a, wav, ogg) - defaults to the user's default_output_formats
+	OutputFormats []string `json:"output_formats,omitempty"`
+}
+
+// RescheduleRecordingRequest is the request body for moving a scheduled recording
+type RescheduleRecordingRequest struct {
+	ScheduledAt time.Time `json:"scheduled_at" validate:"required"`
+	MaxDuration *int      `json:"max_duration_seconds,omitempty" validate:"omitempty,gte=60"` // seconds
 }
 
 // UpdateMeetingStatusRequest is used internally to update meeting status
@@ -132,6 +247,18 @@ type SpawnBotResponse struct {
 	Status      string `json:"status"`
 }
 
+// BotContainerState describes the runtime state of a bot container
+type BotContainerState struct {
+	ContainerID string     `json:"container_id"`
+	Name        string     `json:"name"`
+	MeetingID   int64      `json:"meeting_id"` // From the newar.meeting_id label
+	Status      string     `json:"status"`     // created, running, paused, restarting, removing, exited, dead
+	Running     bool       `json:"running"`
+	ExitCode    int        `json:"exit_code"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
 // BotStatusUpdate is published by bots to Redis
 type BotStatusUpdate struct {
 	ContainerID  string        `json:"container_id"`
@@ -148,6 +275,38 @@ type BotCommand struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// FinalizeJobRequest is a queued finalization of a meeting's uploaded chunks
+type FinalizeJobRequest struct {
+	MeetingID   int64  `json:"meeting_id"`
+	ContainerID string `json:"container_id,omitempty"` // Bot session that uploaded the chunks
+	ChunkCount  int    `json:"chunk_count"`            // Chunks on disk when queued, scales the job timeout
+}
+
+// FinalizationJobStatus represents the state of a meeting's finalization
+type FinalizationJobStatus string
+
+const (
+	FinalizationQueued    FinalizationJobStatus = "queued"
+	FinalizationRunning   FinalizationJobStatus = "running"
+	FinalizationRetrying  FinalizationJobStatus = "retrying" // Last attempt failed, another is pending
+	FinalizationCompleted FinalizationJobStatus = "completed"
+	FinalizationFailed    FinalizationJobStatus = "failed" // Attempts exhausted
+)
+
+// FinalizationJob is the persisted state of a meeting's finalization
+type FinalizationJob struct {
+	MeetingID   int64                 `json:"meeting_id" db:"meeting_id"`
+	ContainerID *string               `json:"container_id,omitempty" db:"container_id"`
+	Status      FinalizationJobStatus `json:"status" db:"status"`
+	ChunkCount  int                   `json:"chunk_count" db:"chunk_count"`
+	Attempts    int                   `json:"attempts" db:"attempts"`
+	LastError   *string               `json:"last_error,omitempty" db:"last_error"`
+	QueuedAt    time.Time             `json:"queued_at" db:"queued_at"`
+	StartedAt   *time.Time            `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt  *time.Time            `json:"finished_at,omitempty" db:"finished_at"`
+	UpdatedAt   time.Time             `json:"updated_at" db:"updated_at"`
+}
+
 // =====================================================
 // PAGINATION TYPES
 // =====================================================
@@ -189,6 +348,30 @@ type HealthResponse struct {
 	Dependencies map[string]string `json:"dependencies,omitempty"`
 }
 
+// Recording stream event types
+const (
+	StreamEventStatus    = "status"    // Status or progress change
+	StreamEventHeartbeat = "heartbeat" // Keep-alive
+	StreamEventEnd       = "end"       // Terminal status, the stream closes after it
+)
+
+// RecordingStreamEvent is sent to clients watching a recording over SSE or WebSocket
+type RecordingStreamEvent struct {
+	Type         string        `json:"type"`
+	MeetingID    int64         `json:"meeting_id"`
+	Status       MeetingStatus `json:"status,omitempty"`
+	ChunkCount   int           `json:"chunk_count,omitempty"`
+	ErrorMessage *string       `json:"error_message,omitempty"`
+	RecordingURL *string       `json:"recording_url,omitempty"`
+	Timestamp    time.Time     `json:"timestamp"`
+}
+
+// StreamTokenResponse is returned when a stream token is issued
+type StreamTokenResponse struct {
+	Token     string    `json:"token"` // Pass as ?stream_token= when opening the stream
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 // =====================================================
 // STORAGE TYPES
 // =====================================================
@@ -209,12 +392,228 @@ type ChunkMetadata struct {
 	UploadedAt time.Time `json:"uploaded_at"`
 }
 
+// =====================================================
+// WEBHOOK TYPES
+// =====================================================
+
+// WebhookEventType is a recording lifecycle event delivered to webhooks
+type WebhookEventType string
+
+const (
+	WebhookEventRecordingJoining   WebhookEventType = "recording.joining"
+	WebhookEventRecordingStarted   WebhookEventType = "recording.started"
+	WebhookEventRecordingCompleted WebhookEventType = "recording.completed"
+	WebhookEventRecordingFailed    WebhookEventType = "recording.failed"
+)
+
+// WebhookDeliveryStatus represents the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookEndpoint is a user's webhook receiver
+type WebhookEndpoint struct {
+	ID          int64              `json:"id" db:"id"`
+	UserID      int64              `json:"user_id" db:"user_id"`
+	URL         string             `json:"url" db:"url"`
+	Secret      string             `json:"secret,omitempty" db:"secret"` // Only returned on creation
+	Events      []WebhookEventType `json:"events" db:"events"`
+	Description *string            `json:"description,omitempty" db:"description"`
+	IsActive    bool               `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDelivery is an entry in the webhook delivery log
+type WebhookDelivery struct {
+	ID             int64                 `json:"id" db:"id"`
+	EndpointID     int64                 `json:"endpoint_id" db:"endpoint_id"`
+	MeetingID      int64                 `json:"meeting_id" db:"meeting_id"`
+	EventType      WebhookEventType      `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	LastStatusCode *int                  `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      *string               `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" db:"next_attempt_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookPayload is the JSON body POSTed to webhook endpoints
+type WebhookPayload struct {
+	Type      WebhookEventType   `json:"type"`
+	CreatedAt time.Time          `json:"created_at"`
+	Data      WebhookPayloadData `json:"data"`
+}
+
+// WebhookPayloadData describes the recording an event is about
+type WebhookPayloadData struct {
+	MeetingID         int64         `json:"id"`
+	Platform          Platform      `json:"platform"`
+	NativeMeetingID   string        `json:"meeting_id"`
+	Status            MeetingStatus `json:"status"`
+	ErrorMessage      *string       `json:"error_message,omitempty"`
+	RecordingURL      *string       `json:"recording_url,omitempty"`
+	RecordingDuration *int          `json:"recording_duration,omitempty"`
+	StartedAt         *time.Time    `json:"started_at,omitempty"`
+	CompletedAt       *time.Time    `json:"completed_at,omitempty"`
+}
+
+// CreateWebhookRequest is the request body for registering a webhook endpoint
+type CreateWebhookRequest struct {
+	URL         string             `json:"url" validate:"required,url"`
+	Events      []WebhookEventType `json:"events,omitempty"` // Defaults to all events
+	Description *string            `json:"description,omitempty" validate:"omitempty,max=255"`
+}
+
+// UpdateWebhookRequest is the request body for updating a webhook endpoint
+type UpdateWebhookRequest struct {
+	URL         *string            `json:"url,omitempty"`
+	Events      []WebhookEventType `json:"events,omitempty"`
+	Description *string            `json:"description,omitempty"`
+	IsActive    *bool              `json:"is_active,omitempty"`
+}
+
+// =====================================================
+// MEETING EVENT BUS TYPES
+// =====================================================
+
+// MeetingEventVersion is the current envelope version. It is bumped on
+// breaking changes to the envelope or to an event's payload.
+const MeetingEventVersion = 1
+
+// MeetingEventType identifies an event published on the meeting:events channel
+type MeetingEventType string
+
+const (
+	EventMeetingCreated     MeetingEventType = "meeting.created"        // Recording requested via the gateway
+	EventBotSpawned         MeetingEventType = "meeting.bot_spawned"    // Bot container started
+	EventStatusChanged      MeetingEventType = "meeting.status_changed" // Status written to the database
+	EventRecordingFinalized MeetingEventType = "meeting.finalized"      // Chunks merged (or merging failed)
+)
+
+// MeetingEventEnvelope wraps every event published on the meeting:events channel.
+// Payload holds the event-specific struct for Type (see the *Payload types below).
+type MeetingEventEnvelope struct {
+	Type       MeetingEventType `json:"type"`
+	Version    int              `json:"version"`
+	MeetingID  int64            `json:"meeting_id"`
+	UserID     int64            `json:"user_id"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Payload    json.RawMessage  `json:"payload"`
+}
+
+// NewMeetingEventEnvelope builds an envelope for a meeting, encoding payload
+func NewMeetingEventEnvelope(eventType MeetingEventType, meetingID, userID int64, payload interface{}) (*MeetingEventEnvelope, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MeetingEventEnvelope{
+		Type:       eventType,
+		Version:    MeetingEventVersion,
+		MeetingID:  meetingID,
+		UserID:     userID,
+		OccurredAt: time.Now(),
+		Payload:    data,
+	}, nil
+}
+
+// DecodePayload decodes the envelope payload into v
+func (e *MeetingEventEnvelope) DecodePayload(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// MeetingCreatedPayload is the payload of meeting.created
+type MeetingCreatedPayload struct {
+	Platform        Platform `json:"platform"`
+	NativeMeetingID string   `json:"native_meeting_id"`
+	MeetingURL      string   `json:"meeting_url"`
+	BotName         *string  `json:"bot_name,omitempty"`
+}
+
+// BotSpawnedPayload is the payload of meeting.bot_spawned
+type BotSpawnedPayload struct {
+	ContainerID string `json:"container_id"`
+	SessionID   string `json:"session_id"`
+}
+
+// StatusChangedPayload is the payload of meeting.status_changed
+type StatusChangedPayload struct {
+	Status       MeetingStatus `json:"status"`
+	ErrorMessage *string       `json:"error_message,omitempty"`
+	ChunkCount   int           `json:"chunk_count,omitempty"`
+}
+
+// RecordingFinalizedPayload is the payload of meeting.finalized
+type RecordingFinalizedPayload struct {
+	Success       bool             `json:"success"`
+	RecordingPath *string          `json:"recording_path,omitempty"`
+	File          *RecordingFile   `json:"file,omitempty"`       // Media metadata of the finalized file
+	Renditions    []*RecordingFile `json:"renditions,omitempty"` // Extra output formats
+	Error         *string          `json:"error,omitempty"`
+}
+
+// =====================================================
+// CALENDAR IMPORT TYPES
+// =====================================================
+
+// CalendarFeed is an ICS feed URL synced into scheduled recordings
+type CalendarFeed struct {
+	ID            int64      `json:"id" db:"id"`
+	UserID        int64      `json:"user_id" db:"user_id"`
+	URL           string     `json:"url" db:"url"`
+	BotName       *string    `json:"bot_name,omitempty" db:"bot_name"`
+	MaxDuration   *int       `json:"max_duration_seconds,omitempty" db:"max_duration"` // seconds
+	IsActive      bool       `json:"is_active" db:"is_active"`
+	LastSyncedAt  *time.Time `json:"last_synced_at,omitempty" db:"last_synced_at"`
+	LastSyncError *string    `json:"last_sync_error,omitempty" db:"last_sync_error"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CalendarImportRequest is the request body for importing a calendar.
+// With Feed set, the URL is saved and re-synced periodically.
+type CalendarImportRequest struct {
+	URL         string  `json:"url"`
+	Feed        bool    `json:"feed,omitempty"`
+	BotName     *string `json:"bot_name,omitempty" validate:"omitempty,max=100"`
+	MaxDuration *int    `json:"max_duration_seconds,omitempty" validate:"omitempty,gte=60"` // seconds
+}
+
+// CalendarImportResult summarizes a calendar import or feed sync
+type CalendarImportResult struct {
+	Events      int                  `json:"events"`      // VEVENTs in the calendar
+	Occurrences int                  `json:"occurrences"` // Occurrences within the import horizon
+	Created     int                  `json:"created"`
+	Duplicates  int                  `json:"duplicates"` // Occurrences imported before
+	Cancelled   int                  `json:"cancelled"`  // Scheduled recordings no longer in the feed
+	Skipped     []CalendarImportSkip `json:"skipped"`
+	Meetings    []*Meeting           `json:"meetings"` // Recordings created by this import
+}
+
+// CalendarImportSkip is an occurrence that was not imported
+type CalendarImportSkip struct {
+	UID     string    `json:"uid"`
+	Summary string    `json:"summary,omitempty"`
+	Start   time.Time `json:"start"`
+	Reason  string    `json:"reason"`
+}
+
 // =====================================================
 // DATABASE FILTER & UPDATE TYPES
 // =====================================================
 
 // Status constants (string versions for database queries)
 const (
+	StatusScheduled  = "scheduled"
 	StatusRequested  = "requested"
 	StatusJoining    = "joining"
 	StatusActive     = "active"
@@ -226,6 +625,7 @@ const (
 
 // MeetingFilter for flexible database queries
 type MeetingFilter struct {
+	ID                 *int64
 	UserID             *int64
 	Platform           *string
 	MeetingID          *string
@@ -236,13 +636,14 @@ type MeetingFilter struct {
 
 // MeetingUpdate for flexible database updates
 type MeetingUpdate struct {
-	Status            *string
-	BotContainerID    *string
-	RecordingPath     *string
-	RecordingDuration *int
-	ErrorMessage      *string
-	StartedAt         *time.Time
-	CompletedAt       *time.Time
+	Status             *string
+	BotContainerID     *string
+	RecordingSessionID *string
+	RecordingPath      *string
+	RecordingDuration  *int
+	ErrorMessage       *string
+	StartedAt          *time.Time
+	CompletedAt        *time.Time
 }
 
 // Rows is a type alias for sql.Rows used in repository interfaces
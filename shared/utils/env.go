@@ -65,3 +65,23 @@ func GetEnvOrDefaultBool(key string, defaultValue bool) bool {
 		return defaultValue
 	}
 }
+
+// GetEnvOrDefaultFloat returns the value of an environment variable as a float,
+// or a default value if the variable is not set, empty, or cannot be parsed.
+//
+// Usage:
+//
+//	target := utils.GetEnvOrDefaultFloat("POSTPROCESS_LOUDNESS_TARGET", -16)
+func GetEnvOrDefaultFloat(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
@@ -0,0 +1,201 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/types"
+)
+
+// AllEvents lists every webhook event type (the default subscription)
+var AllEvents = []types.WebhookEventType{
+	types.WebhookEventRecordingJoining,
+	types.WebhookEventRecordingStarted,
+	types.WebhookEventRecordingCompleted,
+	types.WebhookEventRecordingFailed,
+}
+
+// EventForStatus maps a meeting status to the webhook event it triggers
+func EventForStatus(status types.MeetingStatus) (types.WebhookEventType, bool) {
+	switch status {
+	case types.MeetingStatusJoining:
+		return types.WebhookEventRecordingJoining, true
+	case types.MeetingStatusRecording:
+		return types.WebhookEventRecordingStarted, true
+	case types.MeetingStatusCompleted:
+		return types.WebhookEventRecordingCompleted, true
+	case types.MeetingStatusFailed:
+		return types.WebhookEventRecordingFailed, true
+	}
+	return "", false
+}
+
+// ValidateURL checks that a webhook URL is an absolute http(s) URL.
+// Deliveries to private or loopback addresses are refused when they are sent.
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("webhook URL must use http or https")
+	}
+	return nil
+}
+
+// ValidateEvents checks requested event types, defaulting to all events when empty
+func ValidateEvents(events []types.WebhookEventType) ([]types.WebhookEventType, error) {
+	if len(events) == 0 {
+		return AllEvents, nil
+	}
+
+	seen := map[types.WebhookEventType]bool{}
+	valid := []types.WebhookEventType{}
+	for _, event := range events {
+		known := false
+		for _, e := range AllEvents {
+			if e == event {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown webhook event: %s", event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			valid = append(valid, event)
+		}
+	}
+	return valid, nil
+}
+
+// GenerateSecret generates a signing secret for a webhook endpoint
+func GenerateSecret() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return constants.WebhookSecretPrefix + hex.EncodeToString(bytes), nil
+}
+
+// Sign computes the signature header value for a payload.
+// Receivers verify it by computing HMAC-SHA256 of "{timestamp}.{body}" with their secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Notifier queues webhook deliveries for recording status changes.
+// Deliveries are stored in the database and sent by the Worker, so events
+// survive restarts and each event is delivered at most once per endpoint.
+type Notifier struct {
+	webhookRepo *database.WebhookRepository
+	meetingRepo *database.MeetingRepository
+}
+
+// NewNotifier creates a new webhook notifier
+func NewNotifier(webhookRepo *database.WebhookRepository, meetingRepo *database.MeetingRepository) *Notifier {
+	return &Notifier{
+		webhookRepo: webhookRepo,
+		meetingRepo: meetingRepo,
+	}
+}
+
+// NotifyStatus queues deliveries of the event for a meeting's new status to the
+// owner's subscribed endpoints. Statuses without an event are ignored.
+func (n *Notifier) NotifyStatus(ctx context.Context, meetingID int64, status types.MeetingStatus) error {
+	event, ok := EventForStatus(status)
+	if !ok {
+		return nil
+	}
+
+	meeting, err := n.meetingRepo.GetByID(ctx, meetingID)
+	if err != nil {
+		return fmt.Errorf("failed to load meeting for webhook: %w", err)
+	}
+
+	endpoints, err := n.webhookRepo.ListActiveEndpointsForEvent(ctx, meeting.UserID, event)
+	if err != nil {
+		return err
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(buildPayload(event, status, meeting))
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	for _, endpoint := range endpoints {
+		if _, err := n.webhookRepo.CreateDelivery(ctx, endpoint.ID, meeting.ID, event, payload); err != nil {
+			return err
+		}
+	}
+
+	log.Debug().
+		Int64("meeting_id", meeting.ID).
+		Str("event", string(event)).
+		Int("endpoints", len(endpoints)).
+		Msg("Queued webhook deliveries")
+
+	return nil
+}
+
+// Notify queues deliveries and logs failures instead of returning them, for
+// callers where a webhook problem must not affect the status change itself
+func (n *Notifier) Notify(ctx context.Context, meetingID int64, status types.MeetingStatus) {
+	if n == nil {
+		return
+	}
+	if err := n.NotifyStatus(ctx, meetingID, status); err != nil {
+		log.Error().
+			Err(err).
+			Int64("meeting_id", meetingID).
+			Str("status", string(status)).
+			Msg("Failed to queue webhook deliveries")
+	}
+}
+
+// buildPayload builds the webhook body for a meeting event
+func buildPayload(event types.WebhookEventType, status types.MeetingStatus, meeting *types.Meeting) types.WebhookPayload {
+	data := types.WebhookPayloadData{
+		MeetingID:         meeting.ID,
+		Platform:          meeting.Platform,
+		NativeMeetingID:   meeting.MeetingID,
+		Status:            status,
+		ErrorMessage:      meeting.ErrorMessage,
+		RecordingDuration: meeting.RecordingDuration,
+		StartedAt:         meeting.StartedAt,
+		CompletedAt:       meeting.CompletedAt,
+	}
+
+	// Deliveries can be retried long after the payload is built, so webhooks link to
+	// the authenticated download endpoint rather than a signed URL that may expire
+	if status == types.MeetingStatusCompleted && meeting.RecordingPath != nil && *meeting.RecordingPath != "" {
+		recordingURL := fmt.Sprintf("/recordings/%s/%s/download", meeting.Platform, meeting.MeetingID)
+		data.RecordingURL = &recordingURL
+	}
+
+	return types.WebhookPayload{
+		Type:      event,
+		CreatedAt: time.Now(),
+		Data:      data,
+	}
+}
@@ -0,0 +1,108 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/types"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"recording.completed"}`)
+
+	// What a receiver computes from the delivery headers
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("whsec_test", 1700000000, body); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+	}{
+		{"other secret", "whsec_other", 1700000000, body},
+		{"other timestamp", "whsec_test", 1700000001, body},
+		{"other body", "whsec_test", 1700000000, []byte(`{"event":"recording.failed"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, tt.body); got == want {
+				t.Errorf("Sign() = %s, want a different signature", got)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, constants.WebhookInitialBackoff},
+		{1, constants.WebhookInitialBackoff},
+		{2, 2 * constants.WebhookInitialBackoff},
+		{3, 4 * constants.WebhookInitialBackoff},
+		{4, 8 * constants.WebhookInitialBackoff},
+		{7, 64 * constants.WebhookInitialBackoff},
+		{8, constants.WebhookMaxBackoff},
+		{100, constants.WebhookMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestValidateEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []types.WebhookEventType
+		want    []types.WebhookEventType
+		wantErr bool
+	}{
+		{
+			name: "empty defaults to all events",
+			want: AllEvents,
+		},
+		{
+			name:   "subset",
+			events: []types.WebhookEventType{types.WebhookEventRecordingCompleted, types.WebhookEventRecordingFailed},
+			want:   []types.WebhookEventType{types.WebhookEventRecordingCompleted, types.WebhookEventRecordingFailed},
+		},
+		{
+			name:   "duplicates dropped in order",
+			events: []types.WebhookEventType{types.WebhookEventRecordingFailed, types.WebhookEventRecordingJoining, types.WebhookEventRecordingFailed},
+			want:   []types.WebhookEventType{types.WebhookEventRecordingFailed, types.WebhookEventRecordingJoining},
+		},
+		{
+			name:    "unknown event",
+			events:  []types.WebhookEventType{types.WebhookEventRecordingCompleted, "recording.deleted"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateEvents(tt.events)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateEvents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
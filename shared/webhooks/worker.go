@@ -0,0 +1,177 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/newar/insights/shared/constants"
+	"github.com/newar/insights/shared/database"
+	"github.com/newar/insights/shared/metrics"
+	"github.com/newar/insights/shared/safehttp"
+)
+
+// Worker sends queued webhook deliveries. Failed deliveries are retried with
+// exponential backoff until WebhookMaxAttempts is reached.
+type Worker struct {
+	webhookRepo *database.WebhookRepository
+	client      *http.Client
+	metrics     *metrics.Collector
+	interval    time.Duration
+	batchSize   int
+}
+
+// NewWorker creates a new webhook delivery worker
+func NewWorker(webhookRepo *database.WebhookRepository, collector *metrics.Collector) *Worker {
+	return &Worker{
+		webhookRepo: webhookRepo,
+		client:      safehttp.NewClient(constants.WebhookRequestTimeout),
+		metrics:     collector,
+		interval:    constants.WebhookPollInterval,
+		batchSize:   constants.WebhookBatchSize,
+	}
+}
+
+// Run delivers due webhooks on every interval until ctx is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	log.Info().Dur("interval", w.interval).Msg("Webhook worker started")
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Webhook worker stopped")
+			return
+		case <-ticker.C:
+			if err := w.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("Webhook delivery pass failed")
+			}
+		}
+	}
+}
+
+// DeliverDue claims and sends a batch of due deliveries
+func (w *Worker) DeliverDue(ctx context.Context) error {
+	deliveries, err := w.webhookRepo.ClaimDueDeliveries(ctx, w.batchSize, constants.WebhookDeliveryLease)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(d *database.DueWebhookDelivery) {
+			defer wg.Done()
+			w.deliver(ctx, d)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// deliver sends a single delivery and records the outcome
+func (w *Worker) deliver(ctx context.Context, delivery *database.DueWebhookDelivery) {
+	logger := log.With().
+		Int64("delivery_id", delivery.ID).
+		Int64("endpoint_id", delivery.EndpointID).
+		Int64("meeting_id", delivery.MeetingID).
+		Str("event", string(delivery.EventType)).
+		Int("attempt", delivery.Attempts).
+		Logger()
+
+	statusCode, err := w.send(ctx, delivery)
+
+	// Record the result even if ctx was cancelled mid-request
+	recordCtx, cancel := context.WithTimeout(context.Background(), constants.DefaultQueryTimeout)
+	defer cancel()
+
+	if err == nil {
+		if err := w.webhookRepo.MarkDeliverySucceeded(recordCtx, delivery.ID, statusCode); err != nil {
+			logger.Error().Err(err).Msg("Failed to record webhook delivery")
+		}
+		logger.Info().Int("status_code", statusCode).Msg("Webhook delivered")
+		w.count("succeeded")
+		return
+	}
+
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+
+	var retryAt *time.Time
+	if delivery.Attempts < constants.WebhookMaxAttempts {
+		next := time.Now().Add(Backoff(delivery.Attempts))
+		retryAt = &next
+	}
+
+	if err := w.webhookRepo.MarkDeliveryFailed(recordCtx, delivery.ID, code, err.Error(), retryAt); err != nil {
+		logger.Error().Err(err).Msg("Failed to record webhook delivery")
+	}
+
+	if retryAt == nil {
+		logger.Error().Err(err).Msg("Webhook delivery failed - giving up")
+		w.count("failed")
+		return
+	}
+	logger.Warn().Err(err).Time("retry_at", *retryAt).Msg("Webhook delivery failed - will retry")
+	w.count("retry")
+}
+
+// send POSTs the signed payload. Any non-2xx response is an error.
+func (w *Worker) send(ctx context.Context, delivery *database.DueWebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Newar-Insights-Webhooks/1.0")
+	req.Header.Set(constants.WebhookEventHeader, string(delivery.EventType))
+	req.Header.Set(constants.WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(constants.WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(constants.WebhookSignatureHeader, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Backoff returns the delay before retrying after the given number of attempts
+func Backoff(attempts int) time.Duration {
+	delay := constants.WebhookInitialBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= constants.WebhookMaxBackoff {
+			return constants.WebhookMaxBackoff
+		}
+	}
+	return delay
+}
+
+// count increments the webhook delivery metric for the given outcome
+func (w *Worker) count(outcome string) {
+	if w.metrics != nil {
+		w.metrics.IncrementCounter("webhook_deliveries_total", map[string]string{"outcome": outcome})
+	}
+}